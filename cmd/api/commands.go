@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"example.com/notes-api/internal/backup"
+	"example.com/notes-api/internal/core"
+	"example.com/notes-api/internal/mcp"
+	"example.com/notes-api/internal/repo"
+	"example.com/notes-api/internal/retry"
+)
+
+// reencryptBatch — сколько заметок перешифровывается за одну транзакцию.
+const reencryptBatch = 200
+
+// compressBatch — сколько заметок сжимается за одну транзакцию.
+const compressBatch = 200
+
+// langBatch — для скольких заметок язык определяется за одну транзакцию.
+const langBatch = 500
+
+// reindexRetry — повтор перестройки индекса, если не удалось взять блокировку
+// или сервер временно недоступен.
+var reindexRetry = retry.Policy{
+	Name:      "reindex",
+	Attempts:  3,
+	Base:      time.Second,
+	Max:       30 * time.Second,
+	Retryable: repo.Transient,
+}
+
+// restoreBatch — сколько заметок загружается одним COPY при restore.
+const restoreBatch = 1000
+
+// runCommand выполняет подкоманду CLI: go run ./cmd/api <command> [flags].
+func runCommand(args []string, noteRepo *repo.NoteRepoPG) {
+	switch args[0] {
+	case "reencrypt":
+		runReencrypt(noteRepo)
+	case "restore":
+		runRestore(args[1:], noteRepo)
+	case "reindex":
+		runReindex(noteRepo)
+	case "compress":
+		runCompress(noteRepo)
+	case "mcp":
+		runMCP(noteRepo)
+	default:
+		log.Fatalf("Unknown command %q (available: reencrypt, restore, reindex, compress, mcp)", args[0])
+	}
+}
+
+// runReencrypt перешифровывает content всех заметок текущим ключом
+// (открытый текст тоже шифруется) — нужна после ротации ключа.
+func runReencrypt(noteRepo *repo.NoteRepoPG) {
+	ctx := context.Background()
+
+	total := 0
+	for {
+		n, err := noteRepo.ReencryptBatch(ctx, reencryptBatch)
+		if err != nil {
+			log.Fatal("Re-encryption failed:", err)
+		}
+		total += n
+		if n > 0 {
+			log.Printf("Re-encrypted %d notes", total)
+		}
+		if n < reencryptBatch {
+			break
+		}
+	}
+	log.Printf("Re-encryption finished, %d notes updated", total)
+}
+
+// runCompress сжимает content заметок, записанных до включения сжатия
+// (CONTENT_COMPRESS_THRESHOLD), — нужна один раз после его включения.
+func runCompress(noteRepo *repo.NoteRepoPG) {
+	ctx := context.Background()
+
+	var (
+		cursor              int64
+		scanned, compressed int
+	)
+	for {
+		last, n, c, err := noteRepo.CompressBatch(ctx, cursor, compressBatch)
+		if err != nil {
+			log.Fatal("Compression failed:", err)
+		}
+		cursor, scanned, compressed = last, scanned+n, compressed+c
+		if c > 0 {
+			log.Printf("Compressed %d notes", compressed)
+		}
+		if n < compressBatch {
+			break
+		}
+	}
+	log.Printf("Compression finished, %d of %d candidate notes compressed", compressed, scanned)
+}
+
+// runMCP запускает MCP-сервер на stdin/stdout для ассистента, который
+// запускает API как подпроцесс. Права — MCP_SCOPES ("read" по умолчанию,
+// "read,write" разрешает создавать заметки). Логи идут в stderr.
+func runMCP(noteRepo *repo.NoteRepoPG) {
+	scopes := []string{mcp.ScopeRead}
+	if s := os.Getenv("MCP_SCOPES"); s != "" {
+		scopes = strings.Split(s, ",")
+	}
+	server := mcp.NewServer(noteRepo, nil)
+	server.Limits = limitsFromEnv()
+	if err := server.ServeStdio(context.Background(), scopes, os.Stdin, os.Stdout); err != nil {
+		log.Fatal("MCP server failed:", err)
+	}
+}
+
+// runReindex определяет язык заметок, у которых он ещё не записан (созданных
+// до колонки lang), и перестраивает поисковые индексы по одному — после
+// смены конфигурации поиска или при подозрении на повреждение индекса.
+func runReindex(noteRepo *repo.NoteRepoPG) {
+	ctx := context.Background()
+
+	var (
+		cursor            int64
+		scanned, detected int
+	)
+	for {
+		last, n, d, err := noteRepo.DetectLangBatch(ctx, cursor, langBatch)
+		if err != nil {
+			log.Fatal("Language detection failed:", err)
+		}
+		cursor, scanned, detected = last, scanned+n, detected+d
+		if n < langBatch {
+			break
+		}
+	}
+	log.Printf("Detected language for %d of %d notes without one", detected, scanned)
+
+	for i, index := range repo.SearchIndexes {
+		started := time.Now()
+		err := reindexRetry.Do(ctx, func(ctx context.Context) error {
+			return noteRepo.ReindexSearch(ctx, index)
+		})
+		if err != nil {
+			log.Fatalf("Failed to reindex %s: %v", index, err)
+		}
+		log.Printf("Reindexed %s (%d/%d) in %s",
+			index, i+1, len(repo.SearchIndexes), time.Since(started).Round(time.Millisecond))
+	}
+	log.Println("Reindex finished")
+}
+
+// runRestore загружает заметки из файла резервной копии:
+//
+//	restore [-strategy merge|wipe] [-yes] <file>
+//
+// merge (по умолчанию) добавляет заметки, ID и slug которых ещё свободны;
+// wipe сначала удаляет все заметки и требует -yes. После загрузки число и
+// контрольная сумма восстановленных заметок сверяются с БД.
+func runRestore(args []string, noteRepo *repo.NoteRepoPG) {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	strategy := fs.String("strategy", "merge", "merge or wipe")
+	yes := fs.Bool("yes", false, "confirm -strategy wipe")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		log.Fatal("Usage: restore [-strategy merge|wipe] [-yes] <file>")
+	}
+	switch *strategy {
+	case "merge":
+	case "wipe":
+		if !*yes {
+			log.Fatal("-strategy wipe deletes all notes; pass -yes to confirm")
+		}
+	default:
+		log.Fatalf("Unknown strategy %q (available: merge, wipe)", *strategy)
+	}
+
+	ctx := context.Background()
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		log.Fatal("Failed to open backup:", err)
+	}
+	defer f.Close()
+
+	if *strategy == "wipe" {
+		if err := noteRepo.WipeNotes(ctx); err != nil {
+			log.Fatal("Failed to wipe notes:", err)
+		}
+		log.Println("All notes deleted")
+	}
+
+	var (
+		restored backup.Digest
+		ids      = map[int64]bool{}
+		skipped  int
+		batch    []core.Note
+	)
+	// Заметки грузятся пачками через COPY; пропущенные — те, чьи ID или
+	// slug уже заняты
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		inserted, err := noteRepo.RestoreBatch(ctx, batch)
+		if err != nil {
+			return err
+		}
+		for _, id := range inserted {
+			ids[id] = true
+		}
+		for _, n := range batch {
+			if ids[n.ID] {
+				restored.Add(n)
+			}
+		}
+		skipped += len(batch) - len(inserted)
+		batch = batch[:0]
+		log.Printf("Restored %d notes, skipped %d", restored.Count, skipped)
+		return nil
+	}
+	header, err := backup.Read(f, func(n core.Note) error {
+		batch = append(batch, n)
+		if len(batch) < restoreBatch {
+			return nil
+		}
+		return flush()
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		log.Fatal("Restore failed:", err)
+	}
+	if err := noteRepo.ResetNoteSequence(ctx); err != nil {
+		log.Fatal("Failed to reset note ID sequence:", err)
+	}
+	log.Printf("Restored %d notes from backup of %s, skipped %d existing",
+		restored.Count, header.CreatedAt.Format("2006-01-02 15:04:05"), skipped)
+
+	// Проверка: перечитываем восстановленные заметки из БД и сверяем сумму
+	var stored backup.Digest
+	var before int64
+	for {
+		notes, err := noteRepo.ListAllNotes(ctx, before, 500)
+		if err != nil {
+			log.Fatal("Verification failed:", err)
+		}
+		for _, n := range notes {
+			if ids[n.ID] {
+				stored.Add(n)
+			}
+		}
+		if len(notes) < 500 {
+			break
+		}
+		before = notes[len(notes)-1].ID
+	}
+	if stored.String() != restored.String() {
+		log.Fatalf("Verification failed: backup %s, database %s", restored, stored)
+	}
+	log.Printf("Verification passed: %s", stored)
+}
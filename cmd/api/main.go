@@ -6,6 +6,12 @@
 // @contact.name    Backend Course
 // @contact.email   example@university.ru
 // @BasePath        /api/v1
+//
+// @securityDefinitions.apikey  AdminToken
+// @in                          header
+// @name                        Authorization
+//
+// @securityDefinitions.basic  BasicAuth
 package main
 
 import (
@@ -13,16 +19,36 @@ import (
 	"database/sql"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
+	_ "time/tzdata" // часовые пояса для ?tz= без системной базы zoneinfo
 
 	"github.com/joho/godotenv"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	httpSwagger "github.com/swaggo/http-swagger"
 
+	"example.com/notes-api/internal/auth"
+	"example.com/notes-api/internal/backup"
+	"example.com/notes-api/internal/changes"
+	"example.com/notes-api/internal/core"
+	"example.com/notes-api/internal/dbtime"
+	"example.com/notes-api/internal/dedupe"
+	"example.com/notes-api/internal/encryption"
+	"example.com/notes-api/internal/health"
 	httpx "example.com/notes-api/internal/http"
 	"example.com/notes-api/internal/http/handlers"
+	"example.com/notes-api/internal/ingest"
+	"example.com/notes-api/internal/jobs"
+	"example.com/notes-api/internal/logx"
+	"example.com/notes-api/internal/mcp"
+	"example.com/notes-api/internal/proofread"
 	"example.com/notes-api/internal/repo"
+	"example.com/notes-api/internal/retention"
+	"example.com/notes-api/internal/translate"
+	"example.com/notes-api/internal/views"
 )
 
 func main() {
@@ -31,19 +57,215 @@ func main() {
 		log.Println("No .env file found, using environment variables")
 	}
 
+	// Хранилище заметок: STORAGE=postgres (по умолчанию) или memory — без БД,
+	// данные живут до перезапуска (для разработки)
+	var (
+		noteRepo     noteStore
+		pgRepo       *repo.NoteRepoPG
+		userRepo     core.UserRepository
+		healthChecks []health.Check
+	)
+	switch storage := envString("STORAGE", "postgres"); storage {
+	case "postgres":
+		db := openDB()
+		defer db.Close()
+		pgRepo = newNoteRepoPG(db)
+		noteRepo = pgRepo
+		userRepo = repo.NewUserRepoPG(db)
+		healthChecks = append(healthChecks, health.Check{Name: "db", Ping: db.PingContext})
+	case "memory":
+		noteRepo = repo.NewNoteRepoMemory()
+		userRepo = repo.NewUserRepoMemory()
+		log.Println("Using in-memory storage, notes are lost on restart")
+	default:
+		log.Fatalf("Unknown STORAGE %q (available: postgres, memory)", storage)
+	}
+
+	// Подкоманды CLI вместо запуска сервера
+	if len(os.Args) > 1 {
+		if pgRepo == nil {
+			log.Fatal("CLI commands require STORAGE=postgres")
+		}
+		runCommand(os.Args[1:], pgRepo)
+		return
+	}
+
+	// Учёт просмотров: повторы от одного клиента в пределах окна не считаются
+	viewRecorder := views.NewRecorder(noteRepo, envDuration("VIEW_DEDUPE_WINDOW", 30*time.Minute))
+	go viewRecorder.Run(context.Background())
+
+	// Фоновое удаление заметок с истёкшим сроком жизни
+	purgeInterval := envDuration("EXPIRY_PURGE_INTERVAL", time.Minute)
+	go jobs.Every(context.Background(), "purge-expired", purgeInterval, jobs.PurgeExpired(noteRepo))
+
+	// Секции notes_log на будущие месяцы, если таблица секционирована
+	if pgRepo != nil {
+		go jobs.Every(context.Background(), "log-partitions", 24*time.Hour, jobs.LogPartitions(pgRepo))
+	}
+
+	// Дедупликация повторных POST /notes (0 — выключена)
+	var createDedupe *dedupe.Window
+	if window := envDuration("CREATE_DEDUPE_WINDOW", 0); window > 0 {
+		createDedupe = dedupe.NewWindow(window)
+	}
+
+	// Правила хранения данных, например "archive_notes:8760h,purge_log:2160h,trim_log:1000000"
+	retentionRules, err := retention.ParseRules(os.Getenv("RETENTION_RULES"))
+	if err != nil {
+		log.Fatal("Invalid RETENTION_RULES:", err)
+	}
+	var retentionEngine *retention.Engine
+	if pgRepo != nil {
+		retentionEngine = retention.NewEngine(pgRepo, retentionRules)
+		if len(retentionRules) > 0 {
+			go jobs.Every(context.Background(), "retention", envDuration("RETENTION_INTERVAL", time.Hour), retentionEngine.Run)
+		}
+	} else if len(retentionRules) > 0 {
+		log.Fatal("RETENTION_RULES require STORAGE=postgres")
+	}
+
+	// Резервные копии в BACKUP_DIR (пусто — выключены)
+	var backups *backup.Runner
+	if dir := os.Getenv("BACKUP_DIR"); dir != "" {
+		backups = backup.NewRunner(noteRepo, dir, envInt("BACKUP_KEEP", 7))
+		go jobs.Every(context.Background(), "backup", envDuration("BACKUP_INTERVAL", 24*time.Hour), backups.Run)
+	}
+
+	// Проверка правописания сервером LanguageTool (пустой LANGUAGETOOL_URL — выключена)
+	var proofreader proofread.Checker
+	if url := os.Getenv("LANGUAGETOOL_URL"); url != "" {
+		proofreader = proofread.NewLanguageTool(url)
+	}
+
+	// Перевод заметок сервером LibreTranslate (пустой LIBRETRANSLATE_URL — выключен)
+	var translator translate.Translator
+	if url := os.Getenv("LIBRETRANSLATE_URL"); url != "" {
+		translator = translate.NewLibreTranslate(url, os.Getenv("LIBRETRANSLATE_API_KEY"))
+	}
+
+	changeFeed := changes.NewFeed(1000)
+	limits := limitsFromEnv()
+
+	// Заметки из NATS (пустой NATS_URL — выключено)
+	if url := os.Getenv("NATS_URL"); url != "" {
+		consumer, err := ingest.Connect(ingest.Config{
+			URL:        url,
+			Subject:    envString("NATS_SUBJECT", "notes.create"),
+			Queue:      envString("NATS_QUEUE", "notes-api"),
+			DeadLetter: os.Getenv("NATS_DEAD_LETTER_SUBJECT"),
+			Limits:     limits,
+		}, noteRepo, changeFeed)
+		if err != nil {
+			log.Fatal("Failed to connect to NATS:", err)
+		}
+		go consumer.Run(context.Background())
+		healthChecks = append(healthChecks, health.Check{Name: "broker", Ping: consumer.Ping})
+	}
+
+	// Вход по токену: JWT_SECRET (от 32 байт) включает /auth/login и закрывает
+	// заметки для анонимных запросов
+	var tokens *auth.JWT
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		var err error
+		tokens, err = auth.NewJWT([]byte(secret), envDuration("JWT_TTL", 24*time.Hour))
+		if err != nil {
+			log.Fatal("Invalid JWT_SECRET or JWT_TTL:", err)
+		}
+	}
+
+	// HTTP handlers и роутер
+	h := &handlers.Handler{
+		Repo:    noteRepo,
+		Users:   userRepo,
+		Changes: changeFeed,
+		Views:   viewRecorder,
+		Dedupe:  createDedupe,
+		// Как для админского токена: после 5 неверных паролей подряд
+		// блокировка от 1 с, удваивается до 15 минут
+		LoginLockout: auth.NewLockout(5, time.Second, 15*time.Minute),
+		Tokens:       tokens,
+
+		Retention:     retentionEngine,
+		Backups:       backups,
+		Proofreader:   proofreader,
+		Translator:    translator,
+		DailyTemplate: os.Getenv("DAILY_NOTE_TEMPLATE"),
+		Limits:        limits,
+	}
+	// MCP для LLM-ассистентов по HTTP: MCP_API_KEYS="key:read+write,..." (пусто — выключен)
+	var mcpHandler http.Handler
+	if spec := os.Getenv("MCP_API_KEYS"); spec != "" {
+		keys, err := mcp.ParseKeys(spec)
+		if err != nil {
+			log.Fatal("Invalid MCP_API_KEYS:", err)
+		}
+		mcpServer := mcp.NewServer(noteRepo, changeFeed)
+		mcpServer.Limits = limits
+		mcpHandler = mcpServer.HTTPHandler(keys)
+	}
+
+	logAllowlist := logx.DefaultQueryAllowlist
+	if s := os.Getenv("LOG_QUERY_ALLOWLIST"); s != "" {
+		logAllowlist = strings.Split(s, ",")
+	}
+	r := httpx.NewRouter(h, httpx.Config{
+		LogQueryAllowlist: logAllowlist,
+		// Под большой нагрузкой: ACCESS_LOG_SAMPLE_RATE=0.1 пишет каждый десятый
+		// запрос, кроме ошибок и медленных
+		AccessLogSampleRate: envFloat("ACCESS_LOG_SAMPLE_RATE", 0),
+		AccessLogSlow:       envDuration("ACCESS_LOG_SLOW", time.Second),
+		AdminToken:          os.Getenv("ADMIN_TOKEN"),
+		// После 5 неверных токенов подряд: блокировка от 1 с, удваивается до 15 минут
+		AdminLockout: auth.NewLockout(5, time.Second, 15*time.Minute),
+		Signer:       signerFromEnv(),
+		JWT:          tokens,
+		// Публикация заметок: запись только с ADMIN_TOKEN или подписью
+		ReadOnly: envBool("READ_ONLY", false),
+
+		MaxDecompressedBody: int64(envInt("MAX_DECOMPRESSED_BODY", 10<<20)),
+		MCP:                 mcpHandler,
+		Health:              healthChecks,
+	})
+
+	// Swagger UI
+	r.Get("/docs/*", httpSwagger.WrapHandler)
+	r.Get("/docs/doc.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		http.ServeFile(w, r, "./docs/swagger.json")
+	})
+
+	// Запуск сервера
+	log.Println("Server started at :8080")
+	if err := http.ListenAndServe(":8080", r); err != nil {
+		log.Fatal("Server failed:", err)
+	}
+}
+
+// noteStore — то, что сервер требует от хранилища заметок.
+type noteStore interface {
+	handlers.Repository
+	views.Store
+	jobs.ExpiredPurger
+}
+
+// openDB подключается к PostgreSQL по DATABASE_URL и проверяет соединение.
+func openDB() *sql.DB {
 	dsn := os.Getenv("DATABASE_URL")
 	if dsn == "" {
 		log.Fatal("DATABASE_URL is not set")
 	}
 
-	log.Println("Connecting to DB:", dsn)
+	// Время в ответах всегда в UTC, независимо от TimeZone сервера БД
+	dsn = withUTC(dsn)
+
+	log.Println("Connecting to DB:", logx.RedactDSN(dsn))
 
-	// Подключение к PostgreSQL
-	db, err := sql.Open("postgres", dsn)
+	// Подключение к PostgreSQL; время запросов учитывается в журнале доступа
+	connector, err := pq.NewConnector(dsn)
 	if err != nil {
 		log.Fatal("Failed to open DB:", err)
 	}
-	defer db.Close()
+	db := sql.OpenDB(dbtime.Wrap(connector))
 
 	db.SetMaxOpenConns(40) // максимум открытых соединений
 	db.SetMaxIdleConns(25) // максимум соединений в простое
@@ -57,24 +279,149 @@ func main() {
 	}
 
 	log.Println("Connected to DB successfully")
+	return db
+}
 
-	// Инициализация репозитория PostgreSQL
-	noteRepo := repo.NewNoteRepoPG(db)
+// newNoteRepoPG создаёт репозиторий PostgreSQL с шифрованием и сжатием content
+// из переменных окружения.
+func newNoteRepoPG(db *sql.DB) *repo.NoteRepoPG {
+	var repoOpts []repo.Option
+	if keyring := keyringFromEnv(); keyring != nil {
+		repoOpts = append(repoOpts, repo.WithKeyring(keyring))
+		log.Println("Note content encryption enabled, current key:", keyring.CurrentKeyID())
+	}
+	// Сжатие content длиннее порога в байтах (0 — выключено)
+	if threshold := envInt("CONTENT_COMPRESS_THRESHOLD", 0); threshold > 0 {
+		repoOpts = append(repoOpts, repo.WithCompression(threshold))
+		log.Println("Note content compression enabled above", threshold, "bytes")
+	}
+	return repo.NewNoteRepoPG(db, repoOpts...)
+}
 
-	// HTTP handlers и роутер
-	h := &handlers.Handler{Repo: noteRepo}
-	r := httpx.NewRouter(h)
+// envDuration читает длительность из переменной окружения (например, "30s").
+func envDuration(name string, def time.Duration) time.Duration {
+	s := os.Getenv(name)
+	if s == "" {
+		return def
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", name, err)
+	}
+	return d
+}
 
-	// Swagger UI
-	r.Get("/docs/*", httpSwagger.WrapHandler)
-	r.Get("/docs/doc.json", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		http.ServeFile(w, r, "./docs/swagger.json")
-	})
+// envString читает строку из переменной окружения name или возвращает def.
+func envString(name, def string) string {
+	if s := os.Getenv(name); s != "" {
+		return s
+	}
+	return def
+}
 
-	// Запуск сервера
-	log.Println("Server started at :8080")
-	if err := http.ListenAndServe(":8080", r); err != nil {
-		log.Fatal("Server failed:", err)
+// envBool читает логическое значение ("true", "1", ...) из переменной окружения name.
+func envBool(name string, def bool) bool {
+	s := os.Getenv(name)
+	if s == "" {
+		return def
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", name, err)
+	}
+	return v
+}
+
+// envFloat читает число из переменной окружения name или возвращает def.
+func envFloat(name string, def float64) float64 {
+	s := os.Getenv(name)
+	if s == "" {
+		return def
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", name, err)
+	}
+	return v
+}
+
+// envInt читает целое из переменной окружения name или возвращает def.
+func envInt(name string, def int) int {
+	s := os.Getenv(name)
+	if s == "" {
+		return def
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", name, err)
+	}
+	return v
+}
+
+// limitsFromEnv читает предельные размеры заметки: NOTE_MAX_TITLE (символов,
+// по умолчанию 500) и NOTE_MAX_CONTENT (байт, по умолчанию 1 МиБ).
+func limitsFromEnv() core.Limits {
+	limits := core.Limits{
+		MaxTitle:   envInt("NOTE_MAX_TITLE", 0),
+		MaxContent: envInt("NOTE_MAX_CONTENT", 0),
+	}.OrDefault()
+	if err := limits.Validate(); err != nil {
+		log.Fatal("Invalid note limits:", err)
+	}
+	return limits
+}
+
+// keyringFromEnv собирает ключи шифрования content из NOTES_ENCRYPTION_KEYS
+// ("id:base64,...") и NOTES_ENCRYPTION_KEY_ID. Без ключей шифрование выключено.
+func keyringFromEnv() *encryption.Keyring {
+	spec := os.Getenv("NOTES_ENCRYPTION_KEYS")
+	if spec == "" {
+		return nil
+	}
+
+	keys, err := encryption.ParseKeys(spec)
+	if err != nil {
+		log.Fatal("Invalid NOTES_ENCRYPTION_KEYS:", err)
+	}
+	keyring, err := encryption.NewKeyring(keys, os.Getenv("NOTES_ENCRYPTION_KEY_ID"))
+	if err != nil {
+		log.Fatal("Invalid encryption config:", err)
+	}
+	return keyring
+}
+
+// signerFromEnv собирает ключи подписи запросов из HMAC_KEYS ("id:base64,...").
+// Допустимое расхождение часов — HMAC_MAX_SKEW. Без ключей подписи не принимаются.
+func signerFromEnv() *auth.Signer {
+	spec := os.Getenv("HMAC_KEYS")
+	if spec == "" {
+		return nil
+	}
+
+	keys, err := encryption.ParseKeys(spec)
+	if err != nil {
+		log.Fatal("Invalid HMAC_KEYS:", err)
+	}
+	return auth.NewSigner(keys, envDuration("HMAC_MAX_SKEW", 5*time.Minute))
+}
+
+// withUTC добавляет в DSN параметр сессии timezone=UTC, если он не задан явно.
+// Поддерживает URL ("postgres://...") и формат "key=value".
+func withUTC(dsn string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return dsn
+		}
+		q := u.Query()
+		if q.Get("timezone") == "" {
+			q.Set("timezone", "UTC")
+			u.RawQuery = q.Encode()
+		}
+		return u.String()
+	}
+	if strings.Contains(dsn, "timezone=") {
+		return dsn
 	}
+	return dsn + " timezone=UTC"
 }
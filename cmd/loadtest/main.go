@@ -0,0 +1,229 @@
+// Command loadtest — нагрузочный генератор для Notes API.
+//
+//	go run ./cmd/loadtest -url http://localhost:8080/api/v1 -c 16 -d 30s
+//
+// Каждый воркер в цикле создаёт заметку, читает её, запрашивает список,
+// ищет по metadata, обновляет и удаляет. В конце печатаются число запросов,
+// ошибки и перцентили задержек по каждой операции.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// operations — порядок операций в одном цикле воркера (и в отчёте).
+var operations = []string{"create", "get", "list", "search", "patch", "delete"}
+
+// stats — результаты одной операции.
+type stats struct {
+	latencies []time.Duration
+	errors    int
+}
+
+// worker гоняет цикл операций до отмены ctx и копит свои результаты.
+type worker struct {
+	client *http.Client
+	base   string
+	runID  string
+	id     int
+	seq    int
+	stats  map[string]*stats
+}
+
+func main() {
+	base := flag.String("url", "http://localhost:8080/api/v1", "API base URL")
+	concurrency := flag.Int("c", 8, "number of concurrent workers")
+	duration := flag.Duration("d", 30*time.Second, "test duration")
+	timeout := flag.Duration("timeout", 5*time.Second, "per-request timeout")
+	flag.Parse()
+
+	if *concurrency < 1 {
+		log.Fatal("-c must be at least 1")
+	}
+
+	// Метка прогона: по ней ищем свои заметки и отличаем их от чужих
+	runID := strconv.FormatInt(time.Now().UnixNano(), 36)
+	client := &http.Client{
+		Timeout: *timeout,
+		Transport: &http.Transport{
+			MaxIdleConns:        *concurrency,
+			MaxIdleConnsPerHost: *concurrency,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *duration)
+	defer cancel()
+
+	log.Printf("Load test %s: %d workers for %s against %s", runID, *concurrency, *duration, *base)
+
+	workers := make([]*worker, *concurrency)
+	var wg sync.WaitGroup
+	started := time.Now()
+	for i := range workers {
+		workers[i] = &worker{
+			client: client,
+			base:   *base,
+			runID:  runID,
+			id:     i,
+			stats:  map[string]*stats{},
+		}
+		wg.Add(1)
+		go func(w *worker) {
+			defer wg.Done()
+			w.run(ctx)
+		}(workers[i])
+	}
+	wg.Wait()
+	elapsed := time.Since(started)
+
+	report(os.Stdout, workers, elapsed)
+}
+
+/*
+====================
+WORKER
+====================
+*/
+
+func (w *worker) run(ctx context.Context) {
+	for ctx.Err() == nil {
+		w.seq++
+		id, ok := w.create(ctx)
+		if !ok {
+			continue
+		}
+		path := "/notes/" + strconv.FormatInt(id, 10)
+
+		w.do(ctx, "get", http.MethodGet, path, nil, http.StatusOK, nil)
+		w.do(ctx, "list", http.MethodGet, "/notes", nil, http.StatusOK, nil)
+		w.do(ctx, "search", http.MethodGet, "/notes?meta.loadtest="+url.QueryEscape(w.runID), nil, http.StatusOK, nil)
+		w.do(ctx, "patch", http.MethodPatch, path,
+			map[string]any{"content": fmt.Sprintf("updated %d", w.seq)}, http.StatusOK, nil)
+		w.do(ctx, "delete", http.MethodDelete, path, nil, http.StatusNoContent, nil)
+	}
+}
+
+// create создаёт заметку с уникальным телом (иначе сработает дедупликация).
+func (w *worker) create(ctx context.Context) (int64, bool) {
+	body := map[string]any{
+		"title":    fmt.Sprintf("loadtest %s #%d-%d", w.runID, w.id, w.seq),
+		"content":  "Lorem ipsum dolor sit amet, consectetur adipiscing elit.",
+		"metadata": map[string]string{"loadtest": w.runID},
+	}
+	var note struct {
+		ID int64
+	}
+	ok := w.do(ctx, "create", http.MethodPost, "/notes", body, http.StatusCreated, &note)
+	return note.ID, ok && note.ID != 0
+}
+
+// do выполняет запрос и записывает задержку. Запросы, оборванные концом
+// теста, не учитываются.
+func (w *worker) do(ctx context.Context, op, method, path string, body any, want int, out any) bool {
+	var payload io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			log.Fatal(err)
+		}
+		payload = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, w.base+path, payload)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	s := w.stats[op]
+	if s == nil {
+		s = &stats{}
+		w.stats[op] = s
+	}
+
+	started := time.Now()
+	resp, err := w.client.Do(req)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.errors++
+		}
+		return false
+	}
+	defer resp.Body.Close()
+
+	ok := resp.StatusCode == want
+	if ok && out != nil {
+		ok = json.NewDecoder(resp.Body).Decode(out) == nil
+	} else {
+		_, _ = io.Copy(io.Discard, resp.Body)
+	}
+	latency := time.Since(started)
+
+	if ctx.Err() != nil {
+		return false
+	}
+	s.latencies = append(s.latencies, latency)
+	if !ok {
+		s.errors++
+	}
+	return ok
+}
+
+/*
+====================
+REPORT
+====================
+*/
+
+func report(out io.Writer, workers []*worker, elapsed time.Duration) {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "op\trequests\terrors\trps\tp50\tp90\tp99\tmax\t")
+
+	var total, totalErrors int
+	for _, op := range operations {
+		var merged stats
+		for _, w := range workers {
+			if s := w.stats[op]; s != nil {
+				merged.latencies = append(merged.latencies, s.latencies...)
+				merged.errors += s.errors
+			}
+		}
+		total += len(merged.latencies)
+		totalErrors += merged.errors
+
+		l := merged.latencies
+		slices.Sort(l)
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\t\n",
+			op, len(l), merged.errors, float64(len(l))/elapsed.Seconds(),
+			percentile(l, 50), percentile(l, 90), percentile(l, 99), percentile(l, 100))
+	}
+	fmt.Fprintf(tw, "total\t%d\t%d\t%.1f\t\t\t\t\t\n", total, totalErrors, float64(total)/elapsed.Seconds())
+	tw.Flush()
+}
+
+// percentile возвращает p-й перцентиль отсортированных задержек (nearest-rank).
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := (len(sorted)*p + 99) / 100
+	if i < 1 {
+		i = 1
+	}
+	return sorted[i-1].Round(10 * time.Microsecond)
+}
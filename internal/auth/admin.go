@@ -0,0 +1,79 @@
+// Package auth содержит HTTP middleware для проверки доступа.
+package auth
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"example.com/notes-api/internal/i18n"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Коды ошибок доступа; совпадают по формату с кодами handlers.
+const (
+	CodeAdminRequired    = "admin_required"
+	CodeTooManyAttempts  = "too_many_attempts"
+	CodeInvalidSignature = "invalid_signature"
+)
+
+// AdminToken пропускает только запросы с заголовком "Authorization: Bearer <token>".
+// Пустой token закрывает доступ полностью. Если задан lockout, адрес клиента
+// после серии неверных токенов получает 429 до окончания блокировки.
+// Запросы, уже проверенные Signed, пропускаются без токена.
+func AdminToken(token string, lockout *Lockout) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if SignedKeyID(r.Context()) != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := remoteHost(r)
+			if lockout != nil {
+				if d := lockout.Locked(key); d > 0 {
+					w.Header().Set("Retry-After", strconv.Itoa(int(d.Seconds())+1))
+					respondError(w, r, http.StatusTooManyRequests, CodeTooManyAttempts, "Too many failed attempts")
+					return
+				}
+			}
+
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if token == "" || !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				// Токен пользователя — не попытка подобрать админский: не блокировать за него адрес
+				if lockout != nil && ok && !isJWT(got) {
+					lockout.Fail(key)
+				}
+				respondError(w, r, http.StatusForbidden, CodeAdminRequired, "Admin access required")
+				return
+			}
+			if lockout != nil {
+				lockout.Succeed(key)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func respondError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	w.Header().Add("Vary", "Accept-Language")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	body := map[string]string{"error": i18n.Message(r, message), "code": code}
+	if id := middleware.GetReqID(r.Context()); id != "" {
+		body["request_id"] = id
+	}
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// remoteHost — IP клиента без порта.
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Коды ошибок токенов пользователя.
+const (
+	CodeTokenRequired = "token_required"
+	CodeInvalidToken  = "invalid_token"
+)
+
+// MinJWTSecret — минимальная длина секрета HS256 в байтах.
+const MinJWTSecret = 32
+
+var (
+	errBadToken     = errors.New("invalid token")
+	errTokenExpired = errors.New("token expired")
+)
+
+// jwtHeader — единственный принимаемый заголовок: HS256 без вариантов, чтобы
+// токен с "alg":"none" или другим алгоритмом не проходил проверку.
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+type jwtClaims struct {
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// JWT выпускает и проверяет токены пользователей (JWT, HS256). В sub
+// хранится ID пользователя.
+type JWT struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewJWT создаёт выпуск токенов с секретом secret (не короче MinJWTSecret)
+// и временем жизни ttl.
+func NewJWT(secret []byte, ttl time.Duration) (*JWT, error) {
+	if len(secret) < MinJWTSecret {
+		return nil, errors.New("jwt secret must be at least " + strconv.Itoa(MinJWTSecret) + " bytes")
+	}
+	if ttl <= 0 {
+		return nil, errors.New("jwt ttl must be positive")
+	}
+	return &JWT{secret: secret, ttl: ttl}, nil
+}
+
+// Issue выпускает токен пользователя userID и возвращает его вместе со сроком действия.
+func (j *JWT) Issue(userID int64) (token string, expiresAt time.Time, err error) {
+	now := time.Now()
+	expiresAt = now.Add(j.ttl).Truncate(time.Second)
+	claims, err := json.Marshal(jwtClaims{
+		Subject:   strconv.FormatInt(userID, 10),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	signingInput := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(claims)
+	return signingInput + "." + j.sign(signingInput), expiresAt, nil
+}
+
+// Verify проверяет подпись и срок токена и возвращает ID пользователя.
+func (j *JWT) Verify(token string) (int64, error) {
+	header, rest, ok := strings.Cut(token, ".")
+	if !ok || header != jwtHeader {
+		return 0, errBadToken
+	}
+	payload, sig, ok := strings.Cut(rest, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(j.sign(header+"."+payload))) {
+		return 0, errBadToken
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return 0, errBadToken
+	}
+	var claims jwtClaims
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return 0, errBadToken
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return 0, errTokenExpired
+	}
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, errBadToken
+	}
+	return userID, nil
+}
+
+// isJWT сообщает, похож ли token на токен пользователя (заголовок jwtHeader);
+// подпись и срок не проверяются.
+func isJWT(token string) bool {
+	return strings.HasPrefix(token, jwtHeader+".")
+}
+
+func (j *JWT) sign(signingInput string) string {
+	mac := hmac.New(sha256.New, j.secret)
+	mac.Write([]byte(signingInput))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+type userIDCtx struct{}
+
+// userSlot — ID пользователя в контексте; 0 — запрос без токена пользователя.
+type userSlot struct{ id int64 }
+
+// TrackUser кладёт в контекст пустую ячейку для ID пользователя. RequireJWT
+// ниже по цепочке заполняет её, так что middleware, стоящее раньше (журнал
+// доступа), после обработчика видит ID через UserID.
+func TrackUser(ctx context.Context) context.Context {
+	return context.WithValue(ctx, userIDCtx{}, &userSlot{})
+}
+
+// RequireJWT пропускает только запросы с действующим токеном пользователя
+// в "Authorization: Bearer <token>" и кладёт ID пользователя в контекст
+// (см. UserID). Подписанные запросы (Signed должен стоять раньше) и запросы
+// с админским токеном adminToken проходят без токена пользователя.
+// nil j выключает проверку.
+func RequireJWT(j *JWT, adminToken string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if j == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if SignedKeyID(r.Context()) != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="notes-api"`)
+				respondError(w, r, http.StatusUnauthorized, CodeTokenRequired, "Authentication required")
+				return
+			}
+			if adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			userID, err := j.Verify(token)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="notes-api", error="invalid_token"`)
+				respondError(w, r, http.StatusUnauthorized, CodeInvalidToken, "Invalid or expired token")
+				return
+			}
+			ctx := r.Context()
+			if slot, ok := ctx.Value(userIDCtx{}).(*userSlot); ok {
+				slot.id = userID
+			} else {
+				ctx = context.WithValue(ctx, userIDCtx{}, &userSlot{id: userID})
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// UserID возвращает ID пользователя из токена, проверенного RequireJWT.
+func UserID(ctx context.Context) (int64, bool) {
+	slot, ok := ctx.Value(userIDCtx{}).(*userSlot)
+	if !ok || slot.id == 0 {
+		return 0, false
+	}
+	return slot.id, true
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// maxTracked — сколько адресов хранить, прежде чем чистить устаревшие записи.
+const maxTracked = 10000
+
+// Lockout считает неудачные попытки входа по ключу (обычно IP) и после
+// threshold подряд блокирует ключ, удваивая время блокировки с каждой
+// новой ошибкой, но не больше max.
+type Lockout struct {
+	threshold int
+	base      time.Duration
+	max       time.Duration
+
+	mu       sync.Mutex
+	attempts map[string]*attempt
+}
+
+type attempt struct {
+	failures int
+	until    time.Time
+	last     time.Time
+}
+
+// NewLockout создаёт счётчик попыток.
+func NewLockout(threshold int, base, max time.Duration) *Lockout {
+	return &Lockout{
+		threshold: threshold,
+		base:      base,
+		max:       max,
+		attempts:  make(map[string]*attempt),
+	}
+}
+
+// Locked возвращает, сколько ещё длится блокировка key (0 — не заблокирован).
+func (l *Lockout) Locked(key string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	a, ok := l.attempts[key]
+	if !ok {
+		return 0
+	}
+	if d := time.Until(a.until); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Fail записывает неудачную попытку key.
+func (l *Lockout) Fail(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if len(l.attempts) >= maxTracked {
+		l.prune(now)
+	}
+
+	a, ok := l.attempts[key]
+	if !ok {
+		a = &attempt{}
+		l.attempts[key] = a
+	}
+	a.failures++
+	a.last = now
+
+	if n := a.failures - l.threshold; n >= 0 {
+		d := l.max
+		if n < 32 && l.base<<n < l.max {
+			d = l.base << n
+		}
+		a.until = now.Add(d)
+	}
+}
+
+// Succeed сбрасывает счётчик key после успешной попытки.
+func (l *Lockout) Succeed(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.attempts, key)
+}
+
+// prune удаляет ключи без действующей блокировки, не ошибавшиеся дольше max.
+func (l *Lockout) prune(now time.Time) {
+	for key, a := range l.attempts {
+		if !a.until.After(now) && now.Sub(a.last) > l.max {
+			delete(l.attempts, key)
+		}
+	}
+}
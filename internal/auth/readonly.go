@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+)
+
+// CodeReadOnly — запись без аутентификации в режиме только для чтения.
+const CodeReadOnly = "read_only"
+
+// ReadOnly пропускает запросы на чтение (GET, HEAD, OPTIONS) от всех, а запись —
+// только аутентифицированным клиентам: подписанным (Signed должен стоять раньше),
+// с действующим токеном пользователя tokens или с админским токеном, который
+// проверяется как в AdminToken. Запись без учётных данных получает 403.
+// Токен пользователя не сверяется с админским, поэтому не тратит попытки lockout.
+func ReadOnly(token string, lockout *Lockout, tokens *JWT) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		admin := AdminToken(token, lockout)(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+			if SignedKeyID(r.Context()) != "" {
+				admin.ServeHTTP(w, r)
+				return
+			}
+			bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			switch {
+			case ok && tokens != nil && isJWT(bearer):
+				if _, err := tokens.Verify(bearer); err != nil {
+					w.Header().Set("WWW-Authenticate", `Bearer realm="notes-api", error="invalid_token"`)
+					respondError(w, r, http.StatusUnauthorized, CodeInvalidToken, "Invalid or expired token")
+					return
+				}
+				next.ServeHTTP(w, r)
+			case ok:
+				admin.ServeHTTP(w, r)
+			default:
+				respondError(w, r, http.StatusForbidden, CodeReadOnly, "API is read-only")
+			}
+		})
+	}
+}
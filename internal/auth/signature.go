@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Заголовки подписанного запроса.
+const (
+	SignatureKeyHeader       = "X-Signature-Key"
+	SignatureTimestampHeader = "X-Signature-Timestamp"
+	SignatureHeader          = "X-Signature"
+)
+
+// maxSignedBody — предел тела, которое читается для проверки подписи.
+const maxSignedBody = 10 << 20
+
+var (
+	errBadSignature = errors.New("invalid signature")
+	errStale        = errors.New("signature timestamp out of range")
+	errReplay       = errors.New("signature already used")
+)
+
+// Signer проверяет HMAC-подписи запросов от серверных клиентов.
+//
+// Подписывается строка
+//
+//	METHOD \n REQUEST_URI \n TIMESTAMP \n hex(sha256(body))
+//
+// ключом клиента через HMAC-SHA256; результат в hex передаётся в X-Signature,
+// ID ключа — в X-Signature-Key, unix-время в секундах — в X-Signature-Timestamp.
+// Подпись принимается, если время отличается от серверного не больше чем на skew,
+// и только один раз.
+type Signer struct {
+	keys map[string][]byte
+	skew time.Duration
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// NewSigner создаёт проверку подписей для ключей keyID → секрет.
+func NewSigner(keys map[string][]byte, skew time.Duration) *Signer {
+	return &Signer{keys: keys, skew: skew, seen: make(map[string]time.Time)}
+}
+
+// Verify проверяет подпись запроса и возвращает ID ключа.
+// Тело запроса читается и подменяется копией, чтобы обработчик мог его прочитать.
+func (s *Signer) Verify(r *http.Request) (string, error) {
+	keyID := r.Header.Get(SignatureKeyHeader)
+	secret, ok := s.keys[keyID]
+	if !ok {
+		return "", errBadSignature
+	}
+
+	tsStr := r.Header.Get(SignatureTimestampHeader)
+	ts, err := strconv.ParseInt(tsStr, 10, 64)
+	if err != nil {
+		return "", errStale
+	}
+	signedAt := time.Unix(ts, 0)
+	if d := time.Since(signedAt); d > s.skew || d < -s.skew {
+		return "", errStale
+	}
+
+	got, err := hex.DecodeString(r.Header.Get(SignatureHeader))
+	if err != nil {
+		return "", errBadSignature
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSignedBody+1))
+	if err != nil || len(body) > maxSignedBody {
+		return "", errBadSignature
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, secret)
+	io.WriteString(mac, r.Method+"\n"+r.URL.RequestURI()+"\n"+tsStr+"\n"+hex.EncodeToString(bodyHash[:]))
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return "", errBadSignature
+	}
+
+	if !s.remember(keyID+":"+hex.EncodeToString(got), signedAt.Add(s.skew)) {
+		return "", errReplay
+	}
+	return keyID, nil
+}
+
+// remember запоминает подпись до expires; false — она уже встречалась.
+func (s *Signer) remember(sig string, expires time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if len(s.seen) >= maxTracked {
+		for k, exp := range s.seen {
+			if !exp.After(now) {
+				delete(s.seen, k)
+			}
+		}
+	}
+
+	if exp, ok := s.seen[sig]; ok && exp.After(now) {
+		return false
+	}
+	s.seen[sig] = expires
+	return true
+}
+
+type signedKeyCtx struct{}
+
+// Signed проверяет запросы с заголовком X-Signature и отклоняет их с 401
+// при неверной подписи. Запросы без подписи пропускаются как есть.
+// nil signer выключает проверку.
+func Signed(s *Signer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if s == nil || r.Header.Get(SignatureHeader) == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			keyID, err := s.Verify(r)
+			if err != nil {
+				respondError(w, r, http.StatusUnauthorized, CodeInvalidSignature, "Invalid request signature")
+				return
+			}
+			ctx := context.WithValue(r.Context(), signedKeyCtx{}, keyID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// SignedKeyID возвращает ID ключа, которым подписан запрос, или "".
+func SignedKeyID(ctx context.Context) string {
+	id, _ := ctx.Value(signedKeyCtx{}).(string)
+	return id
+}
@@ -0,0 +1,215 @@
+// Package backup выгружает заметки в сжатые файлы в каталоге и чистит старые копии.
+//
+// Формат файла — gzip с JSON по строкам: первая строка — Header, далее по
+// одной core.Note на строку, включая удалённые и архивные заметки.
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/notes-api/internal/core"
+)
+
+const (
+	// Format — значение Header.Format в файлах копий.
+	Format = "notes-backup"
+	// Version — версия формата.
+	Version = 1
+
+	filePrefix = "notes-"
+	fileSuffix = ".jsonl.gz"
+	pageSize   = 500
+)
+
+// Header — первая строка файла копии.
+type Header struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Info — файл резервной копии в каталоге.
+type Info struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Source отдаёт все заметки страницами от новых к старым (beforeID = 0 — с начала).
+type Source interface {
+	ListAllNotes(ctx context.Context, beforeID int64, limit int) ([]core.Note, error)
+}
+
+// Runner делает резервные копии в dir и оставляет keep последних.
+type Runner struct {
+	src  Source
+	dir  string
+	keep int
+
+	mu          sync.Mutex
+	lastSuccess time.Time
+}
+
+// NewRunner создаёт Runner; keep <= 0 — старые копии не удаляются.
+func NewRunner(src Source, dir string, keep int) *Runner {
+	return &Runner{src: src, dir: dir, keep: keep}
+}
+
+// Run делает копию и ротацию; подходит для jobs.Every.
+func (b *Runner) Run(ctx context.Context) error {
+	info, count, err := b.Backup(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("Backup %s written, %d notes", info.Name, count)
+	return nil
+}
+
+// Backup пишет новую копию и удаляет лишние старые. Одновременно выполняется
+// не больше одной копии.
+func (b *Runner) Backup(ctx context.Context) (Info, int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := os.MkdirAll(b.dir, 0o700); err != nil {
+		return Info{}, 0, err
+	}
+
+	now := time.Now().UTC()
+	name := filePrefix + now.Format("20060102T150405Z") + fileSuffix
+	path := filepath.Join(b.dir, name)
+
+	count, err := b.write(ctx, path, now)
+	if err != nil {
+		return Info{}, 0, err
+	}
+
+	if err := b.rotate(); err != nil {
+		log.Printf("Backup rotation failed: %v", err)
+	}
+
+	st, err := os.Stat(path)
+	if err != nil {
+		return Info{}, 0, err
+	}
+	b.lastSuccess = now
+	return Info{Name: name, Size: st.Size(), CreatedAt: now}, count, nil
+}
+
+// write выгружает заметки во временный файл и атомарно переименовывает его в path.
+func (b *Runner) write(ctx context.Context, path string, now time.Time) (int, error) {
+	tmp, err := os.CreateTemp(b.dir, ".backup-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	zw := gzip.NewWriter(tmp)
+	bw := bufio.NewWriter(zw)
+	enc := json.NewEncoder(bw)
+
+	if err := enc.Encode(Header{Format: Format, Version: Version, CreatedAt: now}); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	var before int64
+	for {
+		notes, err := b.src.ListAllNotes(ctx, before, pageSize)
+		if err != nil {
+			return 0, err
+		}
+		for _, n := range notes {
+			if err := enc.Encode(n); err != nil {
+				return 0, err
+			}
+		}
+		count += len(notes)
+		if len(notes) < pageSize {
+			break
+		}
+		before = notes[len(notes)-1].ID
+	}
+
+	if err := bw.Flush(); err != nil {
+		return 0, err
+	}
+	if err := zw.Close(); err != nil {
+		return 0, err
+	}
+	if err := tmp.Sync(); err != nil {
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+	return count, os.Rename(tmp.Name(), path)
+}
+
+// rotate удаляет копии сверх keep, начиная со старых.
+func (b *Runner) rotate() error {
+	if b.keep <= 0 {
+		return nil
+	}
+	list, err := b.List()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, info := range list[min(b.keep, len(list)):] {
+		if err := os.Remove(filepath.Join(b.dir, info.Name)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// List возвращает копии в каталоге от новых к старым.
+func (b *Runner) List() ([]Info, error) {
+	entries, err := os.ReadDir(b.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Info{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := []Info{}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix)
+		created, err := time.Parse("20060102T150405Z", stamp)
+		if err != nil {
+			continue
+		}
+		fi, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, Info{Name: name, Size: fi.Size(), CreatedAt: created})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+	return list, nil
+}
+
+// LastSuccess — время последней успешной копии этого процесса (нулевое, если её не было).
+func (b *Runner) LastSuccess() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastSuccess
+}
@@ -0,0 +1,92 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"example.com/notes-api/internal/core"
+)
+
+// maxLine — предел длины строки файла копии (одна заметка).
+const maxLine = 64 << 20
+
+// Read читает файл копии, проверяет заголовок и вызывает fn для каждой заметки.
+func Read(r io.Reader, fn func(core.Note) error) (Header, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return Header{}, err
+	}
+	defer zr.Close()
+
+	sc := bufio.NewScanner(zr)
+	sc.Buffer(make([]byte, 64*1024), maxLine)
+
+	var h Header
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return Header{}, err
+		}
+		return Header{}, fmt.Errorf("empty backup")
+	}
+	if err := json.Unmarshal(sc.Bytes(), &h); err != nil {
+		return Header{}, fmt.Errorf("header: %w", err)
+	}
+	if h.Format != Format || h.Version != Version {
+		return Header{}, fmt.Errorf("unsupported backup format %q v%d", h.Format, h.Version)
+	}
+
+	for line := 2; sc.Scan(); line++ {
+		var n core.Note
+		if err := json.Unmarshal(sc.Bytes(), &n); err != nil {
+			return h, fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := fn(n); err != nil {
+			return h, err
+		}
+	}
+	return h, sc.Err()
+}
+
+// Digest — число заметок и контрольная сумма их содержимого, не зависящая от порядка.
+type Digest struct {
+	Count int
+	sum   [sha256.Size]byte
+}
+
+// Add учитывает заметку в сумме.
+func (d *Digest) Add(n core.Note) {
+	h := sha256.New()
+	var id [8]byte
+	binary.BigEndian.PutUint64(id[:], uint64(n.ID))
+	h.Write(id[:])
+	// jsonb отдаёт metadata с пробелами, а JSON копии — компактно
+	var metadata bytes.Buffer
+	if err := json.Compact(&metadata, n.Metadata); err != nil {
+		metadata.Write(n.Metadata)
+	}
+	for _, part := range [][]byte{[]byte(n.Title), []byte(n.Content), metadata.Bytes(), n.Ciphertext, n.Nonce} {
+		var l [8]byte
+		binary.BigEndian.PutUint64(l[:], uint64(len(part)))
+		h.Write(l[:])
+		h.Write(part)
+	}
+
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	for i := range d.sum {
+		d.sum[i] ^= sum[i]
+	}
+	d.Count++
+}
+
+// String — "count:hex".
+func (d Digest) String() string {
+	return fmt.Sprintf("%d:%s", d.Count, hex.EncodeToString(d.sum[:]))
+}
@@ -0,0 +1,109 @@
+// Package changes хранит ленту последних изменений заметок для long-polling клиентов.
+package changes
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Типы событий ленты.
+const (
+	NoteCreated = "created"
+	NoteUpdated = "updated"
+	NoteDeleted = "deleted"
+)
+
+// Event — одно изменение заметки с порядковым номером в ленте.
+type Event struct {
+	Seq    int64     `json:"seq"`
+	NoteID int64     `json:"note_id"`
+	Type   string    `json:"type"`
+	At     time.Time `json:"at"`
+}
+
+// Feed — in-memory кольцевой буфер событий. Порядковые номера живут
+// только в рамках процесса: после перезапуска лента начинается заново.
+type Feed struct {
+	mu       sync.Mutex
+	capacity int
+	events   []Event
+	seq      int64
+	notify   chan struct{}
+}
+
+// NewFeed создаёт ленту, хранящую не более capacity последних событий.
+func NewFeed(capacity int) *Feed {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &Feed{
+		capacity: capacity,
+		notify:   make(chan struct{}),
+	}
+}
+
+// Publish добавляет событие в ленту и будит всех ожидающих.
+func (f *Feed) Publish(noteID int64, typ string) Event {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.seq++
+	ev := Event{Seq: f.seq, NoteID: noteID, Type: typ, At: time.Now().UTC()}
+
+	if len(f.events) == f.capacity {
+		copy(f.events, f.events[1:])
+		f.events = f.events[:len(f.events)-1]
+	}
+	f.events = append(f.events, ev)
+
+	close(f.notify)
+	f.notify = make(chan struct{})
+	return ev
+}
+
+// Since возвращает события с номером больше seq и последний номер ленты.
+// truncated = true, если часть событий после seq уже вытеснена из буфера
+// (или seq из прошлой жизни процесса) — клиенту нужна полная пересинхронизация.
+func (f *Feed) Since(seq int64) (events []Event, last int64, truncated bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.since(seq)
+}
+
+func (f *Feed) since(seq int64) ([]Event, int64, bool) {
+	if seq > f.seq {
+		return []Event{}, f.seq, true
+	}
+
+	truncated := len(f.events) > 0 && seq < f.events[0].Seq-1
+
+	result := []Event{}
+	for _, ev := range f.events {
+		if ev.Seq > seq {
+			result = append(result, ev)
+		}
+	}
+	return result, f.seq, truncated
+}
+
+// Wait блокируется, пока после seq не появится хотя бы одно событие
+// или не завершится ctx. По таймауту возвращает пустой список без ошибки.
+func (f *Feed) Wait(ctx context.Context, seq int64) (events []Event, last int64, truncated bool) {
+	for {
+		f.mu.Lock()
+		events, last, truncated = f.since(seq)
+		ch := f.notify
+		f.mu.Unlock()
+
+		if len(events) > 0 || truncated {
+			return events, last, truncated
+		}
+
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return events, last, truncated
+		}
+	}
+}
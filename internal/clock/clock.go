@@ -0,0 +1,23 @@
+// Package clock — источник текущего времени, подменяемый в тестах.
+package clock
+
+import "time"
+
+// Clock возвращает текущее время.
+type Clock interface {
+	Now() time.Time
+}
+
+// System — системные часы (time.Now).
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// Func превращает функцию в Clock, например для фиксированного времени:
+//
+//	clock.Func(func() time.Time { return fixed })
+type Func func() time.Time
+
+func (f Func) Now() time.Time { return f() }
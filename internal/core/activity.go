@@ -0,0 +1,34 @@
+package core
+
+import "time"
+
+// Действия, записываемые в notes_log.
+const (
+	ActionCreated  = "created"
+	ActionUpdated  = "updated"
+	ActionDeleted  = "deleted"
+	ActionExpired  = "expired"
+	ActionArchived = "archived"
+
+	ActionHoldPlaced   = "hold_placed"
+	ActionHoldReleased = "hold_released"
+
+	ActionRestored = "restored"
+
+	ActionAnnounced   = "announced"
+	ActionUnannounced = "unannounced"
+
+	ActionDrafted         = "drafted"
+	ActionReviewRequested = "review_requested"
+	ActionApproved        = "approved"
+	ActionRejected        = "rejected"
+)
+
+// ActivityEntry — запись ленты активности.
+type ActivityEntry struct {
+	ID        int64     `json:"id"`
+	NoteID    int64     `json:"note_id"`
+	Action    string    `json:"action"`
+	NoteTitle *string   `json:"note_title,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,10 @@
+package core
+
+// CalendarDay — заметки, созданные за один день.
+type CalendarDay struct {
+	// Date — день в часовом поясе запроса, YYYY-MM-DD.
+	Date  string `json:"date" example:"2025-01-31"`
+	Count int    `json:"count"`
+	// Notes — первые заметки дня по времени создания; их может быть меньше Count.
+	Notes []NoteShort `json:"notes"`
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound — заметки нет: не существует, удалена или истекла.
+var ErrNotFound = errors.New("note not found")
+
+// ErrVersionConflict — заметка изменилась на сервере после базовой версии клиента.
+var ErrVersionConflict = errors.New("version conflict")
+
+// ErrNoteLocked — заметка заблокирована другим владельцем.
+var ErrNoteLocked = errors.New("note is locked")
+
+// ErrInvalidMove — якорь перемещения совпадает с самой заметкой или не найден.
+var ErrInvalidMove = errors.New("invalid move target")
+
+// ErrLegalHold — заметка на юридическом удержании и не может быть удалена.
+var ErrLegalHold = errors.New("note is under legal hold")
+
+// ErrReviewState — переход согласования недопустим из текущего состояния заметки.
+var ErrReviewState = errors.New("invalid review state transition")
+
+// ErrNotReviewer — решение по согласованию принимает не назначенный рецензент.
+var ErrNotReviewer = errors.New("not the assigned reviewer")
+
+// ErrTitleTaken — заголовок уже занят другой заметкой (включена уникальность заголовков).
+var ErrTitleTaken = errors.New("title is already taken")
+
+// TitleTakenError — ErrTitleTaken с ID заметки, которой принадлежит заголовок.
+type TitleTakenError struct {
+	NoteID int64
+}
+
+func (e *TitleTakenError) Error() string {
+	return fmt.Sprintf("title is already taken by note %d", e.NoteID)
+}
+
+// Is позволяет проверять ошибку через errors.Is(err, ErrTitleTaken).
+func (e *TitleTakenError) Is(target error) bool {
+	return target == ErrTitleTaken
+}
+
+// ErrUserNotFound — пользователя с таким ID или email нет.
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrEmailTaken — email уже зарегистрирован другим пользователем.
+var ErrEmailTaken = errors.New("email is already registered")
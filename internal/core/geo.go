@@ -0,0 +1,12 @@
+package core
+
+// ValidCoordinates проверяет широту и долготу в градусах.
+func ValidCoordinates(lat, lon float64) bool {
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
+// NearbyNote — заметка с расстоянием до точки запроса.
+type NearbyNote struct {
+	Note           Note    `json:"note"`
+	DistanceMeters float64 `json:"distance_m"`
+}
@@ -0,0 +1,25 @@
+package core
+
+// Проверки целостности данных.
+const (
+	// CheckOrphanLog — записи notes_log о заметках, которых больше нет
+	// (удалены правилом хранения, wipe при restore) и чьё удаление не
+	// записано в журнал как expired. Записи об истечении срока хранятся
+	// как след удаления и сиротами не считаются.
+	CheckOrphanLog = "orphan_log_entries"
+	// CheckExpiredLocks — истёкшие блокировки редактирования, которые
+	// никто не снял и не перехватил.
+	CheckExpiredLocks = "expired_locks"
+)
+
+// IntegrityChecks — все проверки в порядке выполнения.
+var IntegrityChecks = []string{CheckOrphanLog, CheckExpiredLocks}
+
+// IntegrityResult — итог одной проверки.
+type IntegrityResult struct {
+	Check string `json:"check" example:"orphan_log_entries"`
+	// Found — сколько проблемных строк найдено (до исправления).
+	Found int64 `json:"found"`
+	// Repaired — сколько строк исправлено; только при repair.
+	Repaired int64 `json:"repaired"`
+}
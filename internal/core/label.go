@@ -0,0 +1,29 @@
+package core
+
+import "regexp"
+
+// DefaultColor — цвет заметки по умолчанию.
+const DefaultColor = "default"
+
+// Colors — допустимая палитра карточек.
+var Colors = []string{
+	DefaultColor, "red", "orange", "yellow", "green", "teal",
+	"blue", "darkblue", "purple", "pink", "brown", "gray",
+}
+
+var iconPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// ValidColor сообщает, входит ли цвет в палитру.
+func ValidColor(color string) bool {
+	for _, c := range Colors {
+		if c == color {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidIcon проверяет имя иконки; пустая строка означает «без иконки».
+func ValidIcon(icon string) bool {
+	return icon == "" || iconPattern.MatchString(icon)
+}
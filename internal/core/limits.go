@@ -0,0 +1,45 @@
+package core
+
+import "fmt"
+
+// Потолки размеров, закреплённые CHECK-ограничениями в БД
+// (migrations/0020_note_size_limits.sql): настраиваемые лимиты не могут быть выше.
+const (
+	// TitleCeiling — символов в заголовке.
+	TitleCeiling = 1000
+	// ContentCeiling — байт в content или ciphertext.
+	ContentCeiling = 8 << 20
+)
+
+// Limits — предельные размеры заметки, проверяемые при записи.
+type Limits struct {
+	// MaxTitle — символов в заголовке.
+	MaxTitle int
+	// MaxContent — байт в content (или ciphertext зашифрованной клиентом заметки).
+	MaxContent int
+}
+
+// DefaultLimits — лимиты по умолчанию.
+var DefaultLimits = Limits{MaxTitle: 500, MaxContent: 1 << 20}
+
+// OrDefault подставляет значения по умолчанию вместо нулевых.
+func (l Limits) OrDefault() Limits {
+	if l.MaxTitle <= 0 {
+		l.MaxTitle = DefaultLimits.MaxTitle
+	}
+	if l.MaxContent <= 0 {
+		l.MaxContent = DefaultLimits.MaxContent
+	}
+	return l
+}
+
+// Validate проверяет, что лимиты не выше потолков БД.
+func (l Limits) Validate() error {
+	if l.MaxTitle > TitleCeiling {
+		return fmt.Errorf("title limit %d exceeds %d", l.MaxTitle, TitleCeiling)
+	}
+	if l.MaxContent > ContentCeiling {
+		return fmt.Errorf("content limit %d exceeds %d", l.MaxContent, ContentCeiling)
+	}
+	return nil
+}
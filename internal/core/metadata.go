@@ -0,0 +1,87 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Ограничения на метаданные заметки.
+const (
+	MaxMetadataBytes = 16 * 1024
+	MaxMetadataDepth = 5
+)
+
+// ValidateMetadata проверяет, что метаданные — JSON-объект допустимого размера и вложенности.
+func ValidateMetadata(raw json.RawMessage) error {
+	if len(raw) > MaxMetadataBytes {
+		return errors.New("metadata is too large")
+	}
+	if !json.Valid(raw) {
+		return errors.New("invalid metadata")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	tok, err := dec.Token()
+	if err != nil {
+		return errors.New("invalid metadata")
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return errors.New("metadata must be a JSON object")
+	}
+
+	depth := 1
+	for depth > 0 {
+		tok, err := dec.Token()
+		if err != nil {
+			return errors.New("invalid metadata")
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+			if depth > MaxMetadataDepth {
+				return errors.New("metadata is nested too deeply")
+			}
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	return nil
+}
+
+// MetadataFilter строит JSON для запроса вхождения из пар "a.b" → "v":
+// {"a": {"b": "v"}}. Значения всегда сравниваются как строки.
+func MetadataFilter(pairs map[string]string) (json.RawMessage, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	root := map[string]any{}
+	for key, value := range pairs {
+		parts := strings.Split(key, ".")
+		node := root
+		for i, part := range parts {
+			if part == "" {
+				return nil, errors.New("invalid metadata filter key")
+			}
+			if i == len(parts)-1 {
+				if _, exists := node[part]; exists {
+					return nil, errors.New("conflicting metadata filter keys")
+				}
+				node[part] = value
+				break
+			}
+			child, ok := node[part].(map[string]any)
+			if !ok {
+				if _, exists := node[part]; exists {
+					return nil, errors.New("conflicting metadata filter keys")
+				}
+				child = map[string]any{}
+				node[part] = child
+			}
+			node = child
+		}
+	}
+	return json.Marshal(root)
+}
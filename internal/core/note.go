@@ -1,23 +1,147 @@
 package core
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Note struct {
-	ID        int64
-	Title     string
-	Content   string
-	CreatedAt time.Time
-	UpdatedAt *time.Time
+	ID      int64
+	Title   string
+	Content string
+	Lang    string
+	// ContentKind и Preview — вид содержимого и превью (пакет preview),
+	// считаются при записи; пустые у заметок, записанных до их появления.
+	ContentKind  string
+	Preview      string
+	Slug         string
+	Version      int64
+	ViewCount    int64
+	LastViewedAt *time.Time
+	Metadata     json.RawMessage
+	Color        string
+	Icon         string
+	Position     float64
+	Latitude     *float64
+	Longitude    *float64
+	ExpiresAt    *time.Time
+	Encrypted    bool
+	Ciphertext   []byte
+	Nonce        []byte
+	KeyID        *string
+	ArchivedAt   *time.Time
+	LegalHoldAt  *time.Time
+	// AnnouncedAt — когда заметка стала объявлением; AnnounceFrom и
+	// AnnounceUntil — окно показа. Announced — объявление показывается сейчас.
+	AnnouncedAt   *time.Time
+	AnnounceFrom  *time.Time
+	AnnounceUntil *time.Time
+	Announced     bool
+	// ReviewState — состояние согласования (ReviewDraft…), пустое — вне процесса.
+	ReviewState string
+	Reviewer    *string
+	ReviewedAt  *time.Time
+	DeletedAt   *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   *time.Time
 }
 
 type NoteCreate struct {
-	Title   string `json:"title" example:"Новая заметка"`
-	Content string `json:"content" example:"Текст заметки"`
+	Title    string          `json:"title" example:"Новая заметка"`
+	Content  string          `json:"content" example:"Текст заметки"`
+	Metadata json.RawMessage `json:"metadata,omitempty" swaggertype:"object"`
+	Color    string          `json:"color,omitempty" example:"yellow"`
+	Icon     string          `json:"icon,omitempty" example:"idea"`
+
+	Latitude  *float64 `json:"latitude,omitempty" example:"55.7558"`
+	Longitude *float64 `json:"longitude,omitempty" example:"37.6173"`
+
+	// ExpiresAt — после этого момента заметка скрывается и удаляется фоновой задачей.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// Encrypted — содержимое зашифровано клиентом: content пустой,
+	// ciphertext и nonce передаются в base64 и сервером не разбираются.
+	Encrypted  bool   `json:"encrypted,omitempty"`
+	Ciphertext []byte `json:"ciphertext,omitempty" swaggertype:"string" format:"base64"`
+	Nonce      []byte `json:"nonce,omitempty" swaggertype:"string" format:"base64"`
+	KeyID      string `json:"key_id,omitempty" example:"device-key-1"`
 }
 
 type NoteUpdate struct {
 	Title   *string `json:"title,omitempty" example:"Обновлено"`
 	Content *string `json:"content,omitempty" example:"Новый текст"`
+	// Metadata заменяет метаданные целиком.
+	Metadata json.RawMessage `json:"metadata,omitempty" swaggertype:"object"`
+	Color    *string         `json:"color,omitempty" example:"green"`
+	// Icon: пустая строка убирает иконку.
+	Icon *string `json:"icon,omitempty" example:"todo"`
+	// Latitude и Longitude задаются вместе; ClearLocation убирает геометку.
+	Latitude      *float64 `json:"latitude,omitempty"`
+	Longitude     *float64 `json:"longitude,omitempty"`
+	ClearLocation bool     `json:"clear_location,omitempty"`
+	// RegenerateSlug пересчитывает slug из (нового) заголовка.
+	RegenerateSlug bool `json:"regenerate_slug,omitempty"`
+	// ExpiresAt переносит срок жизни; ClearExpiry делает заметку бессрочной.
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	ClearExpiry bool       `json:"clear_expiry,omitempty"`
+	// Ciphertext, Nonce и KeyID меняют шифртекст зашифрованной заметки (вместе).
+	Ciphertext []byte  `json:"ciphertext,omitempty" swaggertype:"string" format:"base64"`
+	Nonce      []byte  `json:"nonce,omitempty" swaggertype:"string" format:"base64"`
+	KeyID      *string `json:"key_id,omitempty"`
+	// Archived убирает заметку в архив или возвращает из него.
+	Archived *bool `json:"archived,omitempty"`
+
+	// BaseVersion — версия, от которой клиент начинал правку (для синхронизации).
+	BaseVersion *int64 `json:"base_version,omitempty" example:"3"`
+	// Base — содержимое базовой версии у клиента; нужно для трёхстороннего слияния.
+	Base *NoteBase `json:"base,omitempty"`
+}
+
+// Empty сообщает, что в запросе нет ни одного изменяемого поля.
+func (u NoteUpdate) Empty() bool {
+	return u.Title == nil && u.Content == nil && u.Metadata == nil &&
+		u.Color == nil && u.Icon == nil && u.Latitude == nil && u.Longitude == nil &&
+		!u.ClearLocation && !u.RegenerateSlug && u.ExpiresAt == nil && !u.ClearExpiry &&
+		u.Ciphertext == nil && u.Nonce == nil && u.KeyID == nil && u.Archived == nil
+}
+
+// NoteBase — состояние заметки, которое клиент видел в последний раз.
+type NoteBase struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// NoteFilter — условия выборки списка заметок.
+type NoteFilter struct {
+	// Metadata — JSON-объект, который должен входить в metadata заметки.
+	Metadata json.RawMessage
+	Color    string
+	// Lang — код языка заметки (lang.Codes).
+	Lang string
+	// Archived — показывать только архивные заметки вместо неархивных.
+	Archived bool
+	// ReviewState — только заметки в этом состоянии согласования.
+	ReviewState string
+	// Sort — порядок выдачи: SortCreated (по умолчанию) или SortManual.
+	Sort string
+}
+
+// Варианты сортировки списка заметок.
+const (
+	SortCreated = "created"
+	SortManual  = "manual"
+)
+
+// NoteAnnouncement — окно показа объявления; пустые границы — без ограничения.
+type NoteAnnouncement struct {
+	From  *time.Time `json:"from,omitempty" example:"2025-03-01T09:00:00Z"`
+	Until *time.Time `json:"until,omitempty" example:"2025-03-08T18:00:00Z"`
+}
+
+// NoteMove — куда переставить заметку: после AfterID или перед BeforeID.
+type NoteMove struct {
+	AfterID  *int64 `json:"after_id,omitempty" example:"12"`
+	BeforeID *int64 `json:"before_id,omitempty"`
 }
 
 type NoteCursor struct {
@@ -29,3 +153,20 @@ type NoteShort struct {
 	ID    int64  `json:"id"`
 	Title string `json:"title"`
 }
+
+// NoteLock — блокировка редактирования заметки.
+type NoteLock struct {
+	NoteID    int64     `json:"note_id"`
+	Owner     string    `json:"owner,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type NoteLockRequest struct {
+	Owner      string `json:"owner" example:"alice"`
+	TTLSeconds int    `json:"ttl_seconds,omitempty" example:"300"`
+}
+
+// NoteDiffRequest — текст, с которым сравнивается content заметки.
+type NoteDiffRequest struct {
+	Content *string `json:"content" example:"Текст заметки\nНовая строка"`
+}
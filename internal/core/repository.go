@@ -0,0 +1,27 @@
+package core
+
+import "context"
+
+// NoteRepository — основные операции хранилища заметок. Отсутствующая
+// заметка — ErrNotFound.
+type NoteRepository interface {
+	// CreateWithLogTx создаёт заметку и запись о создании в журнале изменений.
+	CreateWithLogTx(ctx context.Context, n NoteCreate) (int64, error)
+	GetByID(ctx context.Context, id int64) (*Note, error)
+	// Update меняет поля заметки; при расхождении u.BaseVersion — ErrVersionConflict.
+	Update(ctx context.Context, id int64, u NoteUpdate) error
+	// Delete удаляет заметку; заметку на удержании — ErrLegalHold.
+	Delete(ctx context.Context, id int64) error
+	List(ctx context.Context, f NoteFilter) ([]Note, error)
+}
+
+// UserRepository — хранилище учётных записей. Email сравнивается без учёта
+// регистра; отсутствующий пользователь — ErrUserNotFound, занятый email — ErrEmailTaken.
+type UserRepository interface {
+	// CreateUser сохраняет u (ID и даты заполняет хранилище) и возвращает ID.
+	CreateUser(ctx context.Context, u User) (int64, error)
+	GetUserByID(ctx context.Context, id int64) (*User, error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	// UpdateUser меняет поля u; пароль — через u.PasswordHash.
+	UpdateUser(ctx context.Context, id int64, u UserUpdate) error
+}
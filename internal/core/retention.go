@@ -0,0 +1,67 @@
+package core
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Виды правил хранения.
+const (
+	// RetentionArchiveNotes архивирует заметки, не менявшиеся дольше MaxAge.
+	RetentionArchiveNotes = "archive_notes"
+	// RetentionPurgeLog удаляет записи notes_log старше MaxAge.
+	RetentionPurgeLog = "purge_log"
+	// RetentionPurgeDeleted окончательно удаляет заметки, удалённые раньше MaxAge назад.
+	RetentionPurgeDeleted = "purge_deleted"
+	// RetentionTrimLog оставляет в notes_log только MaxRows последних записей.
+	RetentionTrimLog = "trim_log"
+)
+
+// RetentionRule — одно правило хранения данных. Правила по возрасту
+// используют MaxAge, trim_log — MaxRows.
+type RetentionRule struct {
+	Kind    string        `json:"kind" example:"archive_notes"`
+	MaxAge  time.Duration `json:"max_age,omitempty" swaggertype:"string" example:"2160h0m0s"`
+	MaxRows int64         `json:"max_rows,omitempty"`
+}
+
+// ByAge сообщает, задаёт ли правило границу по возрасту.
+func (r RetentionRule) ByAge() bool {
+	return r.Kind != RetentionTrimLog
+}
+
+// MarshalJSON отдаёт MaxAge строкой вида "2160h0m0s".
+func (r RetentionRule) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Kind    string `json:"kind"`
+		MaxAge  string `json:"max_age,omitempty"`
+		MaxRows int64  `json:"max_rows,omitempty"`
+	}{Kind: r.Kind, MaxRows: r.MaxRows}
+	if r.ByAge() {
+		out.MaxAge = r.MaxAge.String()
+	}
+	return json.Marshal(out)
+}
+
+// RetentionPreview — что затронет правило при следующем запуске.
+type RetentionPreview struct {
+	Rule RetentionRule `json:"rule"`
+	// Cutoff — граница по времени; только для правил по возрасту.
+	Cutoff   *time.Time `json:"cutoff,omitempty"`
+	Affected int64      `json:"affected"`
+	// SampleIDs — несколько ID затронутых строк для проверки.
+	SampleIDs []int64 `json:"sample_ids"`
+}
+
+// RetentionResult — итог применения правила хранения.
+type RetentionResult struct {
+	Rule     RetentionRule `json:"rule"`
+	Affected int64         `json:"affected"`
+}
+
+// TableStats — размер таблицы для наблюдения за ростом.
+type TableStats struct {
+	Table         string `json:"table"`
+	EstimatedRows int64  `json:"estimated_rows"`
+	SizeBytes     int64  `json:"size_bytes"`
+}
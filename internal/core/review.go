@@ -0,0 +1,41 @@
+package core
+
+// Состояния согласования заметки. Пустое состояние — заметка вне процесса.
+const (
+	ReviewDraft     = "draft"
+	ReviewInReview  = "in_review"
+	ReviewPublished = "published"
+)
+
+// ValidReviewState сообщает, является ли s непустым состоянием согласования.
+func ValidReviewState(s string) bool {
+	return s == ReviewDraft || s == ReviewInReview || s == ReviewPublished
+}
+
+// ReviewRequest — тело запросов согласования.
+type ReviewRequest struct {
+	Reviewer string `json:"reviewer" example:"bob"`
+}
+
+// ReviewTransition — переход согласования: из каких состояний, в какое,
+// какое действие пишется в журнал. ByReviewer — переход делает только
+// назначенный рецензент; Assign — переход назначает рецензента.
+type ReviewTransition struct {
+	From       []string
+	To         string
+	Action     string
+	ByReviewer bool
+	Assign     bool
+}
+
+// Переходы согласования.
+var (
+	// ReviewToDraft вводит заметку в процесс или возвращает опубликованную в черновик.
+	ReviewToDraft = ReviewTransition{From: []string{"", ReviewPublished}, To: ReviewDraft, Action: ActionDrafted}
+	// ReviewSubmit отправляет черновик на согласование рецензенту.
+	ReviewSubmit = ReviewTransition{From: []string{"", ReviewDraft}, To: ReviewInReview, Action: ActionReviewRequested, Assign: true}
+	// ReviewApprove публикует заметку.
+	ReviewApprove = ReviewTransition{From: []string{ReviewInReview}, To: ReviewPublished, Action: ActionApproved, ByReviewer: true}
+	// ReviewReject возвращает заметку автору в черновик.
+	ReviewReject = ReviewTransition{From: []string{ReviewInReview}, To: ReviewDraft, Action: ActionRejected, ByReviewer: true}
+)
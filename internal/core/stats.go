@@ -0,0 +1,18 @@
+package core
+
+import "time"
+
+// NoteStats — сводка по истории заметки.
+type NoteStats struct {
+	NoteID int64 `json:"note_id"`
+	// Version — текущая версия; растёт на каждое изменение.
+	Version int64 `json:"version"`
+	// Edits — число изменений по notes_log.
+	Edits int64 `json:"edits"`
+	// EditsPerDay — среднее число изменений в день с момента создания.
+	EditsPerDay  float64    `json:"edits_per_day"`
+	ViewCount    int64      `json:"view_count"`
+	LastViewedAt *time.Time `json:"last_viewed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastEditedAt *time.Time `json:"last_edited_at,omitempty"`
+}
@@ -0,0 +1,50 @@
+package core
+
+import "time"
+
+// User — учётная запись пользователя.
+type User struct {
+	ID    int64
+	Email string
+	Name  string
+	// PasswordHash — bcrypt-хеш пароля (auth.HashPassword).
+	PasswordHash string
+	CreatedAt    time.Time
+	UpdatedAt    *time.Time
+}
+
+// UserRegister — данные регистрации.
+type UserRegister struct {
+	Email    string `json:"email" example:"alice@example.com"`
+	Password string `json:"password" example:"correct horse battery"`
+	Name     string `json:"name,omitempty" example:"Алиса"`
+}
+
+// UserLogin — вход по email и паролю.
+type UserLogin struct {
+	Email    string `json:"email" example:"alice@example.com"`
+	Password string `json:"password" example:"correct horse battery"`
+}
+
+// UserUpdate — изменение своей учётной записи; nil — поле не меняется.
+type UserUpdate struct {
+	Email    *string `json:"email,omitempty" example:"alice@example.org"`
+	Name     *string `json:"name,omitempty" example:"Алиса"`
+	Password *string `json:"password,omitempty"`
+
+	// PasswordHash заполняет сервер из Password; в JSON не участвует.
+	PasswordHash *string `json:"-"`
+}
+
+// Ограничения полей учётной записи. Пароль длиннее MaxPasswordBytes bcrypt
+// не принимает.
+const (
+	MinPasswordLength = 8
+	MaxPasswordBytes  = 72
+	MaxUserNameLength = 100
+)
+
+// Empty сообщает, что в запросе нет ни одного изменяемого поля.
+func (u UserUpdate) Empty() bool {
+	return u.Email == nil && u.Name == nil && u.Password == nil
+}
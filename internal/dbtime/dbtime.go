@@ -0,0 +1,55 @@
+// Package dbtime считает время запросов к БД в пределах одного HTTP-запроса:
+// сколько их было, сколько заняли вместе и сколько — самый долгий. Нужен,
+// чтобы по записи о медленном запросе было видно, ушло ли время в базу.
+package dbtime
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Stats — время запросов к БД. Учитывается время до первого ответа сервера;
+// чтение строк результата в него не входит.
+type Stats struct {
+	Queries int
+	Total   time.Duration
+	Slowest time.Duration
+}
+
+// Recorder накапливает Stats; безопасен для параллельного использования
+// (обработчик может ходить в базу из нескольких горутин).
+type Recorder struct {
+	mu    sync.Mutex
+	stats Stats
+}
+
+// Stats возвращает накопленное на текущий момент.
+func (rec *Recorder) Stats() Stats {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.stats
+}
+
+func (rec *Recorder) observe(d time.Duration) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.stats.Queries++
+	rec.stats.Total += d
+	rec.stats.Slowest = max(rec.stats.Slowest, d)
+}
+
+type recorderKey struct{}
+
+// WithRecorder возвращает контекст, запросы с которым учитываются в новом Recorder.
+func WithRecorder(ctx context.Context) (context.Context, *Recorder) {
+	rec := &Recorder{}
+	return context.WithValue(ctx, recorderKey{}, rec), rec
+}
+
+// Observe учитывает запрос длительностью d; без Recorder в ctx ничего не делает.
+func Observe(ctx context.Context, d time.Duration) {
+	if rec, ok := ctx.Value(recorderKey{}).(*Recorder); ok {
+		rec.observe(d)
+	}
+}
@@ -0,0 +1,100 @@
+// Package dedupe схлопывает одинаковые запросы, пришедшие в коротком окне.
+package dedupe
+
+import (
+	"sync"
+	"time"
+
+	"example.com/notes-api/internal/clock"
+)
+
+type entry struct {
+	done chan struct{}
+	id   int64
+	err  error
+	at   time.Time
+}
+
+// Window помнит результаты по ключу в течение ttl.
+// Одновременные дубликаты ждут завершения первого запроса.
+type Window struct {
+	ttl   time.Duration
+	clock clock.Clock
+
+	mu        sync.Mutex
+	entries   map[string]*entry
+	lastSweep time.Time
+}
+
+// Option настраивает Window.
+type Option func(*Window)
+
+// WithClock подменяет часы, по которым отсчитывается окно.
+func WithClock(c clock.Clock) Option {
+	return func(w *Window) {
+		w.clock = c
+	}
+}
+
+// NewWindow создаёт окно дедупликации длиной ttl.
+func NewWindow(ttl time.Duration, opts ...Option) *Window {
+	w := &Window{
+		ttl:     ttl,
+		clock:   clock.System,
+		entries: make(map[string]*entry),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Do выполняет fn один раз для key в пределах окна и возвращает его результат.
+// dup = true, если результат взят у более раннего запроса.
+// Неудачный вызов не запоминается, чтобы повтор мог пройти.
+func (w *Window) Do(key string, fn func() (int64, error)) (id int64, dup bool, err error) {
+	now := w.clock.Now()
+
+	w.mu.Lock()
+	w.sweep(now)
+	if e, ok := w.entries[key]; ok && now.Sub(e.at) < w.ttl {
+		w.mu.Unlock()
+		<-e.done
+		if e.err == nil {
+			return e.id, true, nil
+		}
+		return w.Do(key, fn)
+	}
+	e := &entry{done: make(chan struct{}), at: now}
+	w.entries[key] = e
+	w.mu.Unlock()
+
+	e.id, e.err = fn()
+	if e.err != nil {
+		w.mu.Lock()
+		if w.entries[key] == e {
+			delete(w.entries, key)
+		}
+		w.mu.Unlock()
+	}
+	close(e.done)
+
+	return e.id, false, e.err
+}
+
+// sweep удаляет устаревшие записи не чаще раза в ttl.
+func (w *Window) sweep(now time.Time) {
+	if now.Sub(w.lastSweep) < w.ttl {
+		return
+	}
+	w.lastSweep = now
+	for key, e := range w.entries {
+		select {
+		case <-e.done:
+			if now.Sub(e.at) >= w.ttl {
+				delete(w.entries, key)
+			}
+		default:
+		}
+	}
+}
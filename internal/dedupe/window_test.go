@@ -0,0 +1,55 @@
+package dedupe
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"example.com/notes-api/internal/clock"
+)
+
+func TestWindow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	w := NewWindow(time.Minute, WithClock(clock.Func(func() time.Time { return now })))
+
+	var calls int64
+	create := func() (int64, error) {
+		calls++
+		return calls, nil
+	}
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		key     string
+		wantID  int64
+		wantDup bool
+	}{
+		{"first call", 0, "a", 1, false},
+		{"repeat inside window", 30 * time.Second, "a", 1, true},
+		{"other key", 0, "b", 2, false},
+		{"just before expiry", 29*time.Second + 999*time.Millisecond, "a", 1, true},
+		{"window expired", time.Millisecond, "a", 3, false},
+		{"new window", 59 * time.Second, "a", 3, true},
+	}
+	for _, tt := range tests {
+		now = now.Add(tt.advance)
+		id, dup, err := w.Do(tt.key, create)
+		if err != nil || id != tt.wantID || dup != tt.wantDup {
+			t.Errorf("%s: Do = %d, %v, %v; want %d, %v, nil", tt.name, id, dup, err, tt.wantID, tt.wantDup)
+		}
+	}
+}
+
+func TestWindowForgetsFailures(t *testing.T) {
+	w := NewWindow(time.Minute, WithClock(clock.Func(func() time.Time { return time.Unix(0, 0) })))
+
+	failed := errors.New("db is down")
+	if _, _, err := w.Do("a", func() (int64, error) { return 0, failed }); !errors.Is(err, failed) {
+		t.Fatalf("err = %v, want %v", err, failed)
+	}
+	id, dup, err := w.Do("a", func() (int64, error) { return 7, nil })
+	if err != nil || id != 7 || dup {
+		t.Errorf("retry after failure = %d, %v, %v; want 7, false, nil", id, dup, err)
+	}
+}
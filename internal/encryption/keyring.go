@@ -0,0 +1,108 @@
+// Package encryption шифрует содержимое заметок при хранении (AES-256-GCM).
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownKey — значение зашифровано ключом, которого нет в связке.
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// Keyring хранит ключи данных по ID. Новые записи шифруются текущим ключом,
+// старые ключи нужны только для чтения до перешифрования.
+type Keyring struct {
+	current string
+	aeads   map[string]cipher.AEAD
+}
+
+// NewKeyring создаёт связку ключей; каждый ключ — 32 байта, current должен быть в keys.
+func NewKeyring(keys map[string][]byte, current string) (*Keyring, error) {
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("current key %q is not in the keyring", current)
+	}
+
+	k := &Keyring{current: current, aeads: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("key %q must be 32 bytes, got %d", id, len(key))
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		k.aeads[id] = aead
+	}
+	return k, nil
+}
+
+// ParseKeys разбирает строку вида "k1:<base64>,k2:<base64>".
+func ParseKeys(spec string) (map[string][]byte, error) {
+	keys := map[string][]byte{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(part, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("invalid key entry %q, want id:base64", part)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		keys[id] = key
+	}
+	return keys, nil
+}
+
+// CurrentKeyID возвращает ID ключа, которым шифруются новые записи.
+func (k *Keyring) CurrentKeyID() string {
+	return k.current
+}
+
+// Encrypt шифрует текст текущим ключом; результат — base64(nonce || ciphertext).
+func (k *Keyring) Encrypt(plaintext string) (sealed, keyID string, err error) {
+	aead := k.aeads[k.current]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", "", err
+	}
+
+	out := aead.Seal(nonce, nonce, []byte(plaintext), []byte(k.current))
+	return base64.StdEncoding.EncodeToString(out), k.current, nil
+}
+
+// Decrypt расшифровывает значение, сохранённое Encrypt ключом keyID.
+func (k *Keyring) Decrypt(sealed, keyID string) (string, error) {
+	aead, ok := k.aeads[keyID]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	if len(raw) < aead.NonceSize() {
+		return "", errors.New("ciphertext too short")
+	}
+
+	nonce, ciphertext := raw[:aead.NonceSize()], raw[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
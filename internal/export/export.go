@@ -0,0 +1,107 @@
+// Package export сериализует заметки в файлы для переноса в другие программы
+// (Obsidian, Logseq, Emacs).
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"example.com/notes-api/internal/core"
+)
+
+// Форматы экспорта.
+const (
+	Markdown = "md"
+	Org      = "org"
+)
+
+// Valid сообщает, поддерживается ли формат.
+func Valid(format string) bool {
+	return format == Markdown || format == Org
+}
+
+// ContentType — MIME-тип файла формата.
+func ContentType(format string) string {
+	if format == Org {
+		return "text/org; charset=utf-8"
+	}
+	return "text/markdown; charset=utf-8"
+}
+
+// Filename — имя файла заметки: slug или note-<id>, с расширением формата.
+func Filename(n core.Note, format string) string {
+	name := n.Slug
+	if name == "" {
+		name = fmt.Sprintf("note-%d", n.ID)
+	}
+	return name + "." + format
+}
+
+// Render сериализует заметку в формат с front-matter (заголовок и даты).
+// У зашифрованных на клиенте заметок текст не выгружается.
+func Render(n core.Note, format string) []byte {
+	if format == Org {
+		return renderOrg(n)
+	}
+	return renderMarkdown(n)
+}
+
+func renderMarkdown(n core.Note) []byte {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "title: %s\n", yamlString(n.Title))
+	fmt.Fprintf(&b, "created: %s\n", n.CreatedAt.UTC().Format(time.RFC3339))
+	if n.UpdatedAt != nil {
+		fmt.Fprintf(&b, "updated: %s\n", n.UpdatedAt.UTC().Format(time.RFC3339))
+	}
+	if n.Encrypted {
+		b.WriteString("encrypted: true\n")
+	}
+	b.WriteString("---\n\n")
+	if !n.Encrypted {
+		b.WriteString(n.Content)
+		if !strings.HasSuffix(n.Content, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+func renderOrg(n core.Note) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#+TITLE: %s\n", oneLine(n.Title))
+	fmt.Fprintf(&b, "#+DATE: %s\n", orgTimestamp(n.CreatedAt))
+	b.WriteString(":PROPERTIES:\n")
+	fmt.Fprintf(&b, ":CREATED: %s\n", orgTimestamp(n.CreatedAt))
+	if n.UpdatedAt != nil {
+		fmt.Fprintf(&b, ":UPDATED: %s\n", orgTimestamp(*n.UpdatedAt))
+	}
+	if n.Encrypted {
+		b.WriteString(":ENCRYPTED: t\n")
+	}
+	b.WriteString(":END:\n\n")
+	if !n.Encrypted {
+		b.WriteString(n.Content)
+		if !strings.HasSuffix(n.Content, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+// yamlString — строка в двойных кавычках; JSON-экранирование допустимо в YAML.
+func yamlString(s string) string {
+	out, _ := json.Marshal(s)
+	return string(out)
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// orgTimestamp — неактивная метка времени Org: [2025-01-31 Fri 14:05].
+func orgTimestamp(t time.Time) string {
+	return t.UTC().Format("[2006-01-02 Mon 15:04]")
+}
@@ -0,0 +1,124 @@
+// Package health — проверки состояния сервиса: /health опрашивает
+// зависимости (БД, брокер) и сообщает версию сборки, /health/live отвечает
+// сразу, без ввода-вывода, — для liveness-проб оркестратора.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// version задаётся при сборке:
+//
+//	go build -ldflags "-X example.com/notes-api/internal/health.version=1.4.0" ./cmd/api
+var version string
+
+// Version — версия сборки: из -ldflags, иначе ревизия git из информации о
+// сборке, иначе "dev".
+func Version() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" && len(s.Value) >= 12 {
+				return s.Value[:12]
+			}
+		}
+	}
+	return "dev"
+}
+
+// Статусы сервиса и компонентов.
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusDown     = "down"
+)
+
+// checkTimeout — сколько ждать ответа одной зависимости.
+const checkTimeout = 2 * time.Second
+
+// Check — проверка одной зависимости.
+type Check struct {
+	Name string
+	Ping func(ctx context.Context) error
+}
+
+// Component — результат проверки зависимости.
+type Component struct {
+	Name      string  `json:"name" example:"db"`
+	Status    string  `json:"status" example:"ok"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report — ответ /health.
+type Report struct {
+	Status     string      `json:"status" example:"ok"`
+	Version    string      `json:"version" example:"1.4.0"`
+	Components []Component `json:"components"`
+}
+
+// Handler опрашивает checks параллельно. Если хоть одна зависимость не
+// ответила, статус — degraded и код ответа 503.
+func Handler(checks ...Check) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := Report{Status: StatusOK, Version: Version(), Components: make([]Component, len(checks))}
+
+		var wg sync.WaitGroup
+		for i, c := range checks {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				report.Components[i] = run(r.Context(), c)
+			}()
+		}
+		wg.Wait()
+
+		status := http.StatusOK
+		for _, c := range report.Components {
+			if c.Status != StatusOK {
+				report.Status = StatusDegraded
+				status = http.StatusServiceUnavailable
+			}
+		}
+		respond(w, status, report)
+	}
+}
+
+func run(ctx context.Context, c Check) Component {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	started := time.Now()
+	err := c.Ping(ctx)
+	comp := Component{
+		Name:      c.Name,
+		Status:    StatusOK,
+		LatencyMS: float64(time.Since(started).Microseconds()) / 1000,
+	}
+	if err != nil {
+		comp.Status = StatusDown
+		comp.Error = err.Error()
+	}
+	return comp
+}
+
+// Live отвечает {"status": "ok"}, ничего не проверяя: процесс жив и
+// обрабатывает запросы.
+func Live(w http.ResponseWriter, r *http.Request) {
+	respond(w, http.StatusOK, map[string]string{"status": StatusOK})
+}
+
+func respond(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
@@ -0,0 +1,72 @@
+package httpx
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"example.com/notes-api/internal/http/handlers"
+	"example.com/notes-api/internal/i18n"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// defaultMaxDecompressedBody — предел распакованного тела, если в Config не задан свой.
+const defaultMaxDecompressedBody = 10 << 20
+
+// decompressBody распаковывает тела запросов с Content-Encoding: gzip.
+// Распакованное тело ограничено limit байтами, чтобы маленький архив не
+// превратился в гигабайты в памяти; при превышении обработчик получает
+// ошибку чтения. Другие кодировки отклоняются с 415.
+func decompressBody(limit int64) func(http.Handler) http.Handler {
+	if limit <= 0 {
+		limit = defaultMaxDecompressedBody
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding"))) {
+			case "", "identity":
+				next.ServeHTTP(w, r)
+				return
+			case "gzip", "x-gzip":
+			default:
+				respondError(w, r, http.StatusUnsupportedMediaType, handlers.CodeUnsupportedEncoding, "Unsupported Content-Encoding")
+				return
+			}
+
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				respondError(w, r, http.StatusBadRequest, handlers.CodeInvalidBody, "Invalid gzip body")
+				return
+			}
+			defer zr.Close()
+
+			r.Body = http.MaxBytesReader(w, gzipBody{Reader: zr, orig: r.Body}, limit)
+			r.Header.Del("Content-Encoding")
+			r.Header.Del("Content-Length")
+			r.ContentLength = -1
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// gzipBody читает распакованные данные и закрывает исходное тело.
+type gzipBody struct {
+	io.Reader
+	orig io.Closer
+}
+
+func (b gzipBody) Close() error { return b.orig.Close() }
+
+// respondError пишет ошибку в формате handlers.ErrorResponse.
+func respondError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	w.Header().Add("Vary", "Accept-Language")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(handlers.ErrorResponse{
+		Error:     i18n.Message(r, message),
+		Code:      code,
+		RequestID: middleware.GetReqID(r.Context()),
+	})
+}
@@ -0,0 +1,60 @@
+package httpx_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"testing"
+
+	httpx "example.com/notes-api/internal/http"
+	"example.com/notes-api/internal/http/handlers"
+	"example.com/notes-api/internal/testutil"
+)
+
+func gzipped(t *testing.T, b []byte) string {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestGzipRequestBody(t *testing.T) {
+	s := testutil.NewServer(t, &handlers.Handler{}, httpx.Config{MaxDecompressedBody: 1 << 20})
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     string
+		status   int
+		code     string
+	}{
+		// Тело без заголовка доходит до валидации — значит, распаковано
+		{"gzip decoded", "gzip", gzipped(t, []byte(`{"content":"x"}`)), http.StatusBadRequest, "title_required"},
+		{"gzip invalid json", "gzip", gzipped(t, []byte(`{`)), http.StatusBadRequest, "invalid_json"},
+		{"not gzip", "gzip", `{"title":"a"}`, http.StatusBadRequest, "invalid_body"},
+		{"over limit", "gzip", gzipped(t, make([]byte, 2<<20)), http.StatusBadRequest, "invalid_body"},
+		{"unsupported encoding", "br", `{}`, http.StatusUnsupportedMediaType, "unsupported_encoding"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := s.Request(http.MethodPost, "/api/v1/notes").
+				Body(tt.body).
+				Header("Content-Encoding", tt.encoding).
+				Do(t)
+			resp.AssertStatus(t, tt.status)
+
+			var body handlers.ErrorResponse
+			resp.Decode(t, &body)
+			if body.Code != tt.code {
+				t.Errorf("code = %q, want %q", body.Code, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"example.com/notes-api/internal/core"
+)
+
+const (
+	defaultActivityLimit = 50
+	maxActivityLimit     = 200
+)
+
+type ActivityResponse struct {
+	Items []core.ActivityEntry `json:"items"`
+	// NextBefore — значение before для следующей страницы; 0, если страниц больше нет.
+	NextBefore int64 `json:"next_before"`
+}
+
+/*
+====================
+ACTIVITY FEED
+====================
+*/
+
+// ListActivity godoc
+// @Summary      Лента активности
+// @Description  Последние действия над заметками из notes_log, от новых к старым.
+// @Tags         activity
+// @Produce      json
+// @Param        before  query    int  false  "Вернуть записи с ID меньше before"
+// @Param        limit   query    int  false  "Размер страницы (по умолчанию 50, максимум 200)"
+// @Success      200     {object} ActivityResponse
+// @Failure      400     {object} ErrorResponse
+// @Failure      500     {object} ErrorResponse
+// @Router       /activity [get]
+func (h *Handler) ListActivity(w http.ResponseWriter, r *http.Request) {
+	var before int64
+	if s := r.URL.Query().Get("before"); s != "" {
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || v < 0 {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid before")
+			return
+		}
+		before = v
+	}
+
+	limit := defaultActivityLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid limit")
+			return
+		}
+		limit = min(v, maxActivityLimit)
+	}
+
+	items, err := h.Repo.ListActivity(r.Context(), before, limit)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to list activity")
+		return
+	}
+
+	resp := ActivityResponse{Items: items}
+	if len(items) == limit {
+		resp.NextBefore = items[len(items)-1].ID
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
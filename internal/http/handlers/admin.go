@@ -0,0 +1,386 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"example.com/notes-api/internal/backup"
+	"example.com/notes-api/internal/changes"
+	"example.com/notes-api/internal/core"
+	"github.com/go-chi/chi/v5"
+)
+
+/*
+====================
+ADMIN: RETENTION PREVIEW
+====================
+*/
+
+// PreviewRetention godoc
+// @Summary      Предпросмотр правил хранения
+// @Description  Показывает, сколько строк затронет каждое настроенное правило, ничего не меняя.
+// @Tags         admin
+// @Produce      json
+// @Security     AdminToken
+// @Success      200  {array}  core.RetentionPreview
+// @Failure      403  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /admin/retention/preview [get]
+func (h *Handler) PreviewRetention(w http.ResponseWriter, r *http.Request) {
+	if h.Retention == nil {
+		respondWithJSON(w, http.StatusOK, []core.RetentionPreview{})
+		return
+	}
+
+	previews, err := h.Retention.Preview(r.Context())
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to preview retention")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, previews)
+}
+
+// RunRetention godoc
+// @Summary      Применить правила хранения сейчас
+// @Description  Не дожидаясь расписания. С dry_run=true правила применяются в транзакции,
+// @Description  которая откатывается: число строк точное, но ничего не удаляется.
+// @Tags         admin
+// @Produce      json
+// @Security     AdminToken
+// @Param        dry_run  query  bool  false  "Пробный запуск"
+// @Success      200  {array}  core.RetentionResult
+// @Failure      400  {object} ErrorResponse
+// @Failure      403  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse
+// @Failure      501  {object} ErrorResponse
+// @Router       /admin/retention/run [post]
+func (h *Handler) RunRetention(w http.ResponseWriter, r *http.Request) {
+	dry, ok := dryRun(w, r)
+	if !ok {
+		return
+	}
+	if h.Retention == nil {
+		respondWithError(w, r, http.StatusNotImplemented, CodeNotConfigured, "Retention is not configured")
+		return
+	}
+
+	var results []core.RetentionResult
+	err := h.apply(w, r, dry, func(ctx context.Context) error {
+		var err error
+		results, err = h.Retention.Apply(ctx)
+		return err
+	})
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to apply retention")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, results)
+}
+
+// TableStats godoc
+// @Summary      Размер таблиц
+// @Description  Оценка числа строк и размер notes и notes_log — чтобы подобрать правила хранения.
+// @Tags         admin
+// @Produce      json
+// @Security     AdminToken
+// @Success      200  {array}  core.TableStats
+// @Failure      403  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /admin/retention/tables [get]
+func (h *Handler) TableStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.Repo.TableStats(r.Context())
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get table stats")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, stats)
+}
+
+/*
+====================
+ADMIN: LEGAL HOLD
+====================
+*/
+
+// PlaceLegalHold godoc
+// @Summary      Поставить заметку на юридическое удержание
+// @Description  Удерживаемая заметка не удаляется по сроку жизни, правилами хранения и через DELETE.
+// @Tags         admin
+// @Produce      json
+// @Security     AdminToken
+// @Param        id   path   int  true  "ID"
+// @Param        X-Lock-Owner  header  string  false  "Владелец блокировки"
+// @Success      200  {object} AdminNoteResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      403  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Failure      423  {object} LockedResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /admin/notes/{id}/hold [post]
+func (h *Handler) PlaceLegalHold(w http.ResponseWriter, r *http.Request) {
+	h.setLegalHold(w, r, true)
+}
+
+// ReleaseLegalHold godoc
+// @Summary      Снять юридическое удержание
+// @Tags         admin
+// @Produce      json
+// @Security     AdminToken
+// @Param        id   path   int  true  "ID"
+// @Param        X-Lock-Owner  header  string  false  "Владелец блокировки"
+// @Success      200  {object} AdminNoteResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      403  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Failure      423  {object} LockedResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /admin/notes/{id}/hold [delete]
+func (h *Handler) ReleaseLegalHold(w http.ResponseWriter, r *http.Request) {
+	h.setLegalHold(w, r, false)
+}
+
+func (h *Handler) setLegalHold(w http.ResponseWriter, r *http.Request, hold bool) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
+		return
+	}
+
+	if !h.checkLock(w, r, id) {
+		return
+	}
+	if err := h.Repo.SetLegalHold(r.Context(), id, hold); err != nil {
+		if respondNotFound(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to update legal hold")
+		return
+	}
+
+	note, err := h.Repo.GetAnyByID(r.Context(), id)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get note")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, newAdminNoteResponse(note))
+}
+
+/*
+====================
+ADMIN: ALL NOTES
+====================
+*/
+
+type AdminNotesResponse struct {
+	Items []AdminNoteResponse `json:"items"`
+	// NextBefore — значение before для следующей страницы; 0, если страниц больше нет.
+	NextBefore int64 `json:"next_before"`
+}
+
+// AdminListNotes godoc
+// @Summary      Все заметки, включая удалённые и истёкшие
+// @Description  Для восстановления по обращениям в поддержку. От новых к старым.
+// @Tags         admin
+// @Produce      json
+// @Security     AdminToken
+// @Param        before  query    int  false  "Вернуть заметки с ID меньше before"
+// @Param        limit   query    int  false  "Размер страницы (по умолчанию 50, максимум 200)"
+// @Success      200     {object} AdminNotesResponse
+// @Failure      400     {object} ErrorResponse
+// @Failure      403     {object} ErrorResponse
+// @Failure      500     {object} ErrorResponse
+// @Router       /admin/notes [get]
+func (h *Handler) AdminListNotes(w http.ResponseWriter, r *http.Request) {
+	var before int64
+	if s := r.URL.Query().Get("before"); s != "" {
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || v < 0 {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid before")
+			return
+		}
+		before = v
+	}
+
+	limit := defaultActivityLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid limit")
+			return
+		}
+		limit = min(v, maxActivityLimit)
+	}
+
+	notes, err := h.Repo.ListAllNotes(r.Context(), before, limit)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to list notes")
+		return
+	}
+
+	resp := AdminNotesResponse{Items: newAdminNoteResponses(notes)}
+	if len(notes) == limit {
+		resp.NextBefore = notes[len(notes)-1].ID
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// AdminGetNote godoc
+// @Summary      Заметка по ID, включая удалённые и истёкшие
+// @Tags         admin
+// @Produce      json
+// @Security     AdminToken
+// @Param        id   path   int  true  "ID"
+// @Success      200  {object} AdminNoteResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      403  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /admin/notes/{id} [get]
+func (h *Handler) AdminGetNote(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
+		return
+	}
+
+	note, err := h.Repo.GetAnyByID(r.Context(), id)
+	if err != nil {
+		if respondNotFound(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get note")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, newAdminNoteResponse(note))
+}
+
+// RestoreNote godoc
+// @Summary      Восстановить удалённую или истёкшую заметку
+// @Description  Снимает пометку удаления и прошедший срок жизни. Вычищенные заметки не восстанавливаются.
+// @Description  С dry_run=true возвращает заметку такой, какой она стала бы, ничего не меняя.
+// @Tags         admin
+// @Produce      json
+// @Security     AdminToken
+// @Param        id   path   int  true  "ID"
+// @Param        dry_run  query  bool  false  "Пробный запуск"
+// @Param        X-Lock-Owner  header  string  false  "Владелец блокировки"
+// @Success      200  {object} AdminNoteResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      403  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Failure      409  {object} TitleTakenResponse  "Заголовок занят другой заметкой"
+// @Failure      423  {object} LockedResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /admin/notes/{id}/restore [post]
+func (h *Handler) RestoreNote(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
+		return
+	}
+
+	dry, ok := dryRun(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkLock(w, r, id) {
+		return
+	}
+
+	var note *core.Note
+	err = h.apply(w, r, dry, func(ctx context.Context) error {
+		if err := h.Repo.Restore(ctx, id); err != nil {
+			return err
+		}
+		note, err = h.Repo.GetByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		if respondTitleTaken(w, r, err) || respondNotFound(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to restore note")
+		return
+	}
+
+	if !dry {
+		h.publish(id, changes.NoteCreated)
+	}
+	respondWithJSON(w, http.StatusOK, newAdminNoteResponse(note))
+}
+
+/*
+====================
+ADMIN: BACKUPS
+====================
+*/
+
+type BackupsResponse struct {
+	// LastSuccess — время последней успешной копии с запуска сервера.
+	LastSuccess *time.Time    `json:"last_success,omitempty"`
+	Backups     []backup.Info `json:"backups"`
+}
+
+type BackupResponse struct {
+	Backup backup.Info `json:"backup"`
+	Notes  int         `json:"notes"`
+}
+
+// ListBackups godoc
+// @Summary      Резервные копии
+// @Description  Файлы копий в BACKUP_DIR от новых к старым и время последней успешной копии.
+// @Tags         admin
+// @Produce      json
+// @Security     AdminToken
+// @Success      200  {object} BackupsResponse
+// @Failure      403  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse
+// @Failure      501  {object} ErrorResponse
+// @Router       /admin/backups [get]
+func (h *Handler) ListBackups(w http.ResponseWriter, r *http.Request) {
+	if h.Backups == nil {
+		respondWithError(w, r, http.StatusNotImplemented, CodeNotConfigured, "Backups are not configured")
+		return
+	}
+
+	list, err := h.Backups.List()
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to list backups")
+		return
+	}
+
+	resp := BackupsResponse{Backups: list}
+	if t := h.Backups.LastSuccess(); !t.IsZero() {
+		resp.LastSuccess = &t
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// CreateBackup godoc
+// @Summary      Сделать резервную копию сейчас
+// @Tags         admin
+// @Produce      json
+// @Security     AdminToken
+// @Success      201  {object} BackupResponse
+// @Failure      403  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse
+// @Failure      501  {object} ErrorResponse
+// @Router       /admin/backups [post]
+func (h *Handler) CreateBackup(w http.ResponseWriter, r *http.Request) {
+	if h.Backups == nil {
+		respondWithError(w, r, http.StatusNotImplemented, CodeNotConfigured, "Backups are not configured")
+		return
+	}
+
+	info, count, err := h.Backups.Backup(r.Context())
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to create backup")
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, BackupResponse{Backup: info, Notes: count})
+}
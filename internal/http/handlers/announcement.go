@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"example.com/notes-api/internal/changes"
+	"example.com/notes-api/internal/core"
+	"github.com/go-chi/chi/v5"
+)
+
+/*
+====================
+ADMIN: ANNOUNCEMENTS
+====================
+*/
+
+// AnnounceNote godoc
+// @Summary      Сделать заметку объявлением
+// @Description  Объявление закреплено над списком заметок у всех пользователей, пока идёт окно показа [from, until).
+// @Description  Пустые границы — без ограничения. Повторный вызов меняет окно.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        id     path  int                    true  "ID"
+// @Param        input  body  core.NoteAnnouncement  true  "Окно показа"
+// @Param        X-Lock-Owner  header  string  false  "Владелец блокировки"
+// @Success      200  {object} AdminNoteResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      403  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Failure      423  {object} LockedResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /admin/notes/{id}/announcement [put]
+func (h *Handler) AnnounceNote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
+		return
+	}
+
+	var a core.NoteAnnouncement
+	if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidJSON, "Invalid JSON")
+		return
+	}
+	if a.From != nil && a.Until != nil && !a.Until.After(*a.From) {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidAnnouncement, "until must be after from")
+		return
+	}
+	if a.Until != nil && !a.Until.After(h.now()) {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidAnnouncement, "until must be in the future")
+		return
+	}
+
+	h.setAnnouncement(w, r, id, &a)
+}
+
+// UnannounceNote godoc
+// @Summary      Снять объявление
+// @Tags         admin
+// @Produce      json
+// @Security     AdminToken
+// @Param        id   path   int  true  "ID"
+// @Param        X-Lock-Owner  header  string  false  "Владелец блокировки"
+// @Success      200  {object} AdminNoteResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      403  {object} ErrorResponse
+// @Failure      423  {object} LockedResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /admin/notes/{id}/announcement [delete]
+func (h *Handler) UnannounceNote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
+		return
+	}
+	h.setAnnouncement(w, r, id, nil)
+}
+
+func (h *Handler) setAnnouncement(w http.ResponseWriter, r *http.Request, id int64, a *core.NoteAnnouncement) {
+	if !h.checkLock(w, r, id) {
+		return
+	}
+	if err := h.Repo.SetAnnouncement(r.Context(), id, a); err != nil {
+		if respondNotFound(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to update announcement")
+		return
+	}
+
+	note, err := h.Repo.GetAnyByID(r.Context(), id)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get note")
+		return
+	}
+	h.publish(id, changes.NoteUpdated)
+	respondWithJSON(w, http.StatusOK, newAdminNoteResponse(note))
+}
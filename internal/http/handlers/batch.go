@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"example.com/notes-api/internal/changes"
+	"example.com/notes-api/internal/core"
+	"example.com/notes-api/internal/i18n"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// maxBatchItems — сколько заметок можно изменить одним пакетным PATCH.
+const maxBatchItems = 100
+
+// errBatchAborted откатывает транзакцию атомарного пакета после первой ошибки.
+var errBatchAborted = errors.New("batch aborted")
+
+// NoteBatchItem — изменение одной заметки в пакетном PATCH.
+type NoteBatchItem struct {
+	ID      int64           `json:"id" example:"1"`
+	Changes core.NoteUpdate `json:"changes"`
+}
+
+// NoteBatchResult — итог изменения одной заметки: HTTP-статус, который
+// вернул бы PATCH /notes/{id}, и заметка либо ошибка.
+type NoteBatchResult struct {
+	ID     int64          `json:"id" example:"1"`
+	Status int            `json:"status" example:"200"`
+	Note   *NoteResponse  `json:"note,omitempty"`
+	Error  *ErrorResponse `json:"error,omitempty"`
+}
+
+// NoteBatchResponse — ответ пакетного PATCH; результаты в порядке запроса.
+type NoteBatchResponse struct {
+	Atomic  bool              `json:"atomic"`
+	Results []NoteBatchResult `json:"results"`
+}
+
+/*
+====================
+BATCH PATCH NOTES
+====================
+*/
+
+// PatchNotes godoc
+// @Summary      Изменить несколько заметок
+// @Description  Принимает массив {id, changes} (не больше 100), каждое изменение проверяется как в PATCH /notes/{id}.
+// @Description  По умолчанию пакет атомарный: при первой ошибке всё откатывается, ответ получает статус
+// @Description  этой ошибки, а остальные элементы — 424. С atomic=false элементы применяются независимо.
+// @Tags         notes
+// @Accept       json
+// @Produce      json
+// @Param        input   body   []NoteBatchItem  true   "Изменения"
+// @Param        atomic  query  bool             false  "Всё или ничего (по умолчанию true)"
+// @Param        X-Lock-Owner  header  string    false  "Владелец блокировки"
+// @Success      200  {object} NoteBatchResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      409  {object} NoteBatchResponse
+// @Failure      423  {object} NoteBatchResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /notes [patch]
+func (h *Handler) PatchNotes(w http.ResponseWriter, r *http.Request) {
+	atomic := true
+	if s := r.URL.Query().Get("atomic"); s != "" {
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid atomic")
+			return
+		}
+		atomic = v
+	}
+
+	var items []NoteBatchItem
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidJSON, "Invalid JSON")
+		return
+	}
+	if len(items) == 0 {
+		respondWithError(w, r, http.StatusBadRequest, CodeNoFields, "No notes to update")
+		return
+	}
+	if len(items) > maxBatchItems {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Too many notes in batch")
+		return
+	}
+
+	resp := NoteBatchResponse{Atomic: atomic, Results: make([]NoteBatchResult, len(items))}
+
+	if !atomic {
+		for i, item := range items {
+			resp.Results[i] = h.patchBatchItem(r.Context(), r, item)
+			if resp.Results[i].Status == http.StatusOK {
+				h.publish(item.ID, changes.NoteUpdated)
+			}
+		}
+		respondWithJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	failed := -1
+	err := h.Repo.WithinTx(r.Context(), func(ctx context.Context) error {
+		for i, item := range items {
+			resp.Results[i] = h.patchBatchItem(ctx, r, item)
+			if resp.Results[i].Status != http.StatusOK {
+				failed = i
+				return errBatchAborted
+			}
+		}
+		return nil
+	})
+	if err != nil && failed < 0 {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to update note")
+		return
+	}
+
+	if failed >= 0 {
+		for i := range resp.Results {
+			if i != failed {
+				resp.Results[i] = batchError(r, items[i].ID, http.StatusFailedDependency,
+					CodeBatchAborted, "Not applied: another note in the batch failed")
+			}
+		}
+		respondWithJSON(w, resp.Results[failed].Status, resp)
+		return
+	}
+
+	for _, item := range items {
+		h.publish(item.ID, changes.NoteUpdated)
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// patchBatchItem применяет одно изменение пакета с теми же проверками, что
+// PatchNote. В атомарном режиме ctx несёт транзакцию пакета.
+func (h *Handler) patchBatchItem(ctx context.Context, r *http.Request, item NoteBatchItem) NoteBatchResult {
+	update := item.Changes
+	if update.Empty() {
+		return batchError(r, item.ID, http.StatusBadRequest, CodeNoFields, "No fields to update")
+	}
+	if code, msg := h.validateUpdate(update); code != "" {
+		return batchError(r, item.ID, validationStatus(code), code, msg)
+	}
+
+	current, err := h.Repo.GetByID(ctx, item.ID)
+	if errors.Is(err, core.ErrNotFound) {
+		return batchError(r, item.ID, http.StatusNotFound, CodeNoteNotFound, "Note not found")
+	}
+	if err != nil {
+		return batchError(r, item.ID, http.StatusInternalServerError, CodeInternal, "Failed to get note")
+	}
+	if msg := validateEncryptedUpdate(current, update); msg != "" {
+		return batchError(r, item.ID, http.StatusBadRequest, CodeInvalidEncryption, msg)
+	}
+
+	lock, err := h.lockConflict(ctx, r, item.ID)
+	if err != nil {
+		return batchError(r, item.ID, http.StatusInternalServerError, CodeInternal, "Failed to check note lock")
+	}
+	if lock != nil {
+		return batchError(r, item.ID, http.StatusLocked, CodeNoteLocked, "Note is locked")
+	}
+
+	if err := h.Repo.Update(ctx, item.ID, update); err != nil {
+		if errors.Is(err, core.ErrVersionConflict) {
+			return batchError(r, item.ID, http.StatusConflict, CodeVersionConflict, "Note was modified on the server")
+		}
+		if errors.Is(err, core.ErrTitleTaken) {
+			return batchError(r, item.ID, http.StatusConflict, CodeTitleTaken, "Title is already taken")
+		}
+		if errors.Is(err, core.ErrNotFound) {
+			return batchError(r, item.ID, http.StatusNotFound, CodeNoteNotFound, "Note not found")
+		}
+		return batchError(r, item.ID, http.StatusInternalServerError, CodeInternal, "Failed to update note")
+	}
+
+	note, err := h.Repo.GetByID(ctx, item.ID)
+	if err != nil {
+		return batchError(r, item.ID, http.StatusInternalServerError, CodeInternal, "Failed to retrieve updated note")
+	}
+	resp := newNoteResponse(note)
+	return NoteBatchResult{ID: item.ID, Status: http.StatusOK, Note: &resp}
+}
+
+func batchError(r *http.Request, id int64, status int, code, message string) NoteBatchResult {
+	return NoteBatchResult{
+		ID:     id,
+		Status: status,
+		Error: &ErrorResponse{
+			Error:     i18n.Message(r, message),
+			Code:      code,
+			RequestID: middleware.GetReqID(r.Context()),
+		},
+	}
+}
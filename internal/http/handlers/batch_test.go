@@ -0,0 +1,71 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	httpx "example.com/notes-api/internal/http"
+	"example.com/notes-api/internal/http/handlers"
+	"example.com/notes-api/internal/testutil"
+)
+
+func TestPatchNotesBatch(t *testing.T) {
+	s := testutil.NewMemoryServer(t, httpx.Config{AdminToken: adminToken})
+
+	runGolden(t, s, []goldenStep{
+		{"create", http.MethodPost, "/api/v1/notes", `{"title":"Покупки"}`, http.StatusCreated, ""},
+		{"create second", http.MethodPost, "/api/v1/notes", `{"title":"Работа"}`, http.StatusCreated, ""},
+		{"atomic", http.MethodPatch, "/api/v1/notes", `[{"id":1,"changes":{"color":"yellow"}},{"id":2,"changes":{"icon":"todo"}}]`, http.StatusOK, "batch_atomic"},
+		// Атомарный пакет с ошибкой не применяет ни одного изменения
+		{"atomic aborted", http.MethodPatch, "/api/v1/notes", `[{"id":1,"changes":{"color":"blue"}},{"id":99,"changes":{"color":"blue"}}]`, http.StatusNotFound, "batch_atomic_aborted"},
+		{"partial", http.MethodPatch, "/api/v1/notes?atomic=false", `[{"id":1,"changes":{"content":"Молоко"}},{"id":99,"changes":{"content":"x"}},{"id":2,"changes":{"base_version":1,"content":"x"}}]`, http.StatusOK, "batch_partial"},
+		{"get after batch", http.MethodGet, "/api/v1/notes/1", ``, http.StatusOK, "batch_note_after"},
+	})
+}
+
+func TestPatchNotesBatchLimits(t *testing.T) {
+	s := newServer(t)
+
+	items := make([]handlers.NoteBatchItem, 101)
+	resp := s.Request(http.MethodPatch, "/api/v1/notes").JSON(items).Do(t)
+	resp.AssertStatus(t, http.StatusBadRequest)
+
+	var body handlers.ErrorResponse
+	resp.Decode(t, &body)
+	if body.Code != "invalid_parameter" {
+		t.Errorf("code = %q, want invalid_parameter", body.Code)
+	}
+}
+
+func TestPatchNotesBatchItemValidation(t *testing.T) {
+	s := newServer(t)
+
+	// Без atomic каждый элемент проверяется отдельно; эти ошибки находятся
+	// до обращения к репозиторию
+	resp := s.Request(http.MethodPatch, "/api/v1/notes?atomic=false").Body(`[
+		{"id": 1, "changes": {}},
+		{"id": 2, "changes": {"title": " "}},
+		{"id": 3, "changes": {"color": "ultraviolet"}}
+	]`).Do(t)
+	resp.AssertStatus(t, http.StatusOK)
+
+	var body handlers.NoteBatchResponse
+	resp.Decode(t, &body)
+	if body.Atomic {
+		t.Error("atomic = true, want false")
+	}
+
+	want := []struct {
+		id   int64
+		code string
+	}{{1, "no_fields"}, {2, "title_required"}, {3, "invalid_color"}}
+	if len(body.Results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(body.Results), len(want))
+	}
+	for i, w := range want {
+		res := body.Results[i]
+		if res.ID != w.id || res.Status != http.StatusBadRequest || res.Error == nil || res.Error.Code != w.code {
+			t.Errorf("result %d = %+v, want id %d, status 400, code %s", i, res, w.id, w.code)
+		}
+	}
+}
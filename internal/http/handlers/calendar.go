@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	// maxCalendarDays — наибольший запрашиваемый диапазон.
+	maxCalendarDays = 366
+	// calendarNotesPerDay — сколько заметок дня отдавать списком.
+	calendarNotesPerDay = 20
+)
+
+/*
+====================
+CALENDAR
+====================
+*/
+
+// NotesCalendar godoc
+// @Summary      Календарь заметок
+// @Description  Заметки, созданные с from по to включительно, сгруппированные по дням.
+// @Description  Дни без заметок не возвращаются; в списке дня не больше 20 заметок.
+// @Tags         notes
+// @Produce      json
+// @Param        from  query    string  true   "Первый день, YYYY-MM-DD"
+// @Param        to    query    string  true   "Последний день, YYYY-MM-DD"
+// @Param        tz    query    string  false  "Часовой пояс IANA (по умолчанию UTC)"
+// @Success      200   {array}  core.CalendarDay
+// @Failure      400   {object} ErrorResponse
+// @Failure      500   {object} ErrorResponse
+// @Router       /notes/calendar [get]
+func (h *Handler) NotesCalendar(w http.ResponseWriter, r *http.Request) {
+	tz := r.URL.Query().Get("tz")
+	if tz == "" {
+		tz = "UTC"
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid tz")
+		return
+	}
+
+	from, err := time.ParseInLocation(time.DateOnly, r.URL.Query().Get("from"), loc)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid from")
+		return
+	}
+	to, err := time.ParseInLocation(time.DateOnly, r.URL.Query().Get("to"), loc)
+	if err != nil || to.Before(from) || to.Sub(from) >= maxCalendarDays*24*time.Hour {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid to")
+		return
+	}
+
+	days, err := h.Repo.Calendar(r.Context(), from, to.AddDate(0, 0, 1), loc.String(), calendarNotesPerDay)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to build calendar")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, days)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"example.com/notes-api/internal/changes"
+)
+
+// maxChangesWait ограничивает время удержания long-polling запроса.
+const maxChangesWait = 60 * time.Second
+
+type ChangesResponse struct {
+	Events    []changes.Event `json:"events"`
+	LastSeq   int64           `json:"last_seq"`
+	Truncated bool            `json:"truncated"`
+}
+
+/*
+====================
+LIST CHANGES (LONG POLLING)
+====================
+*/
+
+// ListChanges godoc
+// @Summary      Лента изменений заметок (long polling)
+// @Description  Возвращает события после since. Если событий нет, ждёт до wait (максимум 60s).
+// @Description  truncated=true означает, что часть событий потеряна и нужна полная синхронизация.
+// @Tags         notes
+// @Produce      json
+// @Param        since  query    int     false  "Последний полученный seq"
+// @Param        wait   query    string  false  "Время ожидания, например 30s"
+// @Success      200    {object} ChangesResponse
+// @Failure      400    {object} ErrorResponse
+// @Router       /notes/changes [get]
+func (h *Handler) ListChanges(w http.ResponseWriter, r *http.Request) {
+	var since int64
+	if s := r.URL.Query().Get("since"); s != "" {
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || v < 0 {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid since")
+			return
+		}
+		since = v
+	}
+
+	var wait time.Duration
+	if s := r.URL.Query().Get("wait"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d < 0 {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid wait")
+			return
+		}
+		wait = min(d, maxChangesWait)
+	}
+
+	if h.Changes == nil {
+		respondWithJSON(w, http.StatusOK, ChangesResponse{Events: []changes.Event{}})
+		return
+	}
+
+	var (
+		events    []changes.Event
+		last      int64
+		truncated bool
+	)
+	if wait > 0 {
+		ctx, cancel := context.WithTimeout(r.Context(), wait)
+		events, last, truncated = h.Changes.Wait(ctx, since)
+		cancel()
+	} else {
+		events, last, truncated = h.Changes.Since(since)
+	}
+
+	respondWithJSON(w, http.StatusOK, ChangesResponse{
+		Events:    events,
+		LastSeq:   last,
+		Truncated: truncated,
+	})
+}
+
+// publish записывает событие в ленту изменений, если она подключена.
+func (h *Handler) publish(noteID int64, typ string) {
+	if h.Changes != nil {
+		h.Changes.Publish(noteID, typ)
+	}
+}
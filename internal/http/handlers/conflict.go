@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"example.com/notes-api/internal/core"
+	"example.com/notes-api/internal/i18n"
+	"example.com/notes-api/internal/merge"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// TitleTakenResponse — тело ответа 409, если заголовок уже занят другой заметкой.
+type TitleTakenResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code" example:"title_taken"`
+	// RequestID совпадает с заголовком X-Request-ID.
+	RequestID string `json:"request_id,omitempty"`
+	// NoteID — заметка, которой принадлежит заголовок.
+	NoteID int64 `json:"note_id" example:"12"`
+}
+
+// respondTitleTaken отвечает 409 с ID владельца заголовка, если err —
+// core.TitleTakenError, и сообщает, был ли ответ отправлен.
+func respondTitleTaken(w http.ResponseWriter, r *http.Request, err error) bool {
+	var taken *core.TitleTakenError
+	if !errors.As(err, &taken) {
+		return false
+	}
+	w.Header().Add("Vary", "Accept-Language")
+	respondWithJSON(w, http.StatusConflict, TitleTakenResponse{
+		Error:     i18n.Message(r, "Title is already taken"),
+		Code:      CodeTitleTaken,
+		RequestID: middleware.GetReqID(r.Context()),
+		NoteID:    taken.NoteID,
+	})
+	return true
+}
+
+// ConflictResponse — тело ответа 409 при расхождении версий.
+type ConflictResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code" example:"version_conflict"`
+	// RequestID совпадает с заголовком X-Request-ID.
+	RequestID string          `json:"request_id,omitempty"`
+	Server    NoteResponse    `json:"server"`
+	Client    core.NoteUpdate `json:"client"`
+	// Merged заполняется, если клиент прислал base и правки не пересекаются.
+	Merged *core.NoteBase `json:"merged,omitempty"`
+}
+
+// respondConflict отдаёт обе версии заметки и, если возможно, результат слияния.
+func (h *Handler) respondConflict(w http.ResponseWriter, r *http.Request, id int64, update core.NoteUpdate) {
+	server, err := h.Repo.GetByID(r.Context(), id)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get note")
+		return
+	}
+
+	resp := ConflictResponse{
+		Error: i18n.Message(r, "Note was modified on the server"),
+		Code:  CodeVersionConflict,
+
+		RequestID: middleware.GetReqID(r.Context()),
+		Server:    newNoteResponse(server),
+		Client:    update,
+	}
+
+	// Шифртекст сервер слить не может — клиент решает конфликт сам.
+	if update.Base != nil && !server.Encrypted {
+		ours := *update.Base
+		if update.Title != nil {
+			ours.Title = *update.Title
+		}
+		if update.Content != nil {
+			ours.Content = *update.Content
+		}
+
+		title, okTitle := merge.ThreeWay(update.Base.Title, ours.Title, server.Title)
+		content, okContent := merge.ThreeWay(update.Base.Content, ours.Content, server.Content)
+		if okTitle && okContent {
+			resp.Merged = &core.NoteBase{Title: title, Content: content}
+		}
+	}
+
+	respondWithJSON(w, http.StatusConflict, resp)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"example.com/notes-api/internal/changes"
+	"example.com/notes-api/internal/core"
+	"github.com/go-chi/chi/v5"
+)
+
+/*
+====================
+DAILY NOTE
+====================
+*/
+
+// GetDailyNote godoc
+// @Summary      Ежедневная заметка
+// @Description  Возвращает заметку-дневник за дату. Создаётся через POST.
+// @Tags         notes
+// @Produce      json
+// @Param        date  path     string  true  "Дата, YYYY-MM-DD"
+// @Success      200   {object} NoteResponse
+// @Failure      400   {object} ErrorResponse
+// @Failure      404   {object} ErrorResponse
+// @Failure      500   {object} ErrorResponse
+// @Router       /notes/daily/{date} [get]
+func (h *Handler) GetDailyNote(w http.ResponseWriter, r *http.Request) {
+	day, ok := dailyDate(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := h.Repo.DailyNoteID(r.Context(), day)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get note")
+		return
+	}
+	if id == 0 {
+		respondWithError(w, r, http.StatusNotFound, CodeNoteNotFound, "Note not found")
+		return
+	}
+
+	note, err := h.Repo.GetByID(r.Context(), id)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get note")
+		return
+	}
+
+	h.recordView(r, id)
+	respondWithJSON(w, http.StatusOK, newNoteResponse(note))
+}
+
+// CreateDailyNote godoc
+// @Summary      Получить или создать ежедневную заметку
+// @Description  Если заметки за дату нет, создаёт её с заголовком-датой и текстом из шаблона
+// @Description  DAILY_NOTE_TEMPLATE ({date} заменяется на дату) и отвечает 201; иначе возвращает существующую.
+// @Tags         notes
+// @Produce      json
+// @Param        date  path     string  true  "Дата, YYYY-MM-DD"
+// @Success      200   {object} NoteResponse
+// @Success      201   {object} NoteResponse
+// @Failure      400   {object} ErrorResponse
+// @Failure      500   {object} ErrorResponse
+// @Router       /notes/daily/{date} [post]
+func (h *Handler) CreateDailyNote(w http.ResponseWriter, r *http.Request) {
+	day, ok := dailyDate(w, r)
+	if !ok {
+		return
+	}
+
+	id, created, err := h.Repo.CreateDaily(r.Context(), day, core.NoteCreate{
+		Title:   day,
+		Content: strings.ReplaceAll(h.DailyTemplate, "{date}", day),
+	})
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to create note")
+		return
+	}
+
+	note, err := h.Repo.GetByID(r.Context(), id)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get note")
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+		h.publish(id, changes.NoteCreated)
+	}
+	respondWithJSON(w, status, newNoteResponse(note))
+}
+
+// dailyDate разбирает {date} из пути и отвечает 400 при ошибке.
+func dailyDate(w http.ResponseWriter, r *http.Request) (string, bool) {
+	s := chi.URLParam(r, "date")
+	if _, err := time.Parse(time.DateOnly, s); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid date")
+		return "", false
+	}
+	return s, true
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strconv"
+
+	"example.com/notes-api/internal/auth"
+	"example.com/notes-api/internal/core"
+)
+
+// createOnce создаёт заметку, схлопывая повторную отправку того же тела
+// тем же клиентом в пределах окна дедупликации.
+func (h *Handler) createOnce(r *http.Request, body []byte, req core.NoteCreate) (id int64, dup bool, err error) {
+	create := func() (int64, error) {
+		return h.Repo.CreateWithLogTx(r.Context(), req)
+	}
+
+	if h.Dedupe == nil {
+		id, err := create()
+		return id, false, err
+	}
+
+	sum := sha256.Sum256(body)
+	return h.Dedupe.Do(clientKey(r)+":"+hex.EncodeToString(sum[:]), create)
+}
+
+// clientKey идентифицирует автора запроса: вошедшего пользователя — по ID
+// из токена, анонимного клиента — по IP (см. clientIP).
+func clientKey(r *http.Request) string {
+	if id, ok := auth.UserID(r.Context()); ok {
+		return "user:" + strconv.FormatInt(id, 10)
+	}
+	return clientIP(r)
+}
+
+// clientIP — IP клиента без порта.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
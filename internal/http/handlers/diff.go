@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"example.com/notes-api/internal/core"
+	"example.com/notes-api/internal/merge"
+	"github.com/go-chi/chi/v5"
+)
+
+// NoteDiffResponse — построчные отличия присланного текста от content заметки.
+type NoteDiffResponse struct {
+	NoteID int64 `json:"note_id" example:"1"`
+	// Version — версия, с которой сравнивался текст; её стоит передать
+	// в base_version при сохранении.
+	Version   int64 `json:"version" example:"3"`
+	Identical bool  `json:"identical"`
+	// Added и Removed — число добавленных и удалённых строк.
+	Added   int         `json:"added" example:"1"`
+	Removed int         `json:"removed" example:"0"`
+	Chunks  []DiffChunk `json:"chunks"`
+}
+
+// DiffChunk — участок diff: строки без изменений, добавленные или удалённые.
+// Text — строки участка как есть, с переводами строк.
+type DiffChunk struct {
+	Op   string `json:"op" example:"insert" enums:"equal,insert,delete"`
+	Text string `json:"text" example:"Новая строка\n"`
+}
+
+/*
+====================
+DIFF NOTE
+====================
+*/
+
+// DiffNote godoc
+// @Summary      Сравнить текст с заметкой
+// @Description  Показывает, что изменится в content, если сохранить присланный текст. Заметка не меняется.
+// @Tags         notes
+// @Accept       json
+// @Produce      json
+// @Param        id     path     int                   true  "ID"
+// @Param        input  body     core.NoteDiffRequest  true  "Текст для сравнения"
+// @Success      200    {object} NoteDiffResponse
+// @Failure      400    {object} ErrorResponse
+// @Failure      404    {object} ErrorResponse
+// @Failure      409    {object} ErrorResponse
+// @Failure      422    {object} ErrorResponse
+// @Failure      500    {object} ErrorResponse
+// @Router       /notes/{id}/diff [post]
+func (h *Handler) DiffNote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
+		return
+	}
+
+	var req core.NoteDiffRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidJSON, "Invalid JSON")
+		return
+	}
+	if req.Content == nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeContentRequired, "Content is required")
+		return
+	}
+	if code, msg := validateSize(nil, req.Content, nil, h.Limits); code != "" {
+		respondWithError(w, r, validationStatus(code), code, msg)
+		return
+	}
+
+	note, err := h.Repo.GetByID(r.Context(), id)
+	if err != nil {
+		if respondNotFound(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get note")
+		return
+	}
+	// Шифртекст сервер не читает, сравнивать не с чем.
+	if note.Encrypted {
+		respondWithError(w, r, http.StatusConflict, CodeNoteEncrypted, "Encrypted note cannot be compared on the server")
+		return
+	}
+
+	chunks, ok := merge.Lines(note.Content, *req.Content)
+	if !ok {
+		respondWithError(w, r, http.StatusUnprocessableEntity, CodeContentTooLarge, "Texts differ too much to compare")
+		return
+	}
+
+	resp := NoteDiffResponse{
+		NoteID:    note.ID,
+		Version:   note.Version,
+		Identical: note.Content == *req.Content,
+		Chunks:    make([]DiffChunk, len(chunks)),
+	}
+	for i, c := range chunks {
+		switch c.Op {
+		case merge.OpInsert:
+			resp.Added += len(c.Lines)
+		case merge.OpDelete:
+			resp.Removed += len(c.Lines)
+		}
+		resp.Chunks[i] = DiffChunk{Op: string(c.Op), Text: strings.Join(c.Lines, "")}
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,59 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	httpx "example.com/notes-api/internal/http"
+	"example.com/notes-api/internal/http/handlers"
+	"example.com/notes-api/internal/repo"
+	"example.com/notes-api/internal/testutil"
+)
+
+func TestDiffResponses(t *testing.T) {
+	s := testutil.NewMemoryServer(t, httpx.Config{AdminToken: adminToken})
+
+	runGolden(t, s, []goldenStep{
+		{"create", http.MethodPost, "/api/v1/notes", `{"title":"Список","content":"молоко\nхлеб\nсыр"}`, http.StatusCreated, ""},
+		{"changed", http.MethodPost, "/api/v1/notes/1/diff", `{"content":"молоко\nмасло\nсыр\nчай"}`, http.StatusOK, "diff_changed"},
+		{"identical", http.MethodPost, "/api/v1/notes/1/diff", `{"content":"молоко\nхлеб\nсыр"}`, http.StatusOK, "diff_identical"},
+		{"patch", http.MethodPatch, "/api/v1/notes/1", `{"content":"молоко\nсыр"}`, http.StatusOK, ""},
+		{"after patch", http.MethodPost, "/api/v1/notes/1/diff", `{"content":""}`, http.StatusOK, "diff_after_patch"},
+	})
+}
+
+func TestDiffNote(t *testing.T) {
+	s := testutil.NewServer(t, &handlers.Handler{Repo: repo.NewNoteRepoMemory()}, httpx.Config{})
+
+	resp := s.Request(http.MethodPost, "/api/v1/notes").
+		JSON(map[string]string{"title": "Список", "content": "молоко\nхлеб\nсыр"}).
+		Do(t)
+	resp.AssertStatus(t, http.StatusCreated)
+	var note handlers.NoteResponse
+	resp.Decode(t, &note)
+
+	resp = s.Request(http.MethodPost, note.Links.Self+"/diff").
+		JSON(map[string]string{"content": "молоко\nмасло\nсыр"}).
+		Do(t)
+	resp.AssertStatus(t, http.StatusOK)
+	var got handlers.NoteDiffResponse
+	resp.Decode(t, &got)
+
+	want := []handlers.DiffChunk{
+		{Op: "equal", Text: "молоко\n"},
+		{Op: "delete", Text: "хлеб\n"},
+		{Op: "insert", Text: "масло\n"},
+		{Op: "equal", Text: "сыр"},
+	}
+	if got.Identical || got.Added != 1 || got.Removed != 1 || got.Version != 1 {
+		t.Errorf("diff = %+v", got)
+	}
+	if len(got.Chunks) != len(want) {
+		t.Fatalf("chunks = %+v, want %+v", got.Chunks, want)
+	}
+	for i := range want {
+		if got.Chunks[i] != want[i] {
+			t.Errorf("chunk %d = %+v, want %+v", i, got.Chunks[i], want[i])
+		}
+	}
+}
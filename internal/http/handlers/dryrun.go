@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// DryRunHeader включает пробный запуск так же, как параметр ?dry_run=true.
+// В ответе пробного запуска этот заголовок равен "true".
+const DryRunHeader = "X-Dry-Run"
+
+// dryRun разбирает флаг пробного запуска из запроса; при некорректном
+// значении отвечает 400 и возвращает ok = false.
+func dryRun(w http.ResponseWriter, r *http.Request) (dry, ok bool) {
+	s := r.URL.Query().Get("dry_run")
+	if s == "" {
+		s = r.Header.Get(DryRunHeader)
+	}
+	if s == "" {
+		return false, true
+	}
+	dry, err := strconv.ParseBool(s)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid dry_run")
+		return false, false
+	}
+	return dry, true
+}
+
+// apply выполняет fn как обычно или, если dry, в транзакции, которая
+// откатывается: ответ совпадает с настоящим, но изменения не сохраняются.
+func (h *Handler) apply(w http.ResponseWriter, r *http.Request, dry bool, fn func(ctx context.Context) error) error {
+	if !dry {
+		return fn(r.Context())
+	}
+	w.Header().Set(DryRunHeader, "true")
+	return h.Repo.DryRun(r.Context(), fn)
+}
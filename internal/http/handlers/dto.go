@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"example.com/notes-api/internal/core"
+	"example.com/notes-api/internal/preview"
+)
+
+// Ответы API не отдают core.Note напрямую: схема БД и доменная модель
+// меняются независимо от контракта, а поля попадают в JSON только через DTO.
+
+// notesPath — путь коллекции заметок в ссылках ответа.
+const notesPath = "/api/v1/notes/"
+
+// NoteResponse — заметка в ответах API.
+type NoteResponse struct {
+	ID      int64  `json:"id" example:"1"`
+	Title   string `json:"title" example:"Новая заметка"`
+	Content string `json:"content" example:"Текст заметки"`
+	// Preview — начало content одной строкой без разметки Markdown, до 200
+	// символов; пустое у зашифрованных заметок.
+	Preview string `json:"preview" example:"Текст заметки"`
+	// ContentKind — вид содержимого для карточки; пустой у зашифрованных заметок.
+	ContentKind string `json:"content_kind,omitempty" example:"markdown" enums:"plain,markdown,checklist"`
+	Slug        string `json:"slug" example:"novaya-zametka"`
+	// Lang — язык заметки (ISO 639-1), определяется сервером при записи.
+	Lang         string          `json:"lang,omitempty" example:"ru"`
+	Version      int64           `json:"version" example:"1"`
+	ViewCount    int64           `json:"view_count"`
+	LastViewedAt *time.Time      `json:"last_viewed_at,omitempty"`
+	Metadata     json.RawMessage `json:"metadata" swaggertype:"object"`
+	Color        string          `json:"color" example:"default"`
+	Icon         string          `json:"icon,omitempty" example:"star"`
+	Position     float64         `json:"position"`
+	Latitude     *float64        `json:"latitude,omitempty" example:"55.7558"`
+	Longitude    *float64        `json:"longitude,omitempty" example:"37.6173"`
+	ExpiresAt    *time.Time      `json:"expires_at,omitempty"`
+	Encrypted    bool            `json:"encrypted"`
+	Ciphertext   []byte          `json:"ciphertext,omitempty"`
+	Nonce        []byte          `json:"nonce,omitempty"`
+	KeyID        *string         `json:"key_id,omitempty"`
+	ArchivedAt   *time.Time      `json:"archived_at,omitempty"`
+	// Announcement — заметка сейчас закреплена как объявление.
+	Announcement bool `json:"announcement,omitempty"`
+	// ReviewState — состояние согласования; пустое — заметка вне процесса.
+	ReviewState string     `json:"review_state,omitempty" enums:"draft,in_review,published"`
+	Reviewer    *string    `json:"reviewer,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	Links       NoteLinks  `json:"links"`
+}
+
+// NoteLinks — ссылки на связанные ресурсы заметки.
+type NoteLinks struct {
+	Self   string `json:"self" example:"/api/v1/notes/1"`
+	Stats  string `json:"stats" example:"/api/v1/notes/1/stats"`
+	Print  string `json:"print" example:"/api/v1/notes/1/print"`
+	Export string `json:"export" example:"/api/v1/notes/1/export"`
+}
+
+// AdminNoteResponse — заметка в админских ответах: с пометками удаления,
+// удержания и окном объявления.
+type AdminNoteResponse struct {
+	NoteResponse
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	LegalHoldAt   *time.Time `json:"legal_hold_at,omitempty"`
+	AnnouncedAt   *time.Time `json:"announced_at,omitempty"`
+	AnnounceFrom  *time.Time `json:"announce_from,omitempty"`
+	AnnounceUntil *time.Time `json:"announce_until,omitempty"`
+}
+
+// NearbyNoteResponse — заметка с расстоянием до точки запроса.
+type NearbyNoteResponse struct {
+	Note           NoteResponse `json:"note"`
+	DistanceMeters float64      `json:"distance_m"`
+}
+
+func newNoteResponse(n *core.Note) NoteResponse {
+	self := notesPath + strconv.FormatInt(n.ID, 10)
+	metadata := n.Metadata
+	if len(metadata) == 0 {
+		metadata = json.RawMessage(`{}`)
+	}
+	return NoteResponse{
+		ID:           n.ID,
+		Title:        n.Title,
+		Content:      n.Content,
+		Preview:      notePreview(n),
+		ContentKind:  contentKind(n),
+		Slug:         n.Slug,
+		Lang:         n.Lang,
+		Version:      n.Version,
+		ViewCount:    n.ViewCount,
+		LastViewedAt: n.LastViewedAt,
+		Metadata:     metadata,
+		Color:        n.Color,
+		Icon:         n.Icon,
+		Position:     n.Position,
+		Latitude:     n.Latitude,
+		Longitude:    n.Longitude,
+		ExpiresAt:    n.ExpiresAt,
+		Encrypted:    n.Encrypted,
+		Ciphertext:   n.Ciphertext,
+		Nonce:        n.Nonce,
+		KeyID:        n.KeyID,
+		ArchivedAt:   n.ArchivedAt,
+		Announcement: n.Announced,
+		ReviewState:  n.ReviewState,
+		Reviewer:     n.Reviewer,
+		ReviewedAt:   n.ReviewedAt,
+		CreatedAt:    n.CreatedAt,
+		UpdatedAt:    n.UpdatedAt,
+		Links: NoteLinks{
+			Self:   self,
+			Stats:  self + "/stats",
+			Print:  self + "/print",
+			Export: self + "/export",
+		},
+	}
+}
+
+func newNoteResponses(notes []core.Note) []NoteResponse {
+	out := make([]NoteResponse, len(notes))
+	for i := range notes {
+		out[i] = newNoteResponse(&notes[i])
+	}
+	return out
+}
+
+func newAdminNoteResponse(n *core.Note) AdminNoteResponse {
+	return AdminNoteResponse{
+		NoteResponse:  newNoteResponse(n),
+		DeletedAt:     n.DeletedAt,
+		LegalHoldAt:   n.LegalHoldAt,
+		AnnouncedAt:   n.AnnouncedAt,
+		AnnounceFrom:  n.AnnounceFrom,
+		AnnounceUntil: n.AnnounceUntil,
+	}
+}
+
+func newAdminNoteResponses(notes []core.Note) []AdminNoteResponse {
+	out := make([]AdminNoteResponse, len(notes))
+	for i := range notes {
+		out[i] = newAdminNoteResponse(&notes[i])
+	}
+	return out
+}
+
+func newNearbyNoteResponses(notes []core.NearbyNote) []NearbyNoteResponse {
+	out := make([]NearbyNoteResponse, len(notes))
+	for i := range notes {
+		out[i] = NearbyNoteResponse{
+			Note:           newNoteResponse(&notes[i].Note),
+			DistanceMeters: notes[i].DistanceMeters,
+		}
+	}
+	return out
+}
+
+// notePreview — сохранённое превью заметки, а если его нет (заметка записана
+// до появления превью или content шифруется в БД) — посчитанное из content.
+func notePreview(n *core.Note) string {
+	if n.Preview != "" {
+		return n.Preview
+	}
+	return preview.Text(n.Content)
+}
+
+// contentKind — сохранённый вид содержимого или посчитанный из content.
+func contentKind(n *core.Note) string {
+	if n.Encrypted {
+		return ""
+	}
+	if n.ContentKind != "" {
+		return n.ContentKind
+	}
+	return preview.Kind(n.Content)
+}
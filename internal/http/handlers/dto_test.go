@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/notes-api/internal/core"
+)
+
+func TestNewNoteResponse(t *testing.T) {
+	deleted := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	n := core.Note{
+		ID:        42,
+		Title:     "Заметка",
+		Content:   "первая строка\n\nвторая   строка",
+		DeletedAt: &deleted,
+	}
+
+	resp := newNoteResponse(&n)
+	if resp.Preview != "первая строка вторая строка" || resp.ContentKind != "plain" {
+		t.Errorf("preview = %q, kind = %q", resp.Preview, resp.ContentKind)
+	}
+	if string(resp.Metadata) != "{}" {
+		t.Errorf("metadata = %s, want {}", resp.Metadata)
+	}
+	if resp.Links.Self != "/api/v1/notes/42" || resp.Links.Export != "/api/v1/notes/42/export" {
+		t.Errorf("links = %+v", resp.Links)
+	}
+
+	// Пометки удаления и удержания — только в админском ответе
+	public, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(public), "deleted_at") {
+		t.Errorf("public response leaks deleted_at: %s", public)
+	}
+	if admin := newAdminNoteResponse(&n); admin.DeletedAt == nil || admin.ID != 42 {
+		t.Errorf("admin response = %+v", admin)
+	}
+}
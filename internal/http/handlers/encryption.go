@@ -0,0 +1,45 @@
+package handlers
+
+import "example.com/notes-api/internal/core"
+
+// validateEncryptedCreate проверяет согласованность полей шифрования новой заметки.
+// Возвращает текст ошибки или пустую строку.
+func validateEncryptedCreate(req core.NoteCreate) string {
+	if !req.Encrypted {
+		if len(req.Ciphertext) > 0 || len(req.Nonce) > 0 || req.KeyID != "" {
+			return "ciphertext, nonce and key_id require encrypted=true"
+		}
+		return ""
+	}
+
+	if req.Content != "" {
+		return "Encrypted note must not have plaintext content"
+	}
+	if len(req.Ciphertext) == 0 || len(req.Nonce) == 0 || req.KeyID == "" {
+		return "Encrypted note requires ciphertext, nonce and key_id"
+	}
+	return ""
+}
+
+// validateEncryptedUpdate проверяет PATCH с учётом того, зашифрована ли заметка.
+func validateEncryptedUpdate(current *core.Note, u core.NoteUpdate) string {
+	touchesCipher := u.Ciphertext != nil || u.Nonce != nil || u.KeyID != nil
+
+	if !current.Encrypted {
+		if touchesCipher {
+			return "Note is not encrypted"
+		}
+		return ""
+	}
+
+	if u.Content != nil {
+		return "Encrypted note content is opaque; send ciphertext instead"
+	}
+	if touchesCipher && (len(u.Ciphertext) == 0 || len(u.Nonce) == 0) {
+		return "ciphertext and nonce must be updated together"
+	}
+	if u.KeyID != nil && *u.KeyID == "" {
+		return "key_id cannot be empty"
+	}
+	return ""
+}
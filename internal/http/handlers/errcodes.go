@@ -0,0 +1,44 @@
+package handlers
+
+// Коды ошибок в поле code ответа. Коды стабильны: клиенты должны ветвиться
+// по ним, а не по тексту error, который зависит от Accept-Language.
+const (
+	CodeInternal            = "internal_error"
+	CodeInvalidBody         = "invalid_body"
+	CodeInvalidJSON         = "invalid_json"
+	CodeInvalidNoteID       = "invalid_note_id"
+	CodeInvalidParameter    = "invalid_parameter"
+	CodeNoFields            = "no_fields"
+	CodeNoteNotFound        = "note_not_found"
+	CodeTitleRequired       = "title_required"
+	CodeContentRequired     = "content_required"
+	CodeInvalidMetadata     = "invalid_metadata"
+	CodeInvalidColor        = "invalid_color"
+	CodeInvalidIcon         = "invalid_icon"
+	CodeInvalidLocation     = "invalid_location"
+	CodeInvalidExpiry       = "invalid_expiry"
+	CodeInvalidEncryption   = "invalid_encryption"
+	CodeInvalidMove         = "invalid_move"
+	CodeInvalidAnnouncement = "invalid_announcement"
+	CodeInvalidReviewState  = "invalid_review_state"
+	CodeReviewerRequired    = "reviewer_required"
+	CodeNotReviewer         = "not_reviewer"
+	CodeOwnerRequired       = "owner_required"
+	CodeNoteLocked          = "note_locked"
+	CodeVersionConflict     = "version_conflict"
+	CodeLegalHold           = "legal_hold"
+	CodeNoteEncrypted       = "note_encrypted"
+	CodeNotConfigured       = "not_configured"
+	CodeUpstreamFailed      = "upstream_failed"
+	CodeBatchAborted        = "batch_aborted"
+	CodeUnsupportedEncoding = "unsupported_encoding"
+	CodeTitleTaken          = "title_taken"
+	CodeInvalidEmail        = "invalid_email"
+	CodeInvalidName         = "invalid_name"
+	CodeInvalidPassword     = "invalid_password"
+	CodeEmailTaken          = "email_taken"
+	CodeAuthRequired        = "auth_required"
+	CodeInvalidCredentials  = "invalid_credentials"
+	CodeTitleTooLong        = "title_too_long"
+	CodeContentTooLarge     = "content_too_large"
+)
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"archive/zip"
+	"net/http"
+	"strconv"
+
+	"example.com/notes-api/internal/core"
+	"example.com/notes-api/internal/export"
+	"github.com/go-chi/chi/v5"
+)
+
+/*
+====================
+EXPORT
+====================
+*/
+
+// ExportNote godoc
+// @Summary      Экспорт заметки в файл
+// @Description  Markdown с YAML front-matter или Org-mode со свойствами (заголовок, даты).
+// @Tags         notes
+// @Produce      plain
+// @Param        id      path     int     true   "ID"
+// @Param        format  query    string  false  "md (по умолчанию) или org"
+// @Success      200     {string} string "Файл заметки"
+// @Failure      400     {object} ErrorResponse
+// @Failure      404     {object} ErrorResponse
+// @Failure      500     {object} ErrorResponse
+// @Router       /notes/{id}/export [get]
+func (h *Handler) ExportNote(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
+		return
+	}
+
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+
+	note, err := h.Repo.GetByID(r.Context(), id)
+	if err != nil {
+		if respondNotFound(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get note")
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", `attachment; filename="`+export.Filename(*note, format)+`"`)
+	_, _ = w.Write(export.Render(*note, format))
+}
+
+// ExportNotes godoc
+// @Summary      Экспорт всех заметок
+// @Description  ZIP-архив, по файлу на каждую неархивную заметку.
+// @Tags         notes
+// @Produce      application/zip
+// @Param        format  query    string  false  "md (по умолчанию) или org"
+// @Success      200     {file}   file
+// @Failure      400     {object} ErrorResponse
+// @Failure      500     {object} ErrorResponse
+// @Router       /notes/export [get]
+func (h *Handler) ExportNotes(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+
+	notes, err := h.Repo.List(r.Context(), core.NoteFilter{})
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to list notes")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="notes-`+format+`.zip"`)
+
+	zw := zip.NewWriter(w)
+	for _, n := range notes {
+		f, err := zw.Create(export.Filename(n, format))
+		if err != nil {
+			return
+		}
+		if _, err := f.Write(export.Render(n, format)); err != nil {
+			return
+		}
+	}
+	_ = zw.Close()
+}
+
+// exportFormat читает ?format= и отвечает 400 при неизвестном формате.
+func exportFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = export.Markdown
+	}
+	if !export.Valid(format) {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid format")
+		return "", false
+	}
+	return format, true
+}
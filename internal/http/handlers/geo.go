@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"example.com/notes-api/internal/core"
+)
+
+const (
+	defaultNearbyRadius = 1000.0
+	maxNearbyRadius     = 50_000.0
+	nearbyLimit         = 100
+)
+
+/*
+====================
+NEARBY NOTES
+====================
+*/
+
+// NearbyNotes godoc
+// @Summary      Заметки поблизости
+// @Tags         notes
+// @Produce      json
+// @Param        lat     query    number  true   "Широта"
+// @Param        lon     query    number  true   "Долгота"
+// @Param        radius  query    number  false  "Радиус в метрах (по умолчанию 1000, максимум 50000)"
+// @Success      200     {array}  NearbyNoteResponse
+// @Failure      400     {object} ErrorResponse
+// @Failure      500     {object} ErrorResponse
+// @Router       /notes/nearby [get]
+func (h *Handler) NearbyNotes(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	lat, errLat := strconv.ParseFloat(q.Get("lat"), 64)
+	lon, errLon := strconv.ParseFloat(q.Get("lon"), 64)
+	if errLat != nil || errLon != nil || !core.ValidCoordinates(lat, lon) {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidLocation, "Invalid coordinates")
+		return
+	}
+
+	radius := defaultNearbyRadius
+	if s := q.Get("radius"); s != "" {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v <= 0 {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid radius")
+			return
+		}
+		radius = min(v, maxNearbyRadius)
+	}
+
+	notes, err := h.Repo.ListNearby(r.Context(), lat, lon, radius, nearbyLimit)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to list nearby notes")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, newNearbyNoteResponses(notes))
+}
+
+// validLocation проверяет, что координаты заданы парой и в допустимых пределах.
+func validLocation(lat, lon *float64) bool {
+	if lat == nil && lon == nil {
+		return true
+	}
+	if lat == nil || lon == nil {
+		return false
+	}
+	return core.ValidCoordinates(*lat, *lon)
+}
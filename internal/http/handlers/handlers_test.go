@@ -0,0 +1,506 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/notes-api/internal/auth"
+	httpx "example.com/notes-api/internal/http"
+	"example.com/notes-api/internal/http/handlers"
+	"example.com/notes-api/internal/proofread"
+	"example.com/notes-api/internal/repo"
+	"example.com/notes-api/internal/testutil"
+)
+
+// Обработчики проверяют запрос до обращения к репозиторию, поэтому ошибки
+// валидации тестируются без базы: Handler без Repo. Сценарии целиком идут
+// через репозиторий в памяти. Тесты отдельного обработчика лежат в файле
+// рядом с ним (notes_test.go для notes.go и т. д.), здесь — общие помощники
+// и проверки по всему API.
+
+const adminToken = "test-admin-token"
+
+func newServer(t *testing.T) *testutil.Server {
+	return testutil.NewServer(t, &handlers.Handler{}, httpx.Config{AdminToken: adminToken})
+}
+
+// newTokens — выпуск токенов пользователей для тестов с JWT.
+func newTokens(t *testing.T) *auth.JWT {
+	t.Helper()
+	tokens, err := auth.NewJWT([]byte(strings.Repeat("s", auth.MinJWTSecret)), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokens
+}
+
+// issue выпускает токен пользователя userID.
+func issue(t *testing.T, tokens *auth.JWT, userID int64) string {
+	t.Helper()
+	token, _, err := tokens.Issue(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestValidationErrors(t *testing.T) {
+	s := newServer(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		code   string
+	}{
+		// Создание
+		{"create invalid json", http.MethodPost, "/api/v1/notes", `{`, http.StatusBadRequest, "invalid_json"},
+		{"create without title", http.MethodPost, "/api/v1/notes", `{"content":"x"}`, http.StatusBadRequest, "title_required"},
+		{"create blank title", http.MethodPost, "/api/v1/notes", `{"title":"   "}`, http.StatusBadRequest, "title_required"},
+		{"create control-only title", http.MethodPost, "/api/v1/notes", `{"title":"\u0000\t"}`, http.StatusBadRequest, "title_required"},
+		{"create metadata not object", http.MethodPost, "/api/v1/notes", `{"title":"a","metadata":[1]}`, http.StatusBadRequest, "invalid_metadata"},
+		{"create invalid color", http.MethodPost, "/api/v1/notes", `{"title":"a","color":"ultraviolet"}`, http.StatusBadRequest, "invalid_color"},
+		{"create invalid icon", http.MethodPost, "/api/v1/notes", `{"title":"a","icon":"Not An Icon"}`, http.StatusBadRequest, "invalid_icon"},
+		{"create latitude out of range", http.MethodPost, "/api/v1/notes", `{"title":"a","latitude":91,"longitude":0}`, http.StatusBadRequest, "invalid_location"},
+		{"create latitude without longitude", http.MethodPost, "/api/v1/notes", `{"title":"a","latitude":10}`, http.StatusBadRequest, "invalid_location"},
+		{"create expiry in the past", http.MethodPost, "/api/v1/notes", `{"title":"a","expires_at":"2000-01-01T00:00:00Z"}`, http.StatusBadRequest, "invalid_expiry"},
+		{"create title too long", http.MethodPost, "/api/v1/notes", `{"title":"` + strings.Repeat("я", 501) + `"}`, http.StatusUnprocessableEntity, "title_too_long"},
+		{"create content too large", http.MethodPost, "/api/v1/notes", `{"title":"a","content":"` + strings.Repeat("x", 1<<20+1) + `"}`, http.StatusUnprocessableEntity, "content_too_large"},
+		{"patch content too large", http.MethodPatch, "/api/v1/notes/1", `{"content":"` + strings.Repeat("x", 1<<20+1) + `"}`, http.StatusUnprocessableEntity, "content_too_large"},
+		{"create ciphertext without encrypted", http.MethodPost, "/api/v1/notes", `{"title":"a","ciphertext":"AAAA"}`, http.StatusBadRequest, "invalid_encryption"},
+		{"create encrypted without ciphertext", http.MethodPost, "/api/v1/notes", `{"title":"a","encrypted":true}`, http.StatusBadRequest, "invalid_encryption"},
+
+		// Чтение и список
+		{"get invalid id", http.MethodGet, "/api/v1/notes/abc", ``, http.StatusBadRequest, "invalid_note_id"},
+		{"list invalid metadata filter", http.MethodGet, "/api/v1/notes?meta.=x", ``, http.StatusBadRequest, "invalid_metadata"},
+		{"list invalid color", http.MethodGet, "/api/v1/notes?color=ultraviolet", ``, http.StatusBadRequest, "invalid_color"},
+		{"list invalid archived", http.MethodGet, "/api/v1/notes?archived=maybe", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"list invalid sort", http.MethodGet, "/api/v1/notes?sort=random", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"list invalid review state", http.MethodGet, "/api/v1/notes?review_state=approved", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"trigger invalid since_id", http.MethodGet, "/api/v1/integrations/notes/new?since_id=-1", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"trigger invalid limit", http.MethodGet, "/api/v1/integrations/notes/new?limit=0", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"search missing query", http.MethodGet, "/api/v1/integrations/notes/search?q=+", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"integrations create title required", http.MethodPost, "/api/v1/integrations/notes", `{}`, http.StatusBadRequest, "title_required"},
+		{"by title missing title", http.MethodGet, "/api/v1/notes/by-title?title=", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"by title invalid match", http.MethodGet, "/api/v1/notes/by-title?title=a&match=fuzzy", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"overview invalid limit", http.MethodGet, "/api/v1/overview?limit=0", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"list invalid lang", http.MethodGet, "/api/v1/notes?lang=xx", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"stats invalid id", http.MethodGet, "/api/v1/notes/abc/stats", ``, http.StatusBadRequest, "invalid_note_id"},
+		{"print invalid id", http.MethodGet, "/api/v1/notes/abc/print", ``, http.StatusBadRequest, "invalid_note_id"},
+		{"export invalid id", http.MethodGet, "/api/v1/notes/abc/export", ``, http.StatusBadRequest, "invalid_note_id"},
+		{"export all invalid format", http.MethodGet, "/api/v1/notes/export?format=docx", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"changes invalid since", http.MethodGet, "/api/v1/notes/changes?since=x", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"changes invalid wait", http.MethodGet, "/api/v1/notes/changes?wait=x", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"recent invalid limit", http.MethodGet, "/api/v1/notes/recent?limit=x", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"calendar invalid tz", http.MethodGet, "/api/v1/notes/calendar?tz=Mars/Olympus&from=2024-01-01&to=2024-01-31", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"calendar missing from", http.MethodGet, "/api/v1/notes/calendar?to=2024-01-31", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"calendar invalid to", http.MethodGet, "/api/v1/notes/calendar?from=2024-01-01&to=soon", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"daily get invalid date", http.MethodGet, "/api/v1/notes/daily/2024-13-01", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"daily create invalid date", http.MethodPost, "/api/v1/notes/daily/today", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"nearby missing coordinates", http.MethodGet, "/api/v1/notes/nearby", ``, http.StatusBadRequest, "invalid_location"},
+		{"nearby coordinates out of range", http.MethodGet, "/api/v1/notes/nearby?lat=0&lon=181", ``, http.StatusBadRequest, "invalid_location"},
+		{"nearby invalid radius", http.MethodGet, "/api/v1/notes/nearby?lat=0&lon=0&radius=-1", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"activity invalid before", http.MethodGet, "/api/v1/activity?before=x", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"activity invalid limit", http.MethodGet, "/api/v1/activity?limit=x", ``, http.StatusBadRequest, "invalid_parameter"},
+
+		// Изменение
+		{"patch invalid id", http.MethodPatch, "/api/v1/notes/abc", `{"title":"a"}`, http.StatusBadRequest, "invalid_note_id"},
+		{"patch invalid json", http.MethodPatch, "/api/v1/notes/1", `{`, http.StatusBadRequest, "invalid_json"},
+		{"patch no fields", http.MethodPatch, "/api/v1/notes/1", `{}`, http.StatusBadRequest, "no_fields"},
+		{"patch empty title", http.MethodPatch, "/api/v1/notes/1", `{"title":" "}`, http.StatusBadRequest, "title_required"},
+		{"patch metadata not object", http.MethodPatch, "/api/v1/notes/1", `{"metadata":"x"}`, http.StatusBadRequest, "invalid_metadata"},
+		{"patch invalid color", http.MethodPatch, "/api/v1/notes/1", `{"color":"ultraviolet"}`, http.StatusBadRequest, "invalid_color"},
+		{"patch invalid icon", http.MethodPatch, "/api/v1/notes/1", `{"icon":"Not An Icon"}`, http.StatusBadRequest, "invalid_icon"},
+		{"patch expiry in the past", http.MethodPatch, "/api/v1/notes/1", `{"expires_at":"2000-01-01T00:00:00Z"}`, http.StatusBadRequest, "invalid_expiry"},
+		{"batch invalid atomic", http.MethodPatch, "/api/v1/notes?atomic=maybe", `[]`, http.StatusBadRequest, "invalid_parameter"},
+		{"batch invalid json", http.MethodPatch, "/api/v1/notes", `{"id":1}`, http.StatusBadRequest, "invalid_json"},
+		{"batch empty", http.MethodPatch, "/api/v1/notes", `[]`, http.StatusBadRequest, "no_fields"},
+		{"delete invalid id", http.MethodDelete, "/api/v1/notes/abc", ``, http.StatusBadRequest, "invalid_note_id"},
+		{"delete invalid dry_run", http.MethodDelete, "/api/v1/notes/1?dry_run=perhaps", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"lock invalid id", http.MethodPost, "/api/v1/notes/abc/lock", `{"owner":"a"}`, http.StatusBadRequest, "invalid_note_id"},
+		{"lock invalid json", http.MethodPost, "/api/v1/notes/1/lock", `{`, http.StatusBadRequest, "invalid_json"},
+		{"lock without owner", http.MethodPost, "/api/v1/notes/1/lock", `{"owner":" "}`, http.StatusBadRequest, "owner_required"},
+		{"lock negative ttl", http.MethodPost, "/api/v1/notes/1/lock", `{"owner":"a","ttl_seconds":-1}`, http.StatusBadRequest, "invalid_parameter"},
+		{"unlock invalid id", http.MethodPost, "/api/v1/notes/abc/unlock", `{"owner":"a"}`, http.StatusBadRequest, "invalid_note_id"},
+		{"unlock invalid json", http.MethodPost, "/api/v1/notes/1/unlock", `{`, http.StatusBadRequest, "invalid_json"},
+		{"move invalid id", http.MethodPost, "/api/v1/notes/abc/move", `{}`, http.StatusBadRequest, "invalid_note_id"},
+		{"move invalid json", http.MethodPost, "/api/v1/notes/1/move", `{`, http.StatusBadRequest, "invalid_json"},
+		{"move both neighbours", http.MethodPost, "/api/v1/notes/1/move", `{"after_id":2,"before_id":3}`, http.StatusBadRequest, "invalid_move"},
+		{"proofread invalid id", http.MethodPost, "/api/v1/notes/abc/proofread", ``, http.StatusBadRequest, "invalid_note_id"},
+		{"proofread not configured", http.MethodPost, "/api/v1/notes/1/proofread", ``, http.StatusNotImplemented, "not_configured"},
+		{"register invalid json", http.MethodPost, "/api/v1/auth/register", `{`, http.StatusBadRequest, "invalid_json"},
+		{"register invalid email", http.MethodPost, "/api/v1/auth/register", `{"email":"alice","password":"long enough"}`, http.StatusBadRequest, "invalid_email"},
+		{"register email with name", http.MethodPost, "/api/v1/auth/register", `{"email":"Alice <a@example.com>","password":"long enough"}`, http.StatusBadRequest, "invalid_email"},
+		{"register short password", http.MethodPost, "/api/v1/auth/register", `{"email":"a@example.com","password":"short"}`, http.StatusBadRequest, "invalid_password"},
+		{"register long password", http.MethodPost, "/api/v1/auth/register", `{"email":"a@example.com","password":"` + strings.Repeat("x", 73) + `"}`, http.StatusBadRequest, "invalid_password"},
+		{"register long name", http.MethodPost, "/api/v1/auth/register", `{"email":"a@example.com","password":"long enough","name":"` + strings.Repeat("я", 101) + `"}`, http.StatusBadRequest, "invalid_name"},
+		{"register not configured", http.MethodPost, "/api/v1/auth/register", `{"email":"a@example.com","password":"long enough"}`, http.StatusNotImplemented, "not_configured"},
+		{"me not configured", http.MethodGet, "/api/v1/me", ``, http.StatusNotImplemented, "not_configured"},
+		{"translate invalid id", http.MethodPost, "/api/v1/notes/abc/translate?to=en", ``, http.StatusBadRequest, "invalid_note_id"},
+		{"translate without to", http.MethodPost, "/api/v1/notes/1/translate", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"translate unknown language", http.MethodPost, "/api/v1/notes/1/translate?to=xx", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"translate invalid save", http.MethodPost, "/api/v1/notes/1/translate?to=en&save=maybe", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"translate not configured", http.MethodPost, "/api/v1/notes/1/translate?to=en", ``, http.StatusNotImplemented, "not_configured"},
+		{"suggest tags invalid id", http.MethodGet, "/api/v1/notes/abc/suggest-tags", ``, http.StatusBadRequest, "invalid_note_id"},
+		{"suggest tags invalid limit", http.MethodGet, "/api/v1/notes/1/suggest-tags?limit=0", ``, http.StatusBadRequest, "invalid_parameter"},
+		{"diff invalid id", http.MethodPost, "/api/v1/notes/abc/diff", `{}`, http.StatusBadRequest, "invalid_note_id"},
+		{"diff invalid json", http.MethodPost, "/api/v1/notes/1/diff", `{`, http.StatusBadRequest, "invalid_json"},
+		{"diff without content", http.MethodPost, "/api/v1/notes/1/diff", `{}`, http.StatusBadRequest, "content_required"},
+		{"diff content too large", http.MethodPost, "/api/v1/notes/1/diff", `{"content":"` + strings.Repeat("x", 1<<20+1) + `"}`, http.StatusUnprocessableEntity, "content_too_large"},
+
+		// Согласование
+		{"draft invalid id", http.MethodPost, "/api/v1/notes/abc/draft", ``, http.StatusBadRequest, "invalid_note_id"},
+		{"review invalid json", http.MethodPost, "/api/v1/notes/1/review", `{`, http.StatusBadRequest, "invalid_json"},
+		{"review without reviewer", http.MethodPost, "/api/v1/notes/1/review", `{"reviewer":"  "}`, http.StatusBadRequest, "reviewer_required"},
+		{"approve without reviewer", http.MethodPost, "/api/v1/notes/1/approve", `{}`, http.StatusBadRequest, "reviewer_required"},
+		{"reject without reviewer", http.MethodPost, "/api/v1/notes/1/reject", `{}`, http.StatusBadRequest, "reviewer_required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := s.Request(tt.method, tt.path)
+			if tt.body != "" {
+				req.Body(tt.body)
+			}
+			resp := req.Do(t)
+			resp.AssertStatus(t, tt.status)
+
+			var body handlers.ErrorResponse
+			resp.Decode(t, &body)
+			if body.Code != tt.code {
+				t.Errorf("code = %q, want %q", body.Code, tt.code)
+			}
+			if body.RequestID == "" {
+				t.Error("request_id is empty")
+			}
+		})
+	}
+}
+
+func TestAdminRoutes(t *testing.T) {
+	s := newServer(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		status int
+		code   string
+	}{
+		{"no token", http.MethodGet, "/api/v1/admin/notes", "", http.StatusForbidden, "admin_required"},
+		{"wrong token", http.MethodGet, "/api/v1/admin/notes", "nope", http.StatusForbidden, "admin_required"},
+		{"list invalid before", http.MethodGet, "/api/v1/admin/notes?before=x", adminToken, http.StatusBadRequest, "invalid_parameter"},
+		{"list invalid limit", http.MethodGet, "/api/v1/admin/notes?limit=x", adminToken, http.StatusBadRequest, "invalid_parameter"},
+		{"get invalid id", http.MethodGet, "/api/v1/admin/notes/abc", adminToken, http.StatusBadRequest, "invalid_note_id"},
+		{"restore invalid id", http.MethodPost, "/api/v1/admin/notes/abc/restore", adminToken, http.StatusBadRequest, "invalid_note_id"},
+		{"restore invalid dry_run", http.MethodPost, "/api/v1/admin/notes/1/restore?dry_run=x", adminToken, http.StatusBadRequest, "invalid_parameter"},
+		{"integrity repair invalid dry_run", http.MethodPost, "/api/v1/admin/integrity/repair?dry_run=x", adminToken, http.StatusBadRequest, "invalid_parameter"},
+		{"retention run invalid dry_run", http.MethodPost, "/api/v1/admin/retention/run?dry_run=x", adminToken, http.StatusBadRequest, "invalid_parameter"},
+		{"retention run not configured", http.MethodPost, "/api/v1/admin/retention/run", adminToken, http.StatusNotImplemented, "not_configured"},
+		{"place hold invalid id", http.MethodPost, "/api/v1/admin/notes/abc/hold", adminToken, http.StatusBadRequest, "invalid_note_id"},
+		{"release hold invalid id", http.MethodDelete, "/api/v1/admin/notes/abc/hold", adminToken, http.StatusBadRequest, "invalid_note_id"},
+		{"announce invalid id", http.MethodPut, "/api/v1/admin/notes/abc/announcement", adminToken, http.StatusBadRequest, "invalid_note_id"},
+		{"unannounce invalid id", http.MethodDelete, "/api/v1/admin/notes/abc/announcement", adminToken, http.StatusBadRequest, "invalid_note_id"},
+		{"list backups not configured", http.MethodGet, "/api/v1/admin/backups", adminToken, http.StatusNotImplemented, "not_configured"},
+		{"create backup not configured", http.MethodPost, "/api/v1/admin/backups", adminToken, http.StatusNotImplemented, "not_configured"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := s.Request(tt.method, tt.path)
+			if tt.token != "" {
+				req.Header("Authorization", "Bearer "+tt.token)
+			}
+			resp := req.Do(t)
+			resp.AssertStatus(t, tt.status)
+
+			var body handlers.ErrorResponse
+			resp.Decode(t, &body)
+			if body.Code != tt.code {
+				t.Errorf("code = %q, want %q", body.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestAnnouncementWindow(t *testing.T) {
+	s := newServer(t)
+
+	for name, body := range map[string]string{
+		"until before from": `{"from":"2030-01-02T00:00:00Z","until":"2030-01-01T00:00:00Z"}`,
+		"until in the past": `{"until":"2000-01-01T00:00:00Z"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := s.Request(http.MethodPut, "/api/v1/admin/notes/1/announcement").
+				Header("Authorization", "Bearer "+adminToken).
+				Body(body).
+				Do(t)
+			resp.AssertStatus(t, http.StatusBadRequest)
+
+			var got handlers.ErrorResponse
+			resp.Decode(t, &got)
+			if got.Code != "invalid_announcement" {
+				t.Errorf("code = %q, want invalid_announcement", got.Code)
+			}
+		})
+	}
+}
+
+func TestGoldenResponses(t *testing.T) {
+	s := newServer(t)
+
+	tests := []struct {
+		name   string
+		req    *testutil.Request
+		status int
+	}{
+		{"health", s.Request(http.MethodGet, "/health"), http.StatusOK},
+		{"health_live", s.Request(http.MethodGet, "/health/live"), http.StatusOK},
+		{"error_title_required", s.Request(http.MethodPost, "/api/v1/notes").Body(`{}`), http.StatusBadRequest},
+		{"error_title_required_ru", s.Request(http.MethodPost, "/api/v1/notes").Body(`{}`).
+			Header("Accept-Language", "ru-RU,ru;q=0.9"), http.StatusBadRequest},
+		{"error_admin_required", s.Request(http.MethodGet, "/api/v1/admin/notes"), http.StatusForbidden},
+		{"retention_preview_empty", s.Request(http.MethodGet, "/api/v1/admin/retention/preview").
+			Header("Authorization", "Bearer "+adminToken), http.StatusOK},
+		{"integrations_sample", s.Request(http.MethodGet, "/api/v1/integrations/notes/sample"), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := tt.req.Do(t)
+			resp.AssertStatus(t, tt.status)
+			resp.AssertGolden(t, tt.name, "request_id")
+		})
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	s := testutil.NewServer(t, &handlers.Handler{}, httpx.Config{AdminToken: adminToken, ReadOnly: true})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		status int
+		code   string
+	}{
+		{"read allowed", http.MethodGet, "/api/v1/notes?sort=random", "", http.StatusBadRequest, "invalid_parameter"},
+		{"create anonymous", http.MethodPost, "/api/v1/notes", "", http.StatusForbidden, "read_only"},
+		{"patch anonymous", http.MethodPatch, "/api/v1/notes/abc", "", http.StatusForbidden, "read_only"},
+		{"create wrong token", http.MethodPost, "/api/v1/notes", "nope", http.StatusForbidden, "admin_required"},
+		{"create with token", http.MethodPost, "/api/v1/notes", adminToken, http.StatusBadRequest, "title_required"},
+		{"admin unaffected", http.MethodPost, "/api/v1/admin/notes/abc/restore", adminToken, http.StatusBadRequest, "invalid_note_id"},
+		{"register anonymous", http.MethodPost, "/api/v1/auth/register", "", http.StatusForbidden, "read_only"},
+		{"patch me anonymous", http.MethodPatch, "/api/v1/me", "", http.StatusForbidden, "read_only"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := s.Request(tt.method, tt.path).Body(`{}`)
+			if tt.token != "" {
+				req.Header("Authorization", "Bearer "+tt.token)
+			}
+			resp := req.Do(t)
+			resp.AssertStatus(t, tt.status)
+
+			var body handlers.ErrorResponse
+			resp.Decode(t, &body)
+			if body.Code != tt.code {
+				t.Errorf("code = %q, want %q", body.Code, tt.code)
+			}
+		})
+	}
+}
+
+// TestReadOnlyJWT: в режиме только для чтения с JWT пользователь пишет по
+// своему токену, а токены пользователей не тратят попытки админского lockout.
+func TestReadOnlyJWT(t *testing.T) {
+	tokens := newTokens(t)
+	s := testutil.NewServer(t, &handlers.Handler{Repo: repo.NewNoteRepoMemory()}, httpx.Config{
+		AdminToken:   adminToken,
+		AdminLockout: auth.NewLockout(1, time.Minute, time.Hour),
+		ReadOnly:     true,
+		JWT:          tokens,
+	})
+	userToken := issue(t, tokens, 1)
+
+	tests := []struct {
+		name   string
+		token  string
+		status int
+		code   string
+	}{
+		{"create with user token", userToken, http.StatusCreated, ""},
+		{"create with tampered user token", userToken + "x", http.StatusUnauthorized, "invalid_token"},
+		{"create with user token again", userToken, http.StatusCreated, ""},
+		// Lockout с порогом 1: любая засчитанная неудача выше дала бы здесь 429
+		{"create with admin token", adminToken, http.StatusCreated, ""},
+		{"create anonymous", "", http.StatusForbidden, "read_only"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := s.Request(http.MethodPost, "/api/v1/notes").JSON(map[string]string{"title": tt.name})
+			if tt.token != "" {
+				req.Header("Authorization", "Bearer "+tt.token)
+			}
+			resp := req.Do(t)
+			resp.AssertStatus(t, tt.status)
+			if tt.code == "" {
+				return
+			}
+			var body handlers.ErrorResponse
+			resp.Decode(t, &body)
+			if body.Code != tt.code {
+				t.Errorf("code = %q, want %q", body.Code, tt.code)
+			}
+		})
+	}
+}
+
+// goldenStep — шаг сценария на сервере в памяти: запрос, ожидаемый код и
+// golden-файл тела (пусто — тело не сверяется, например у 204).
+type goldenStep struct {
+	name   string
+	method string
+	path   string
+	body   string
+	status int
+	golden string
+}
+
+// runGolden выполняет шаги по порядку от имени администратора: шаги
+// опираются на данные предыдущих, поэтому первая ошибка останавливает тест.
+func runGolden(t *testing.T, s *testutil.Server, steps []goldenStep) {
+	t.Helper()
+	for _, tt := range steps {
+		req := s.Request(tt.method, tt.path).Header("Authorization", "Bearer "+adminToken)
+		if tt.body != "" {
+			req.Body(tt.body)
+		}
+		resp := req.Do(t)
+		if resp.Status != tt.status {
+			t.Fatalf("%s: status = %d, want %d; body: %s", tt.name, resp.Status, tt.status, resp.Body)
+		}
+		if tt.golden != "" {
+			resp.AssertGolden(t, tt.golden, "request_id")
+		}
+	}
+}
+
+// noopChecker — проверка правописания без ошибок: для обработчика без внешнего сервиса.
+type noopChecker struct{}
+
+func (noopChecker) Check(ctx context.Context, text, lang string) ([]proofread.Suggestion, error) {
+	return nil, nil
+}
+
+// TestSuccessPaths проходит по всем маршрутам API на хранилище в памяти.
+// Шаги выполняются по порядку и опираются на заметки 1 и 2 из первых шагов.
+// Тела ответов обработчиков заметок, блокировок, пакетов, diff, тегов и
+// учётных записей сверяются в тестах этих обработчиков; здесь — остальные.
+func TestSuccessPaths(t *testing.T) {
+	s := testutil.NewMemoryServer(t, httpx.Config{AdminToken: adminToken})
+	s.Handler.Proofreader = noopChecker{}
+	s.Handler.Translator = prefixTranslator{}
+
+	today := testutil.Now.Format("2006-01-02")
+	future := testutil.Now.Add(time.Hour).Format(time.RFC3339)
+
+	runGolden(t, s, []goldenStep{
+		{"create", http.MethodPost, "/api/v1/notes", `{"title":"Покупки","content":"Молоко","latitude":55.75,"longitude":37.61}`, http.StatusCreated, ""},
+		{"create second", http.MethodPost, "/api/v1/notes", `{"title":"Работа","content":"Отчёт"}`, http.StatusCreated, ""},
+		{"list", http.MethodGet, "/api/v1/notes", ``, http.StatusOK, ""},
+		{"get", http.MethodGet, "/api/v1/notes/1", ``, http.StatusOK, ""},
+		{"get by slug", http.MethodGet, "/api/v1/notes/by-slug/pokupki", ``, http.StatusOK, ""},
+		{"get by title", http.MethodGet, "/api/v1/notes/by-title?title=%D0%9F%D0%BE%D0%BA%D1%83%D0%BF%D0%BA%D0%B8", ``, http.StatusOK, ""},
+		{"recent", http.MethodGet, "/api/v1/notes/recent", ``, http.StatusOK, ""},
+		{"changes", http.MethodGet, "/api/v1/notes/changes", ``, http.StatusOK, ""},
+		{"calendar", http.MethodGet, "/api/v1/notes/calendar?from=" + today + "&to=" + today, ``, http.StatusOK, "note_calendar"},
+		{"nearby", http.MethodGet, "/api/v1/notes/nearby?lat=55.75&lon=37.61", ``, http.StatusOK, ""},
+		{"export all", http.MethodGet, "/api/v1/notes/export", ``, http.StatusOK, ""},
+		{"export", http.MethodGet, "/api/v1/notes/1/export?format=org", ``, http.StatusOK, ""},
+		{"print", http.MethodGet, "/api/v1/notes/1/print", ``, http.StatusOK, ""},
+		{"stats", http.MethodGet, "/api/v1/notes/1/stats", ``, http.StatusOK, "note_stats"},
+		{"patch", http.MethodPatch, "/api/v1/notes/1", `{"content":"Молоко, хлеб","base_version":1}`, http.StatusOK, ""},
+		{"patch batch", http.MethodPatch, "/api/v1/notes", `[{"id":2,"changes":{"color":"yellow"}}]`, http.StatusOK, ""},
+		{"move", http.MethodPost, "/api/v1/notes/2/move", `{"before_id":1}`, http.StatusOK, ""},
+		{"lock", http.MethodPost, "/api/v1/notes/1/lock", `{"owner":"alice","ttl_seconds":60}`, http.StatusOK, ""},
+		{"unlock", http.MethodPost, "/api/v1/notes/1/unlock", `{"owner":"alice"}`, http.StatusNoContent, ""},
+		{"diff", http.MethodPost, "/api/v1/notes/1/diff", `{"content":"Молоко"}`, http.StatusOK, ""},
+		{"proofread", http.MethodPost, "/api/v1/notes/1/proofread", ``, http.StatusOK, "proofread"},
+		{"translate", http.MethodPost, "/api/v1/notes/1/translate?to=en", ``, http.StatusOK, ""},
+		{"suggest tags", http.MethodGet, "/api/v1/notes/1/suggest-tags", ``, http.StatusOK, ""},
+		{"submit for review", http.MethodPost, "/api/v1/notes/1/review", `{"reviewer":"bob"}`, http.StatusOK, "review_submit"},
+		{"approve", http.MethodPost, "/api/v1/notes/1/approve", `{"reviewer":"bob"}`, http.StatusOK, "review_approve"},
+		{"back to draft", http.MethodPost, "/api/v1/notes/1/draft", ``, http.StatusOK, "review_draft"},
+		{"submit second", http.MethodPost, "/api/v1/notes/2/review", `{"reviewer":"bob"}`, http.StatusOK, ""},
+		{"reject", http.MethodPost, "/api/v1/notes/2/reject", `{"reviewer":"bob"}`, http.StatusOK, "review_reject"},
+		{"daily create", http.MethodPost, "/api/v1/notes/daily/" + today, ``, http.StatusCreated, ""},
+		{"daily get", http.MethodGet, "/api/v1/notes/daily/" + today, ``, http.StatusOK, ""},
+		{"activity", http.MethodGet, "/api/v1/activity", ``, http.StatusOK, ""},
+		{"overview", http.MethodGet, "/api/v1/overview", ``, http.StatusOK, ""},
+		{"trigger new", http.MethodGet, "/api/v1/integrations/notes/new", ``, http.StatusOK, ""},
+		{"trigger sample", http.MethodGet, "/api/v1/integrations/notes/sample", ``, http.StatusOK, ""},
+		{"search action", http.MethodGet, "/api/v1/integrations/notes/search?q=%D0%9F%D0%BE%D0%BA", ``, http.StatusOK, ""},
+		{"create action", http.MethodPost, "/api/v1/integrations/notes", `{"title":"Из Zapier"}`, http.StatusCreated, ""},
+		{"delete", http.MethodDelete, "/api/v1/notes/2", ``, http.StatusNoContent, ""},
+
+		// Администрирование
+		{"admin list", http.MethodGet, "/api/v1/admin/notes", ``, http.StatusOK, ""},
+		{"admin get deleted", http.MethodGet, "/api/v1/admin/notes/2", ``, http.StatusOK, "admin_get_deleted"},
+		{"admin restore", http.MethodPost, "/api/v1/admin/notes/2/restore", ``, http.StatusOK, "admin_restore"},
+		{"admin hold", http.MethodPost, "/api/v1/admin/notes/1/hold", ``, http.StatusOK, "admin_hold"},
+		{"admin release hold", http.MethodDelete, "/api/v1/admin/notes/1/hold", ``, http.StatusOK, "admin_release_hold"},
+		{"admin announce", http.MethodPut, "/api/v1/admin/notes/1/announcement", `{"until":"` + future + `"}`, http.StatusOK, "admin_announce"},
+		{"admin unannounce", http.MethodDelete, "/api/v1/admin/notes/1/announcement", ``, http.StatusOK, "admin_unannounce"},
+		{"admin table stats", http.MethodGet, "/api/v1/admin/retention/tables", ``, http.StatusOK, ""},
+		{"admin integrity", http.MethodGet, "/api/v1/admin/integrity", ``, http.StatusOK, ""},
+		{"admin integrity repair", http.MethodPost, "/api/v1/admin/integrity/repair", ``, http.StatusOK, ""},
+		{"admin metrics", http.MethodGet, "/api/v1/admin/metrics", ``, http.StatusOK, ""},
+	})
+}
+
+func TestNotFound(t *testing.T) {
+	s := testutil.NewServer(t, &handlers.Handler{Repo: repo.NewNoteRepoMemory()}, httpx.Config{AdminToken: adminToken})
+
+	tests := []struct {
+		method, path string
+	}{
+		{http.MethodGet, "/api/v1/notes/999"},
+		{http.MethodGet, "/api/v1/notes/by-slug/nope"},
+		{http.MethodGet, "/api/v1/notes/999/stats"},
+		{http.MethodPost, "/api/v1/admin/notes/999/hold"},
+		{http.MethodDelete, "/api/v1/admin/notes/999/hold"},
+		{http.MethodPost, "/api/v1/admin/notes/999/restore"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			resp := s.Request(tt.method, tt.path).Header("Authorization", "Bearer "+adminToken).Do(t)
+			resp.AssertStatus(t, http.StatusNotFound)
+			var body handlers.ErrorResponse
+			resp.Decode(t, &body)
+			if body.Code != "note_not_found" {
+				t.Errorf("code = %q, want note_not_found", body.Code)
+			}
+		})
+	}
+}
+
+// prefixTranslator «переводит», дописывая код языка: для проверки обработчика
+// без внешнего сервиса.
+type prefixTranslator struct{}
+
+func (prefixTranslator) Translate(ctx context.Context, texts []string, from, to string) ([]string, error) {
+	out := make([]string, len(texts))
+	for i, t := range texts {
+		out[i] = to + ": " + t
+	}
+	return out, nil
+}
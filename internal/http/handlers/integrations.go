@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Эндпоинты для no-code платформ (Zapier, Make): плоские массивы вместо
+// обёрток и курсоров, ID заметки — ключ дедупликации опроса.
+
+const (
+	defaultTriggerLimit = 50
+	maxTriggerLimit     = 100
+)
+
+// IntegrationNote — заметка в плоском виде для no-code платформ.
+type IntegrationNote struct {
+	// ID — ключ дедупликации: платформа запускает сценарий один раз на каждый новый ID.
+	ID        int64           `json:"id" example:"42"`
+	Title     string          `json:"title" example:"Встреча с командой"`
+	Content   string          `json:"content" example:"Обсудить план релиза"`
+	Preview   string          `json:"preview" example:"Обсудить план релиза"`
+	Slug      string          `json:"slug" example:"vstrecha-s-komandoy"`
+	Color     string          `json:"color" example:"default"`
+	Lang      string          `json:"lang,omitempty" example:"ru"`
+	Metadata  json.RawMessage `json:"metadata" swaggertype:"object"`
+	URL       string          `json:"url" example:"/api/v1/notes/42"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func newIntegrationNote(n NoteResponse) IntegrationNote {
+	return IntegrationNote{
+		ID:        n.ID,
+		Title:     n.Title,
+		Content:   n.Content,
+		Preview:   n.Preview,
+		Slug:      n.Slug,
+		Color:     n.Color,
+		Lang:      n.Lang,
+		Metadata:  n.Metadata,
+		URL:       n.Links.Self,
+		CreatedAt: n.CreatedAt,
+	}
+}
+
+func newIntegrationNotes(notes []NoteResponse) []IntegrationNote {
+	out := make([]IntegrationNote, len(notes))
+	for i := range notes {
+		out[i] = newIntegrationNote(notes[i])
+	}
+	return out
+}
+
+// sampleIntegrationNote — пример ответа опроса для настройки сценария, пока заметок нет.
+var sampleIntegrationNote = IntegrationNote{
+	ID:        42,
+	Title:     "Встреча с командой",
+	Content:   "Обсудить план релиза",
+	Preview:   "Обсудить план релиза",
+	Slug:      "vstrecha-s-komandoy",
+	Color:     "default",
+	Lang:      "ru",
+	Metadata:  json.RawMessage(`{"source":"sample"}`),
+	URL:       notesPath + "42",
+	CreatedAt: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+}
+
+/*
+====================
+POLLING TRIGGER
+====================
+*/
+
+// NewNotesTrigger godoc
+// @Summary      Новые заметки (триггер опроса)
+// @Description  Заметки с ID больше since_id, новые первыми. Подходит для триггеров Zapier/Make:
+// @Description  платформа запоминает увиденные id и запускает сценарий только на новые.
+// @Description  Без since_id возвращает последние заметки.
+// @Tags         integrations
+// @Produce      json
+// @Param        since_id  query    int  false  "Последний обработанный ID"
+// @Param        limit     query    int  false  "Количество (по умолчанию 50, максимум 100)"
+// @Success      200  {array}  IntegrationNote
+// @Failure      400  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /integrations/notes/new [get]
+func (h *Handler) NewNotesTrigger(w http.ResponseWriter, r *http.Request) {
+	var sinceID int64
+	if s := r.URL.Query().Get("since_id"); s != "" {
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || v < 0 {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid since_id")
+			return
+		}
+		sinceID = v
+	}
+
+	limit, ok := triggerLimit(w, r)
+	if !ok {
+		return
+	}
+
+	notes, err := h.Repo.ListCreatedAfter(r.Context(), sinceID, limit)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to list notes")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, newIntegrationNotes(newNoteResponses(notes)))
+}
+
+// SampleNotesTrigger godoc
+// @Summary      Пример данных триггера
+// @Description  Статический пример ответа /integrations/notes/new для сопоставления полей в сценарии.
+// @Tags         integrations
+// @Produce      json
+// @Success      200  {array}  IntegrationNote
+// @Router       /integrations/notes/sample [get]
+func (h *Handler) SampleNotesTrigger(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, []IntegrationNote{sampleIntegrationNote})
+}
+
+/*
+====================
+SEARCH ACTION
+====================
+*/
+
+// SearchNotesAction godoc
+// @Summary      Найти заметки по заголовку
+// @Description  Полнотекстовый поиск по словам заголовка с учётом языка заметки; лучшие совпадения первыми.
+// @Description  Зашифрованные заметки не ищутся. Пустой массив — ничего не найдено.
+// @Tags         integrations
+// @Produce      json
+// @Param        q      query    string  true   "Слова заголовка"
+// @Param        limit  query    int     false  "Количество (по умолчанию 50, максимум 100)"
+// @Success      200  {array}  IntegrationNote
+// @Failure      400  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /integrations/notes/search [get]
+func (h *Handler) SearchNotesAction(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Search query is required")
+		return
+	}
+
+	limit, ok := triggerLimit(w, r)
+	if !ok {
+		return
+	}
+
+	notes, err := h.Repo.SearchTitles(r.Context(), query, limit)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to search notes")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, newIntegrationNotes(newNoteResponses(notes)))
+}
+
+// triggerLimit читает ?limit для эндпоинтов интеграций.
+func triggerLimit(w http.ResponseWriter, r *http.Request) (int, bool) {
+	limit := defaultTriggerLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid limit")
+			return 0, false
+		}
+		limit = min(v, maxTriggerLimit)
+	}
+	return limit, true
+}
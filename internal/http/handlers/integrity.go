@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"example.com/notes-api/internal/core"
+)
+
+// integrityBatch — сколько строк исправляется одним запросом.
+const integrityBatch = 1000
+
+/*
+====================
+ADMIN: INTEGRITY
+====================
+*/
+
+// CheckIntegrity godoc
+// @Summary      Проверка целостности
+// @Description  Считает строки-сироты по каждой проверке, ничего не меняя:
+// @Description  orphan_log_entries — записи журнала об удалённых заметках, expired_locks — истёкшие блокировки.
+// @Tags         admin
+// @Produce      json
+// @Security     AdminToken
+// @Success      200  {array}  core.IntegrityResult
+// @Failure      403  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /admin/integrity [get]
+func (h *Handler) CheckIntegrity(w http.ResponseWriter, r *http.Request) {
+	results := make([]core.IntegrityResult, 0, len(core.IntegrityChecks))
+	for _, check := range core.IntegrityChecks {
+		n, err := h.Repo.CountIntegrity(r.Context(), check)
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to check integrity")
+			return
+		}
+		results = append(results, core.IntegrityResult{Check: check, Found: n})
+	}
+	respondWithJSON(w, http.StatusOK, results)
+}
+
+// RepairIntegrity godoc
+// @Summary      Исправить найденные нарушения целостности
+// @Description  Удаляет строки-сироты пачками по 1000, записывая ход работы в лог сервера.
+// @Description  С dry_run=true всё выполняется в откатываемой транзакции.
+// @Tags         admin
+// @Produce      json
+// @Security     AdminToken
+// @Param        dry_run  query  bool  false  "Пробный запуск"
+// @Success      200  {array}  core.IntegrityResult
+// @Failure      400  {object} ErrorResponse
+// @Failure      403  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /admin/integrity/repair [post]
+func (h *Handler) RepairIntegrity(w http.ResponseWriter, r *http.Request) {
+	dry, ok := dryRun(w, r)
+	if !ok {
+		return
+	}
+
+	results := make([]core.IntegrityResult, 0, len(core.IntegrityChecks))
+	err := h.apply(w, r, dry, func(ctx context.Context) error {
+		for _, check := range core.IntegrityChecks {
+			res, err := h.repairCheck(ctx, check)
+			if err != nil {
+				return err
+			}
+			results = append(results, res)
+		}
+		return nil
+	})
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to repair integrity")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, results)
+}
+
+func (h *Handler) repairCheck(ctx context.Context, check string) (core.IntegrityResult, error) {
+	res := core.IntegrityResult{Check: check}
+	found, err := h.Repo.CountIntegrity(ctx, check)
+	res.Found = found
+	if err != nil || found == 0 {
+		return res, err
+	}
+
+	for {
+		n, err := h.Repo.RepairIntegrity(ctx, check, integrityBatch)
+		if err != nil {
+			return res, err
+		}
+		res.Repaired += n
+		log.Printf("integrity: %s repaired %d of %d", check, res.Repaired, found)
+		if n < integrityBatch {
+			return res, nil
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"unicode/utf8"
+
+	"example.com/notes-api/internal/core"
+)
+
+// validateSize проверяет размеры заголовка, текста и шифртекста (nil — поле
+// не меняется) по limits. Превышение — 422: запрос корректен, но не может
+// быть сохранён.
+func validateSize(title, content *string, ciphertext []byte, limits core.Limits) (code, msg string) {
+	limits = limits.OrDefault()
+	if title != nil && utf8.RuneCountInString(*title) > limits.MaxTitle {
+		return CodeTitleTooLong, fmt.Sprintf("Title is too long: limit is %d characters", limits.MaxTitle)
+	}
+	if (content != nil && len(*content) > limits.MaxContent) || len(ciphertext) > limits.MaxContent {
+		return CodeContentTooLarge, fmt.Sprintf("Content is too large: limit is %d bytes", limits.MaxContent)
+	}
+	return "", ""
+}
+
+// validationStatus — HTTP-статус ошибки проверки с кодом code.
+func validationStatus(code string) int {
+	switch code {
+	case CodeTitleTooLong, CodeContentTooLarge:
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/notes-api/internal/auth"
+	"example.com/notes-api/internal/core"
+	"example.com/notes-api/internal/i18n"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+const (
+	defaultLockTTL = 5 * time.Minute
+	maxLockTTL     = time.Hour
+)
+
+// LockOwnerHeader — заголовок, которым клиент представляется при записи в
+// заблокированную заметку. Для вошедшего пользователя не учитывается, см. lockOwner.
+const LockOwnerHeader = "X-Lock-Owner"
+
+type LockedResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code" example:"note_locked"`
+	// RequestID совпадает с заголовком X-Request-ID.
+	RequestID string `json:"request_id,omitempty"`
+	// Lock — срок чужой блокировки; владелец не раскрывается.
+	Lock *core.NoteLock `json:"lock,omitempty"`
+}
+
+/*
+====================
+LOCK NOTE
+====================
+*/
+
+// LockNote godoc
+// @Summary      Заблокировать заметку для редактирования
+// @Description  Повторный вызов тем же owner продлевает блокировку. Для пользователя с токеном
+// @Description  владельцем считается он сам, owner из тела не учитывается.
+// @Tags         notes
+// @Accept       json
+// @Produce      json
+// @Param        id     path     int                   true  "ID"
+// @Param        input  body     core.NoteLockRequest  true  "Владелец и TTL"
+// @Success      200    {object} core.NoteLock
+// @Failure      400    {object} ErrorResponse
+// @Failure      404    {object} ErrorResponse
+// @Failure      423    {object} LockedResponse
+// @Failure      500    {object} ErrorResponse
+// @Router       /notes/{id}/lock [post]
+func (h *Handler) LockNote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
+		return
+	}
+
+	var req core.NoteLockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidJSON, "Invalid JSON")
+		return
+	}
+
+	owner := lockOwner(r, req.Owner)
+	if strings.TrimSpace(owner) == "" {
+		respondWithError(w, r, http.StatusBadRequest, CodeOwnerRequired, "Owner is required")
+		return
+	}
+
+	ttl := defaultLockTTL
+	if req.TTLSeconds < 0 {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid ttl_seconds")
+		return
+	}
+	if req.TTLSeconds > 0 {
+		ttl = min(time.Duration(req.TTLSeconds)*time.Second, maxLockTTL)
+	}
+
+	lock, err := h.Repo.Lock(r.Context(), id, owner, ttl)
+	if err != nil {
+		if errors.Is(err, core.ErrNoteLocked) {
+			respondLocked(w, r, lock)
+			return
+		}
+		if respondNotFound(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to lock note")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, lock)
+}
+
+/*
+====================
+UNLOCK NOTE
+====================
+*/
+
+// UnlockNote godoc
+// @Summary      Снять блокировку заметки
+// @Tags         notes
+// @Accept       json
+// @Param        id     path     int                   true  "ID"
+// @Param        input  body     core.NoteLockRequest  true  "Владелец блокировки"
+// @Success      204    "No Content"
+// @Failure      400    {object} ErrorResponse
+// @Failure      423    {object} LockedResponse
+// @Failure      500    {object} ErrorResponse
+// @Router       /notes/{id}/unlock [post]
+func (h *Handler) UnlockNote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
+		return
+	}
+
+	var req core.NoteLockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidJSON, "Invalid JSON")
+		return
+	}
+
+	if err := h.Repo.Unlock(r.Context(), id, lockOwner(r, req.Owner)); err != nil {
+		if errors.Is(err, core.ErrNoteLocked) {
+			respondWithError(w, r, http.StatusLocked, CodeNoteLocked, "Note is locked by another owner")
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to unlock note")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkLock отвечает 423 и возвращает false, если заметку держит не автор запроса.
+// Его вызывает каждый обработчик, который меняет заметку.
+func (h *Handler) checkLock(w http.ResponseWriter, r *http.Request, id int64) bool {
+	lock, err := h.lockConflict(r.Context(), r, id)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to check note lock")
+		return false
+	}
+	if lock != nil {
+		respondLocked(w, r, lock)
+		return false
+	}
+	return true
+}
+
+// lockConflict возвращает блокировку заметки id, если её держит не автор
+// запроса r, и nil, если писать можно. ctx — контекст запроса или пакета
+// с транзакцией.
+func (h *Handler) lockConflict(ctx context.Context, r *http.Request, id int64) (*core.NoteLock, error) {
+	lock, err := h.Repo.GetLock(ctx, id)
+	if err != nil || lock == nil {
+		return nil, err
+	}
+	if lock.Owner == lockOwner(r, r.Header.Get(LockOwnerHeader)) {
+		return nil, nil
+	}
+	return lock, nil
+}
+
+// lockOwner — владелец блокировки от имени запроса. У пользователя с токеном
+// (RequireJWT) это его ID, а claimed (X-Lock-Owner или owner из тела) не
+// учитывается: иначе любой мог бы назваться владельцем. Без токена —
+// claimed как есть.
+func lockOwner(r *http.Request, claimed string) string {
+	if id, ok := auth.UserID(r.Context()); ok {
+		return "user:" + strconv.FormatInt(id, 10)
+	}
+	return claimed
+}
+
+// respondLocked отвечает 423 со сроком блокировки lock без её владельца.
+func respondLocked(w http.ResponseWriter, r *http.Request, lock *core.NoteLock) {
+	resp := LockedResponse{
+		Error:     i18n.Message(r, "Note is locked"),
+		Code:      CodeNoteLocked,
+		RequestID: middleware.GetReqID(r.Context()),
+	}
+	if lock != nil {
+		resp.Lock = &core.NoteLock{NoteID: lock.NoteID, ExpiresAt: lock.ExpiresAt}
+	}
+	respondWithJSON(w, http.StatusLocked, resp)
+}
@@ -0,0 +1,147 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	httpx "example.com/notes-api/internal/http"
+	"example.com/notes-api/internal/http/handlers"
+	"example.com/notes-api/internal/repo"
+	"example.com/notes-api/internal/testutil"
+)
+
+func TestLockResponses(t *testing.T) {
+	s := testutil.NewMemoryServer(t, httpx.Config{AdminToken: adminToken})
+
+	runGolden(t, s, []goldenStep{
+		{"create", http.MethodPost, "/api/v1/notes", `{"title":"Общая"}`, http.StatusCreated, ""},
+		{"lock", http.MethodPost, "/api/v1/notes/1/lock", `{"owner":"alice","ttl_seconds":60}`, http.StatusOK, "lock_acquire"},
+		{"extend", http.MethodPost, "/api/v1/notes/1/lock", `{"owner":"alice"}`, http.StatusOK, "lock_extend"},
+		{"lock taken", http.MethodPost, "/api/v1/notes/1/lock", `{"owner":"bob"}`, http.StatusLocked, "lock_taken"},
+		{"write locked", http.MethodPatch, "/api/v1/notes/1", `{"content":"правка"}`, http.StatusLocked, "lock_write_rejected"},
+		{"unlock by other", http.MethodPost, "/api/v1/notes/1/unlock", `{"owner":"bob"}`, http.StatusLocked, "lock_unlock_rejected"},
+		{"unlock", http.MethodPost, "/api/v1/notes/1/unlock", `{"owner":"alice"}`, http.StatusNoContent, ""},
+		{"write unlocked", http.MethodPatch, "/api/v1/notes/1", `{"content":"правка"}`, http.StatusOK, "lock_write_unlocked"},
+	})
+}
+
+func TestNoteLocks(t *testing.T) {
+	s := testutil.NewServer(t, &handlers.Handler{Repo: repo.NewNoteRepoMemory()}, httpx.Config{AdminToken: adminToken})
+
+	s.Request(http.MethodPost, "/api/v1/notes/999/lock").
+		JSON(map[string]string{"owner": "alice"}).
+		Do(t).AssertStatus(t, http.StatusNotFound)
+
+	resp := s.Request(http.MethodPost, "/api/v1/notes").JSON(map[string]string{"title": "Общая"}).Do(t)
+	resp.AssertStatus(t, http.StatusCreated)
+	var note handlers.NoteResponse
+	resp.Decode(t, &note)
+	path := note.Links.Self
+
+	s.Request(http.MethodPost, path+"/lock").
+		JSON(map[string]string{"owner": "alice"}).
+		Do(t).AssertStatus(t, http.StatusOK)
+
+	writes := []struct {
+		method, path string
+		body         any
+	}{
+		{http.MethodPatch, path, map[string]string{"content": "правка"}},
+		{http.MethodPost, path + "/move", map[string]any{}},
+		{http.MethodPost, path + "/review", map[string]string{"reviewer": "bob"}},
+		{http.MethodPost, "/api/v1/admin/notes/" + strconv.FormatInt(note.ID, 10) + "/hold", nil},
+		{http.MethodDelete, path, nil},
+	}
+	for _, tt := range writes {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := s.Request(tt.method, tt.path).Header("Authorization", "Bearer "+adminToken)
+			if tt.body != nil {
+				req.JSON(tt.body)
+			}
+			req.Do(t).AssertStatus(t, http.StatusLocked)
+			if resp := req.Header(handlers.LockOwnerHeader, "alice").Do(t); resp.Status == http.StatusLocked {
+				t.Errorf("lock owner is rejected: %s", resp.Body)
+			}
+		})
+	}
+
+	s.Request(http.MethodPost, "/api/v1/admin/notes/"+strconv.FormatInt(note.ID, 10)+"/restore").
+		Header("Authorization", "Bearer "+adminToken).
+		Do(t).AssertStatus(t, http.StatusLocked)
+	s.Request(http.MethodPost, path+"/lock").
+		JSON(map[string]string{"owner": "bob"}).
+		Do(t).AssertStatus(t, http.StatusLocked)
+}
+
+// TestNoteLocksJWT: с токенами владелец блокировки — пользователь, а не
+// X-Lock-Owner; ответ 423 не раскрывает владельца.
+func TestNoteLocksJWT(t *testing.T) {
+	tokens := newTokens(t)
+	s := testutil.NewServer(t, &handlers.Handler{Repo: repo.NewNoteRepoMemory()}, httpx.Config{JWT: tokens})
+	alice, bob := issue(t, tokens, 1), issue(t, tokens, 2)
+
+	resp := s.Request(http.MethodPost, "/api/v1/notes").
+		Header("Authorization", "Bearer "+alice).
+		JSON(map[string]string{"title": "Общая"}).
+		Do(t)
+	resp.AssertStatus(t, http.StatusCreated)
+	var note handlers.NoteResponse
+	resp.Decode(t, &note)
+	path := note.Links.Self
+
+	// owner из тела не нужен и не учитывается
+	resp = s.Request(http.MethodPost, path+"/lock").
+		Header("Authorization", "Bearer "+alice).
+		JSON(map[string]string{"owner": "bob"}).
+		Do(t)
+	resp.AssertStatus(t, http.StatusOK)
+	var lock struct{ Owner string }
+	resp.Decode(t, &lock)
+	if lock.Owner != "user:1" {
+		t.Fatalf("owner = %q, want user:1", lock.Owner)
+	}
+
+	for _, owner := range []string{"", "user:1", "bob"} {
+		t.Run("bob as "+strconv.Quote(owner), func(t *testing.T) {
+			req := s.Request(http.MethodPatch, path).
+				Header("Authorization", "Bearer "+bob).
+				JSON(map[string]string{"content": "правка"})
+			if owner != "" {
+				req.Header(handlers.LockOwnerHeader, owner)
+			}
+			resp := req.Do(t)
+			resp.AssertStatus(t, http.StatusLocked)
+			if strings.Contains(string(resp.Body), "owner") {
+				t.Errorf("423 body reveals the owner: %s", resp.Body)
+			}
+			var body handlers.LockedResponse
+			resp.Decode(t, &body)
+			if body.Lock == nil || body.Lock.ExpiresAt.IsZero() {
+				t.Errorf("lock = %+v, want expiry", body.Lock)
+			}
+		})
+	}
+
+	resp = s.Request(http.MethodPatch, "/api/v1/notes?atomic=false").
+		Header("Authorization", "Bearer "+bob).
+		Header(handlers.LockOwnerHeader, "user:1").
+		JSON([]map[string]any{{"id": note.ID, "changes": map[string]string{"content": "правка"}}}).
+		Do(t)
+	resp.AssertStatus(t, http.StatusOK)
+	var batch handlers.NoteBatchResponse
+	resp.Decode(t, &batch)
+	if len(batch.Results) != 1 || batch.Results[0].Status != http.StatusLocked {
+		t.Errorf("batch results = %+v, want 423", batch.Results)
+	}
+
+	s.Request(http.MethodPost, path+"/lock").
+		Header("Authorization", "Bearer "+bob).
+		JSON(map[string]string{"owner": "user:1"}).
+		Do(t).AssertStatus(t, http.StatusLocked)
+	s.Request(http.MethodPatch, path).
+		Header("Authorization", "Bearer "+alice).
+		JSON(map[string]string{"content": "правка"}).
+		Do(t).AssertStatus(t, http.StatusOK)
+}
@@ -1,22 +1,75 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"example.com/notes-api/internal/auth"
+	"example.com/notes-api/internal/backup"
+	"example.com/notes-api/internal/changes"
+	"example.com/notes-api/internal/clock"
 	"example.com/notes-api/internal/core"
-	"example.com/notes-api/internal/repo"
+	"example.com/notes-api/internal/dedupe"
+	"example.com/notes-api/internal/i18n"
+	"example.com/notes-api/internal/lang"
+	"example.com/notes-api/internal/proofread"
+	"example.com/notes-api/internal/retention"
+	"example.com/notes-api/internal/textnorm"
+	"example.com/notes-api/internal/translate"
+	"example.com/notes-api/internal/views"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 )
 
 type Handler struct {
-	Repo *repo.NoteRepoPG
+	Repo    Repository
+	Changes *changes.Feed
+	Views   *views.Recorder
+	Dedupe  *dedupe.Window
+
+	// Users — учётные записи; nil — регистрация и /me выключены.
+	Users core.UserRepository
+	// LoginLockout ограничивает подбор паролей к /me и /auth/login; nil — без ограничения.
+	LoginLockout *auth.Lockout
+	// Tokens выпускает токены /auth/login; nil — вход по токену выключен.
+	Tokens *auth.JWT
+
+	Retention *retention.Engine
+	Backups   *backup.Runner
+	// Proofreader — проверка правописания; nil — выключена.
+	Proofreader proofread.Checker
+	// Translator — перевод заметок; nil — выключен.
+	Translator translate.Translator
+
+	// DailyTemplate — текст новой ежедневной заметки; {date} заменяется на дату.
+	DailyTemplate string
+
+	// Clock — часы для проверки сроков (expires_at); nil — системные.
+	Clock clock.Clock
+	// Limits — предельные размеры заметки; нулевые поля — core.DefaultLimits.
+	Limits core.Limits
+}
+
+// now возвращает текущее время по h.Clock.
+func (h *Handler) now() time.Time {
+	if h.Clock == nil {
+		return time.Now()
+	}
+	return h.Clock.Now()
 }
 
 type ErrorResponse struct {
 	Error string `json:"error"`
+	// Code — стабильный машиночитаемый код ошибки, см. errcodes.go.
+	// RequestID совпадает с заголовком X-Request-ID и записью в логе.
+	RequestID string `json:"request_id,omitempty"`
+	Code      string `json:"code" example:"title_required" enums:"internal_error,invalid_body,invalid_json,invalid_note_id,invalid_parameter,no_fields,note_not_found,title_required,content_required,invalid_metadata,invalid_color,invalid_icon,invalid_location,invalid_expiry,invalid_encryption,invalid_move,invalid_announcement,invalid_review_state,reviewer_required,not_reviewer,owner_required,note_locked,version_conflict,legal_hold,note_encrypted,not_configured,upstream_failed,batch_aborted,unsupported_encoding,admin_required,too_many_attempts,invalid_signature,read_only,token_required,invalid_token,title_taken,title_too_long,content_too_large,invalid_email,invalid_name,invalid_password,email_taken,auth_required,invalid_credentials"`
 }
 
 type SuccessResponse struct {
@@ -34,37 +87,56 @@ CREATE NOTE
 // @Tags         notes
 // @Accept       json
 // @Produce      json
+// @Description  Если включена дедупликация, одинаковое тело от того же клиента в пределах окна
+// @Description  возвращает уже созданную заметку с заголовком X-Deduplicated: true.
 // @Param        input  body     core.NoteCreate  true  "Данные новой заметки"
-// @Success      201    {object} core.Note
-// @Failure      400    {object} map[string]string
-// @Failure      500    {object} map[string]string
+// @Success      201    {object} NoteResponse
+// @Failure      400    {object} ErrorResponse
+// @Failure      409    {object} TitleTakenResponse  "Заголовок занят (если включена уникальность заголовков)"
+// @Failure      422    {object} ErrorResponse  "Заголовок или текст длиннее лимита"
+// @Failure      500    {object} ErrorResponse
 // @Router       /notes [post]
+// @Router       /integrations/notes [post]
 func (h *Handler) CreateNote(w http.ResponseWriter, r *http.Request) {
-	var req core.NoteCreate
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidBody, "Failed to read request body")
+		return
+	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid JSON")
+	var req core.NoteCreate
+	if err := json.Unmarshal(body, &req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidJSON, "Invalid JSON")
 		return
 	}
 
-	if strings.TrimSpace(req.Title) == "" {
-		respondWithError(w, http.StatusBadRequest, "Title is required")
+	if code, msg := ValidateCreate(req, h.now(), h.Limits); code != "" {
+		respondWithError(w, r, validationStatus(code), code, msg)
 		return
 	}
 
-	id, err := h.Repo.Create(r.Context(), req)
+	id, dup, err := h.createOnce(r, body, req)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to create note")
+		if respondTitleTaken(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to create note")
 		return
 	}
 
 	note, err := h.Repo.GetByID(r.Context(), id)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve created note")
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to retrieve created note")
 		return
 	}
 
-	respondWithJSON(w, http.StatusCreated, note)
+	if dup {
+		w.Header().Set("X-Deduplicated", "true")
+	} else {
+		h.publish(id, changes.NoteCreated)
+	}
+
+	respondWithJSON(w, http.StatusCreated, newNoteResponse(note))
 }
 
 /*
@@ -77,25 +149,30 @@ GET NOTE BY ID
 // @Summary      Получить заметку
 // @Tags         notes
 // @Param        id   path   int  true  "ID"
-// @Success      200  {object} core.Note
-// @Failure      400  {object} map[string]string
-// @Failure      500  {object} map[string]string
+// @Success      200  {object} NoteResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse
 // @Router       /notes/{id} [get]
 func (h *Handler) GetNote(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid note ID")
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
 		return
 	}
 
 	note, err := h.Repo.GetByID(r.Context(), id)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to get note")
+		if respondNotFound(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get note")
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, note)
+	h.recordView(r, id)
+	respondWithJSON(w, http.StatusOK, newNoteResponse(note))
 }
 
 /*
@@ -106,16 +183,80 @@ LIST NOTES
 
 // ListNotes godoc
 // @Summary      Список заметок
+// @Description  Параметры вида meta.<ключ>=<значение> фильтруют по metadata (вложенные ключи через точку).
 // @Tags         notes
-// @Success      200  {array} core.Note
+// @Param        meta.key  query  string  false  "Фильтр по metadata"
+// @Param        color     query  string  false  "Фильтр по цвету"
+// @Param        lang      query  string  false  "Фильтр по языку (ru, uk, en, de, fr, es)"
+// @Param        sort      query  string  false  "Порядок: created (по умолчанию) или manual"
+// @Param        review_state  query  string  false  "Фильтр по состоянию согласования: draft, in_review, published"
+// @Param        archived  query  bool    false  "Показать архивные заметки"
+// @Success      200  {array} NoteResponse
+// @Failure      400  {object} ErrorResponse
 // @Router       /notes [get]
 func (h *Handler) ListNotes(w http.ResponseWriter, r *http.Request) {
-	notes, err := h.Repo.GetAll(r.Context())
+	var filter core.NoteFilter
+
+	meta := map[string]string{}
+	for key, values := range r.URL.Query() {
+		if name, ok := strings.CutPrefix(key, "meta."); ok {
+			meta[name] = values[0]
+		}
+	}
+	metaFilter, err := core.MetadataFilter(meta)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to list notes")
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidMetadata, "Invalid metadata filter: "+err.Error())
 		return
 	}
-	respondWithJSON(w, http.StatusOK, notes)
+	filter.Metadata = metaFilter
+
+	if color := r.URL.Query().Get("color"); color != "" {
+		if !core.ValidColor(color) {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidColor, "Invalid color")
+			return
+		}
+		filter.Color = color
+	}
+
+	if code := r.URL.Query().Get("lang"); code != "" {
+		if !lang.Valid(code) {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid lang")
+			return
+		}
+		filter.Lang = code
+	}
+
+	if s := r.URL.Query().Get("archived"); s != "" {
+		archived, err := strconv.ParseBool(s)
+		if err != nil {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid archived")
+			return
+		}
+		filter.Archived = archived
+	}
+
+	if state := r.URL.Query().Get("review_state"); state != "" {
+		if !core.ValidReviewState(state) {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid review_state")
+			return
+		}
+		filter.ReviewState = state
+	}
+
+	switch sort := r.URL.Query().Get("sort"); sort {
+	case "", core.SortCreated, core.SortManual:
+		filter.Sort = sort
+	default:
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid sort")
+		return
+	}
+
+	notes, err := h.Repo.List(r.Context(), filter)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to list notes")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, newNoteResponses(notes))
 }
 
 /*
@@ -126,50 +267,159 @@ PATCH NOTE
 
 // PatchNote godoc
 // @Summary      Обновить заметку (частично)
+// @Description  Если передан base_version и заметка уже изменена на сервере, возвращает 409
+// @Description  с обеими версиями и, при наличии base, результатом трёхстороннего слияния.
 // @Tags         notes
 // @Accept       json
 // @Param        id     path   int              true  "ID"
 // @Param        input  body   core.NoteUpdate  true  "Поля для обновления"
-// @Success      200    {object} core.Note
-// @Failure      400    {object} map[string]string
-// @Failure      500    {object} map[string]string
+// @Param        X-Lock-Owner  header  string   false "Владелец блокировки"
+// @Success      200    {object} NoteResponse
+// @Failure      400    {object} ErrorResponse
+// @Failure      404    {object} ErrorResponse
+// @Failure      409    {object} ConflictResponse  "Версия изменилась; TitleTakenResponse, если заголовок занят"
+// @Failure      422    {object} ErrorResponse  "Заголовок или текст длиннее лимита"
+// @Failure      423    {object} LockedResponse
+// @Failure      500    {object} ErrorResponse
 // @Router       /notes/{id} [patch]
 func (h *Handler) PatchNote(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid note ID")
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
 		return
 	}
 
 	var update core.NoteUpdate
 	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid JSON")
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidJSON, "Invalid JSON")
+		return
+	}
+
+	if update.Empty() {
+		respondWithError(w, r, http.StatusBadRequest, CodeNoFields, "No fields to update")
 		return
 	}
 
-	if update.Title == nil && update.Content == nil {
-		respondWithError(w, http.StatusBadRequest, "No fields to update")
+	if code, msg := h.validateUpdate(update); code != "" {
+		respondWithError(w, r, validationStatus(code), code, msg)
+		return
+	}
+
+	current, err := h.Repo.GetByID(r.Context(), id)
+	if err != nil {
+		if respondNotFound(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get note")
 		return
 	}
 
-	if update.Title != nil && strings.TrimSpace(*update.Title) == "" {
-		respondWithError(w, http.StatusBadRequest, "Title cannot be empty")
+	if msg := validateEncryptedUpdate(current, update); msg != "" {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidEncryption, msg)
+		return
+	}
+
+	if !h.checkLock(w, r, id) {
 		return
 	}
 
 	if err := h.Repo.Update(r.Context(), id, update); err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to update note")
+		if errors.Is(err, core.ErrVersionConflict) {
+			h.respondConflict(w, r, id, update)
+			return
+		}
+		if respondTitleTaken(w, r, err) || respondNotFound(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to update note")
 		return
 	}
 
+	h.publish(id, changes.NoteUpdated)
+
 	note, err := h.Repo.GetByID(r.Context(), id)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve updated note")
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to retrieve updated note")
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, note)
+	respondWithJSON(w, http.StatusOK, newNoteResponse(note))
+}
+
+// ValidateCreate проверяет новую заметку так же, как POST /notes, и возвращает
+// код и текст ошибки (пустой код — заметка корректна). Нужна и вне HTTP:
+// тем же правилам подчиняются заметки из очереди сообщений. Нулевые поля
+// limits — core.DefaultLimits.
+func ValidateCreate(req core.NoteCreate, now time.Time, limits core.Limits) (code, msg string) {
+	if textnorm.Title(req.Title) == "" {
+		return CodeTitleRequired, "Title is required"
+	}
+	if code, msg := validateSize(&req.Title, &req.Content, req.Ciphertext, limits); code != "" {
+		return code, msg
+	}
+	if len(req.Metadata) > 0 {
+		if err := core.ValidateMetadata(req.Metadata); err != nil {
+			return CodeInvalidMetadata, "Invalid metadata: " + err.Error()
+		}
+	}
+	if req.Color != "" && !core.ValidColor(req.Color) {
+		return CodeInvalidColor, "Invalid color"
+	}
+	if !core.ValidIcon(req.Icon) {
+		return CodeInvalidIcon, "Invalid icon"
+	}
+	if !validLocation(req.Latitude, req.Longitude) {
+		return CodeInvalidLocation, "Invalid location"
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return CodeInvalidExpiry, "expires_at must be in the future"
+	}
+	if msg := validateEncryptedCreate(req); msg != "" {
+		return CodeInvalidEncryption, msg
+	}
+	return "", ""
+}
+
+// validateUpdate проверяет поля изменения, не требующие текущей заметки, и
+// подставляет цвет по умолчанию вместо пустого. Возвращает код и текст ошибки
+// или пустой код.
+func (h *Handler) validateUpdate(update core.NoteUpdate) (code, msg string) {
+	if update.Title != nil && textnorm.Title(*update.Title) == "" {
+		return CodeTitleRequired, "Title cannot be empty"
+	}
+	if code, msg := validateSize(update.Title, update.Content, update.Ciphertext, h.Limits); code != "" {
+		return code, msg
+	}
+
+	if update.Metadata != nil {
+		if err := core.ValidateMetadata(update.Metadata); err != nil {
+			return CodeInvalidMetadata, "Invalid metadata: " + err.Error()
+		}
+	}
+
+	if update.Color != nil {
+		if *update.Color == "" {
+			*update.Color = core.DefaultColor
+		}
+		if !core.ValidColor(*update.Color) {
+			return CodeInvalidColor, "Invalid color"
+		}
+	}
+
+	if update.Icon != nil && !core.ValidIcon(*update.Icon) {
+		return CodeInvalidIcon, "Invalid icon"
+	}
+
+	if !validLocation(update.Latitude, update.Longitude) ||
+		(update.ClearLocation && update.Latitude != nil) {
+		return CodeInvalidLocation, "Invalid location"
+	}
+
+	if update.ExpiresAt != nil && (update.ClearExpiry || !update.ExpiresAt.After(h.now())) {
+		return CodeInvalidExpiry, "expires_at must be in the future"
+	}
+	return "", ""
 }
 
 /*
@@ -180,25 +430,54 @@ DELETE NOTE
 
 // DeleteNote godoc
 // @Summary      Удалить заметку
+// @Description  С dry_run=true (или X-Dry-Run: true) отвечает так же, но заметку не удаляет.
 // @Tags         notes
 // @Param        id  path  int  true  "ID"
+// @Param        dry_run  query  bool  false  "Пробный запуск"
+// @Param        X-Lock-Owner  header  string  false  "Владелец блокировки"
 // @Success      204  "No Content"
-// @Failure      400  {object} map[string]string
-// @Failure      500  {object} map[string]string
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Failure      409  {object} ErrorResponse
+// @Failure      423  {object} LockedResponse
+// @Failure      500  {object} ErrorResponse
 // @Router       /notes/{id} [delete]
 func (h *Handler) DeleteNote(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid note ID")
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
+		return
+	}
+
+	dry, ok := dryRun(w, r)
+	if !ok {
+		return
+	}
+
+	if !h.checkLock(w, r, id) {
 		return
 	}
 
-	if err := h.Repo.Delete(r.Context(), id); err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to delete note")
+	err = h.apply(w, r, dry, func(ctx context.Context) error {
+		return h.Repo.Delete(ctx, id)
+	})
+	if err != nil {
+		if errors.Is(err, core.ErrLegalHold) {
+			respondWithError(w, r, http.StatusConflict, CodeLegalHold, "Note is under legal hold")
+			return
+		}
+		if respondNotFound(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to delete note")
 		return
 	}
 
+	if !dry {
+		h.publish(id, changes.NoteDeleted)
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -208,10 +487,26 @@ HELPERS
 ====================
 */
 
-func respondWithError(w http.ResponseWriter, code int, message string) {
+// respondWithError отдаёт ошибку на языке из Accept-Language запроса.
+func respondWithError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	w.Header().Add("Vary", "Accept-Language")
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	_ = json.NewEncoder(w).Encode(ErrorResponse{Error: message})
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(ErrorResponse{
+		Error:     i18n.Message(r, message),
+		Code:      code,
+		RequestID: middleware.GetReqID(r.Context()),
+	})
+}
+
+// respondNotFound отвечает 404, если err — core.ErrNotFound, и сообщает, был
+// ли ответ отправлен.
+func respondNotFound(w http.ResponseWriter, r *http.Request, err error) bool {
+	if !errors.Is(err, core.ErrNotFound) {
+		return false
+	}
+	respondWithError(w, r, http.StatusNotFound, CodeNoteNotFound, "Note not found")
+	return true
 }
 
 func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
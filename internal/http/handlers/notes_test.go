@@ -0,0 +1,109 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/notes-api/internal/dedupe"
+	httpx "example.com/notes-api/internal/http"
+	"example.com/notes-api/internal/http/handlers"
+	"example.com/notes-api/internal/repo"
+	"example.com/notes-api/internal/testutil"
+)
+
+func TestNoteResponses(t *testing.T) {
+	s := testutil.NewMemoryServer(t, httpx.Config{AdminToken: adminToken})
+	today := testutil.Now.Format("2006-01-02")
+
+	runGolden(t, s, []goldenStep{
+		{"create", http.MethodPost, "/api/v1/notes", `{"title":"Покупки","content":"Молоко","color":"yellow","metadata":{"list":"home"}}`, http.StatusCreated, "note_create"},
+		{"create second", http.MethodPost, "/api/v1/notes", `{"title":"Работа","content":"Отчёт","latitude":55.75,"longitude":37.61}`, http.StatusCreated, "note_create_second"},
+		{"get", http.MethodGet, "/api/v1/notes/1", ``, http.StatusOK, "note_get"},
+		{"get by slug", http.MethodGet, "/api/v1/notes/by-slug/pokupki", ``, http.StatusOK, "note_get_by_slug"},
+		{"get by title", http.MethodGet, "/api/v1/notes/by-title?title=%D0%A0%D0%B0%D0%B1%D0%BE%D1%82%D0%B0", ``, http.StatusOK, "note_get_by_title"},
+		{"list", http.MethodGet, "/api/v1/notes", ``, http.StatusOK, "note_list"},
+		{"list by metadata", http.MethodGet, "/api/v1/notes?meta.list=home", ``, http.StatusOK, "note_list_by_metadata"},
+		{"recent", http.MethodGet, "/api/v1/notes/recent", ``, http.StatusOK, "note_recent"},
+		{"nearby", http.MethodGet, "/api/v1/notes/nearby?lat=55.75&lon=37.61", ``, http.StatusOK, "note_nearby"},
+		{"patch", http.MethodPatch, "/api/v1/notes/1", `{"content":"Молоко, хлеб","base_version":1}`, http.StatusOK, "note_patch"},
+		{"patch version conflict", http.MethodPatch, "/api/v1/notes/1", `{"content":"Хлеб","base_version":1}`, http.StatusConflict, "note_patch_conflict"},
+		{"move", http.MethodPost, "/api/v1/notes/2/move", `{"before_id":1}`, http.StatusOK, "note_move"},
+		{"daily create", http.MethodPost, "/api/v1/notes/daily/" + today, ``, http.StatusCreated, "note_daily_create"},
+		{"daily get", http.MethodGet, "/api/v1/notes/daily/" + today, ``, http.StatusOK, "note_daily_get"},
+		{"delete", http.MethodDelete, "/api/v1/notes/2", ``, http.StatusNoContent, ""},
+		{"get deleted", http.MethodGet, "/api/v1/notes/2", ``, http.StatusNotFound, "note_get_deleted"},
+	})
+}
+
+func TestNoteLifecycle(t *testing.T) {
+	s := testutil.NewServer(t, &handlers.Handler{Repo: repo.NewNoteRepoMemory()}, httpx.Config{AdminToken: adminToken})
+
+	resp := s.Request(http.MethodPost, "/api/v1/notes").
+		JSON(map[string]string{"title": "Покупки", "content": "Молоко"}).
+		Do(t)
+	resp.AssertStatus(t, http.StatusCreated)
+	var created handlers.NoteResponse
+	resp.Decode(t, &created)
+	if created.Slug != "pokupki" || created.Version != 1 {
+		t.Fatalf("created = %+v", created)
+	}
+	path := created.Links.Self
+
+	resp = s.Request(http.MethodPatch, path).
+		JSON(map[string]any{"content": "Молоко, хлеб", "base_version": 1}).
+		Do(t)
+	resp.AssertStatus(t, http.StatusOK)
+	var patched handlers.NoteResponse
+	resp.Decode(t, &patched)
+	if patched.Content != "Молоко, хлеб" || patched.Version != 2 {
+		t.Fatalf("patched = %+v", patched)
+	}
+
+	s.Request(http.MethodPatch, path).
+		JSON(map[string]any{"content": "Хлеб", "base_version": 1}).
+		Do(t).AssertStatus(t, http.StatusConflict)
+
+	resp = s.Request(http.MethodGet, "/api/v1/notes").Do(t)
+	resp.AssertStatus(t, http.StatusOK)
+	if !strings.Contains(string(resp.Body), `"Молоко, хлеб"`) {
+		t.Errorf("list does not contain the note: %s", resp.Body)
+	}
+
+	s.Request(http.MethodDelete, path).Do(t).AssertStatus(t, http.StatusNoContent)
+
+	resp = s.Request(http.MethodGet, path).Do(t)
+	resp.AssertStatus(t, http.StatusNotFound)
+	var got handlers.ErrorResponse
+	resp.Decode(t, &got)
+	if got.Code != "note_not_found" {
+		t.Errorf("code = %q, want note_not_found", got.Code)
+	}
+}
+
+// TestDedupePerUser: окно дедупликации различает пользователей с одного IP.
+func TestDedupePerUser(t *testing.T) {
+	tokens := newTokens(t)
+	s := testutil.NewServer(t, &handlers.Handler{
+		Repo:   repo.NewNoteRepoMemory(),
+		Dedupe: dedupe.NewWindow(time.Minute),
+	}, httpx.Config{JWT: tokens})
+
+	create := func(userID int64) *testutil.Response {
+		resp := s.Request(http.MethodPost, "/api/v1/notes").
+			Header("Authorization", "Bearer "+issue(t, tokens, userID)).
+			JSON(map[string]string{"title": "Покупки"}).
+			Do(t)
+		resp.AssertStatus(t, http.StatusCreated)
+		return resp
+	}
+
+	create(1)
+	if resp := create(2); resp.Header.Get("X-Deduplicated") != "" {
+		t.Error("another user's create is deduplicated")
+	}
+	if resp := create(1); resp.Header.Get("X-Deduplicated") != "true" {
+		t.Error("repeated create of the same user is not deduplicated")
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultOverviewLimit = 5
+	maxOverviewLimit     = 20
+
+	// overviewExpiryWindow — насколько вперёд смотреть на истекающие заметки.
+	overviewExpiryWindow = 7 * 24 * time.Hour
+)
+
+// OverviewResponse — данные для главного экрана.
+type OverviewResponse struct {
+	RecentlyUpdated []NoteResponse `json:"recently_updated"`
+	RecentlyViewed  []NoteResponse `json:"recently_viewed"`
+	// ExpiringSoon — заметки, которые удалятся в ближайшие 7 дней.
+	ExpiringSoon []NoteResponse `json:"expiring_soon"`
+}
+
+/*
+====================
+OVERVIEW
+====================
+*/
+
+// Overview godoc
+// @Summary      Сводка для главного экрана
+// @Description  Недавно изменённые, недавно просмотренные и скоро истекающие заметки одним запросом.
+// @Description  Выборки выполняются параллельно; limit относится к каждому списку.
+// @Tags         notes
+// @Produce      json
+// @Param        limit  query    int  false  "Размер каждого списка (по умолчанию 5, максимум 20)"
+// @Success      200    {object} OverviewResponse
+// @Failure      400    {object} ErrorResponse
+// @Failure      500    {object} ErrorResponse
+// @Router       /overview [get]
+func (h *Handler) Overview(w http.ResponseWriter, r *http.Request) {
+	limit := defaultOverviewLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid limit")
+			return
+		}
+		limit = min(v, maxOverviewLimit)
+	}
+
+	var resp OverviewResponse
+	g, ctx := errgroup.WithContext(r.Context())
+	g.Go(func() error {
+		notes, err := h.Repo.ListRecentlyUpdated(ctx, limit)
+		resp.RecentlyUpdated = newNoteResponses(notes)
+		return err
+	})
+	g.Go(func() error {
+		notes, err := h.Repo.ListRecentlyViewed(ctx, limit)
+		resp.RecentlyViewed = newNoteResponses(notes)
+		return err
+	})
+	g.Go(func() error {
+		notes, err := h.Repo.ListExpiringBefore(ctx, h.now().Add(overviewExpiryWindow), limit)
+		resp.ExpiringSoon = newNoteResponses(notes)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to build overview")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"example.com/notes-api/internal/changes"
+	"example.com/notes-api/internal/core"
+	"github.com/go-chi/chi/v5"
+)
+
+/*
+====================
+MOVE NOTE
+====================
+*/
+
+// MoveNote godoc
+// @Summary      Переставить заметку в ручном порядке
+// @Description  Ставит заметку после after_id или перед before_id; без якоря — в начало списка.
+// @Tags         notes
+// @Accept       json
+// @Produce      json
+// @Param        id     path     int            true  "ID"
+// @Param        input  body     core.NoteMove  true  "Якорь"
+// @Param        X-Lock-Owner  header  string  false  "Владелец блокировки"
+// @Success      200    {object} NoteResponse
+// @Failure      400    {object} ErrorResponse
+// @Failure      404    {object} ErrorResponse
+// @Failure      423    {object} LockedResponse
+// @Failure      500    {object} ErrorResponse
+// @Router       /notes/{id}/move [post]
+func (h *Handler) MoveNote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
+		return
+	}
+
+	var move core.NoteMove
+	if err := json.NewDecoder(r.Body).Decode(&move); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidJSON, "Invalid JSON")
+		return
+	}
+
+	if move.AfterID != nil && move.BeforeID != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidMove, "Only one of after_id and before_id is allowed")
+		return
+	}
+
+	if !h.checkLock(w, r, id) {
+		return
+	}
+	if err := h.Repo.Move(r.Context(), id, move); err != nil {
+		if errors.Is(err, core.ErrInvalidMove) {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidMove, "Invalid move target")
+			return
+		}
+		if respondNotFound(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to move note")
+		return
+	}
+
+	note, err := h.Repo.GetByID(r.Context(), id)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to retrieve moved note")
+		return
+	}
+
+	h.publish(id, changes.NoteUpdated)
+	respondWithJSON(w, http.StatusOK, newNoteResponse(note))
+}
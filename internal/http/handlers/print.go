@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// printTemplate — самодостаточная страница для печати; html/template экранирует
+// заголовок и текст, поэтому разметка из заметки не исполняется.
+var printTemplate = template.Must(template.New("print").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font: 16px/1.5 Georgia, "Times New Roman", serif; max-width: 42em; margin: 2em auto; padding: 0 1em; color: #111; }
+h1 { font-size: 1.6em; margin: 0 0 .8em; }
+.content { white-space: pre-wrap; overflow-wrap: break-word; }
+.encrypted { font-style: italic; color: #666; }
+footer { margin-top: 2em; padding-top: .5em; border-top: 1px solid #ccc; font-size: .8em; color: #666; }
+@media print { body { margin: 0; max-width: none; } }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Encrypted}}<p class="encrypted">Содержимое зашифровано на клиенте и недоступно серверу.</p>
+{{else}}<div class="content">{{.Content}}</div>
+{{end}}<footer>Создано {{.CreatedAt}}{{with .UpdatedAt}} · изменено {{.}}{{end}}</footer>
+</body>
+</html>
+`))
+
+type printView struct {
+	Title     string
+	Content   string
+	Encrypted bool
+	CreatedAt string
+	UpdatedAt string
+}
+
+/*
+====================
+PRINT VIEW
+====================
+*/
+
+// PrintNote godoc
+// @Summary      Версия для печати
+// @Description  Самодостаточная HTML-страница со встроенными стилями: для печати из браузера и вставки в письма.
+// @Tags         notes
+// @Produce      html
+// @Param        id   path     int  true  "ID"
+// @Success      200  {string} string "HTML"
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /notes/{id}/print [get]
+func (h *Handler) PrintNote(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
+		return
+	}
+
+	note, err := h.Repo.GetByID(r.Context(), id)
+	if err != nil {
+		if respondNotFound(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get note")
+		return
+	}
+
+	view := printView{
+		Title:     note.Title,
+		Content:   note.Content,
+		Encrypted: note.Encrypted,
+		CreatedAt: note.CreatedAt.Format(time.DateOnly),
+	}
+	if note.UpdatedAt != nil {
+		view.UpdatedAt = note.UpdatedAt.Format(time.DateOnly)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+	_ = printTemplate.Execute(w, view)
+}
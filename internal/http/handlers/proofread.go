@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"example.com/notes-api/internal/proofread"
+	"github.com/go-chi/chi/v5"
+)
+
+// ProofreadResponse — ошибки в content заметки, найденные проверкой правописания.
+type ProofreadResponse struct {
+	NoteID int64 `json:"note_id" example:"1"`
+	// Version — версия, content которой проверялся: позиции относятся к ней.
+	Version int64 `json:"version" example:"3"`
+	// Lang — язык, на котором шла проверка; auto — определён сервисом.
+	Lang        string                 `json:"lang" example:"ru"`
+	Suggestions []proofread.Suggestion `json:"suggestions"`
+}
+
+/*
+====================
+PROOFREAD NOTE
+====================
+*/
+
+// ProofreadNote godoc
+// @Summary      Проверить правописание заметки
+// @Description  Проверяет content внешним сервисом (LanguageTool) и возвращает ошибки с позициями. Заметка не меняется.
+// @Tags         notes
+// @Produce      json
+// @Param        id   path     int  true  "ID"
+// @Success      200  {object} ProofreadResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Failure      409  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse
+// @Failure      501  {object} ErrorResponse
+// @Failure      502  {object} ErrorResponse
+// @Router       /notes/{id}/proofread [post]
+func (h *Handler) ProofreadNote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
+		return
+	}
+	if h.Proofreader == nil {
+		respondWithError(w, r, http.StatusNotImplemented, CodeNotConfigured, "Proofreading is not configured")
+		return
+	}
+
+	note, err := h.Repo.GetByID(r.Context(), id)
+	if err != nil {
+		if respondNotFound(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get note")
+		return
+	}
+	if note.Encrypted {
+		respondWithError(w, r, http.StatusConflict, CodeNoteEncrypted, "Encrypted note cannot be proofread on the server")
+		return
+	}
+
+	lang := note.Lang
+	if lang == "" {
+		lang = "auto"
+	}
+	suggestions, err := h.Proofreader.Check(r.Context(), note.Content, lang)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadGateway, CodeUpstreamFailed, "Proofreading service failed")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, ProofreadResponse{
+		NoteID:      note.ID,
+		Version:     note.Version,
+		Lang:        lang,
+		Suggestions: suggestions,
+	})
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"example.com/notes-api/internal/core"
+)
+
+// Repository — хранилище, которым пользуются обработчики: основные операции
+// core.NoteRepository и запросы отдельных возможностей API. Реализация —
+// repo.NoteRepoPG; для другого хранилища или теста достаточно этих методов.
+type Repository interface {
+	core.NoteRepository
+
+	// Транзакции
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	DryRun(ctx context.Context, fn func(ctx context.Context) error) error
+
+	// Чтение и поиск
+	GetBySlug(ctx context.Context, s string) (*core.Note, error)
+	FindByTitle(ctx context.Context, title string, prefix bool, limit int) ([]core.Note, error)
+	SearchTitles(ctx context.Context, query string, limit int) ([]core.Note, error)
+	ListCreatedAfter(ctx context.Context, afterID int64, limit int) ([]core.Note, error)
+	ListRecentlyUpdated(ctx context.Context, limit int) ([]core.Note, error)
+	ListRecentlyViewed(ctx context.Context, limit int) ([]core.Note, error)
+	ListExpiringBefore(ctx context.Context, until time.Time, limit int) ([]core.Note, error)
+	ListNearby(ctx context.Context, lat, lon, radius float64, limit int) ([]core.NearbyNote, error)
+	Calendar(ctx context.Context, from, to time.Time, tz string, perDay int) ([]core.CalendarDay, error)
+	NoteStats(ctx context.Context, id int64) (*core.NoteStats, error)
+	ListActivity(ctx context.Context, beforeID int64, limit int) ([]core.ActivityEntry, error)
+
+	// Ежедневные заметки
+	DailyNoteID(ctx context.Context, day string) (int64, error)
+	CreateDaily(ctx context.Context, day string, n core.NoteCreate) (id int64, created bool, err error)
+
+	// Блокировки, порядок и согласование
+	Lock(ctx context.Context, noteID int64, owner string, ttl time.Duration) (*core.NoteLock, error)
+	Unlock(ctx context.Context, noteID int64, owner string) error
+	GetLock(ctx context.Context, noteID int64) (*core.NoteLock, error)
+	Move(ctx context.Context, id int64, m core.NoteMove) error
+	Review(ctx context.Context, id int64, t core.ReviewTransition, reviewer string) error
+
+	// Администрирование
+	GetAnyByID(ctx context.Context, id int64) (*core.Note, error)
+	ListAllNotes(ctx context.Context, beforeID int64, limit int) ([]core.Note, error)
+	Restore(ctx context.Context, id int64) error
+	SetLegalHold(ctx context.Context, id int64, hold bool) error
+	SetAnnouncement(ctx context.Context, id int64, a *core.NoteAnnouncement) error
+	TableStats(ctx context.Context) ([]core.TableStats, error)
+	CountIntegrity(ctx context.Context, check string) (int64, error)
+	RepairIntegrity(ctx context.Context, check string, limit int) (int64, error)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/notes-api/internal/core"
+)
+
+// discardWriter — ResponseWriter без накладных расходов httptest.Recorder.
+type discardWriter struct{ header http.Header }
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (w *discardWriter) WriteHeader(int)             {}
+
+// benchPage — страница списка заметок типичного размера.
+func benchPage(count int) []core.Note {
+	now := time.Now().UTC()
+	notes := make([]core.Note, count)
+	for i := range notes {
+		notes[i] = core.Note{
+			ID:        int64(i + 1),
+			Title:     fmt.Sprintf("Note %d", i),
+			Content:   "Lorem ipsum dolor sit amet, consectetur adipiscing elit.",
+			Slug:      fmt.Sprintf("note-%d", i),
+			Version:   1,
+			Metadata:  json.RawMessage(`{"source":"bench","tags":["a","b"]}`),
+			Color:     core.DefaultColor,
+			CreatedAt: now,
+			UpdatedAt: &now,
+		}
+	}
+	return notes
+}
+
+var benchBuffers = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// BenchmarkRespondWithJSON сравнивает текущий respondWithJSON (с отступами)
+// с компактным кодированием и кодированием через пул буферов.
+func BenchmarkRespondWithJSON(b *testing.B) {
+	for _, size := range []int{1, 100} {
+		payload := newNoteResponses(benchPage(size))
+
+		b.Run(fmt.Sprintf("indent/%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			w := &discardWriter{header: http.Header{}}
+			for i := 0; i < b.N; i++ {
+				respondWithJSON(w, http.StatusOK, payload)
+			}
+		})
+
+		b.Run(fmt.Sprintf("compact/%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			w := &discardWriter{header: http.Header{}}
+			for i := 0; i < b.N; i++ {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_ = json.NewEncoder(w).Encode(payload)
+			}
+		})
+
+		b.Run(fmt.Sprintf("pooled/%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			w := &discardWriter{header: http.Header{}}
+			for i := 0; i < b.N; i++ {
+				buf := benchBuffers.Get().(*bytes.Buffer)
+				buf.Reset()
+				_ = json.NewEncoder(buf).Encode(payload)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write(buf.Bytes())
+				benchBuffers.Put(buf)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"example.com/notes-api/internal/changes"
+	"example.com/notes-api/internal/core"
+	"github.com/go-chi/chi/v5"
+)
+
+/*
+====================
+REVIEW WORKFLOW
+====================
+*/
+
+// DraftNote godoc
+// @Summary      Перевести заметку в черновик
+// @Description  Вводит заметку в процесс согласования или возвращает опубликованную на доработку.
+// @Tags         review
+// @Produce      json
+// @Param        id   path   int  true  "ID"
+// @Param        X-Lock-Owner  header  string  false  "Владелец блокировки"
+// @Success      200  {object} NoteResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Failure      409  {object} ErrorResponse
+// @Failure      423  {object} LockedResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /notes/{id}/draft [post]
+func (h *Handler) DraftNote(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, core.ReviewToDraft)
+}
+
+// SubmitNoteForReview godoc
+// @Summary      Отправить заметку на согласование
+// @Description  Назначает рецензента; одобрить или отклонить заметку может только он.
+// @Tags         review
+// @Accept       json
+// @Produce      json
+// @Param        id     path  int                 true  "ID"
+// @Param        input  body  core.ReviewRequest  true  "Рецензент"
+// @Param        X-Lock-Owner  header  string  false  "Владелец блокировки"
+// @Success      200  {object} NoteResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Failure      409  {object} ErrorResponse
+// @Failure      423  {object} LockedResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /notes/{id}/review [post]
+func (h *Handler) SubmitNoteForReview(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, core.ReviewSubmit)
+}
+
+// ApproveNote godoc
+// @Summary      Одобрить и опубликовать заметку
+// @Tags         review
+// @Accept       json
+// @Produce      json
+// @Param        id     path  int                 true  "ID"
+// @Param        input  body  core.ReviewRequest  true  "Назначенный рецензент"
+// @Param        X-Lock-Owner  header  string  false  "Владелец блокировки"
+// @Success      200  {object} NoteResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      403  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Failure      409  {object} ErrorResponse
+// @Failure      423  {object} LockedResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /notes/{id}/approve [post]
+func (h *Handler) ApproveNote(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, core.ReviewApprove)
+}
+
+// RejectNote godoc
+// @Summary      Отклонить заметку
+// @Description  Возвращает заметку в черновик; рецензент остаётся назначенным.
+// @Tags         review
+// @Accept       json
+// @Produce      json
+// @Param        id     path  int                 true  "ID"
+// @Param        input  body  core.ReviewRequest  true  "Назначенный рецензент"
+// @Param        X-Lock-Owner  header  string  false  "Владелец блокировки"
+// @Success      200  {object} NoteResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      403  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Failure      409  {object} ErrorResponse
+// @Failure      423  {object} LockedResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /notes/{id}/reject [post]
+func (h *Handler) RejectNote(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, core.ReviewReject)
+}
+
+func (h *Handler) review(w http.ResponseWriter, r *http.Request, t core.ReviewTransition) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
+		return
+	}
+
+	var req core.ReviewRequest
+	if t.Assign || t.ByReviewer {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidJSON, "Invalid JSON")
+			return
+		}
+		req.Reviewer = strings.TrimSpace(req.Reviewer)
+		if req.Reviewer == "" {
+			respondWithError(w, r, http.StatusBadRequest, CodeReviewerRequired, "Reviewer is required")
+			return
+		}
+	}
+
+	if !h.checkLock(w, r, id) {
+		return
+	}
+	if err := h.Repo.Review(r.Context(), id, t, req.Reviewer); err != nil {
+		switch {
+		case errors.Is(err, core.ErrNotFound):
+			respondWithError(w, r, http.StatusNotFound, CodeNoteNotFound, "Note not found")
+		case errors.Is(err, core.ErrReviewState):
+			respondWithError(w, r, http.StatusConflict, CodeInvalidReviewState, "Transition is not allowed from the current review state")
+		case errors.Is(err, core.ErrNotReviewer):
+			respondWithError(w, r, http.StatusForbidden, CodeNotReviewer, "Only the assigned reviewer can decide")
+		default:
+			respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to update review state")
+		}
+		return
+	}
+
+	note, err := h.Repo.GetByID(r.Context(), id)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get note")
+		return
+	}
+	h.publish(id, changes.NoteUpdated)
+	respondWithJSON(w, http.StatusOK, newNoteResponse(note))
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/*
+====================
+GET NOTE BY SLUG
+====================
+*/
+
+// GetNoteBySlug godoc
+// @Summary      Получить заметку по slug
+// @Tags         notes
+// @Produce      json
+// @Param        slug  path     string  true  "Slug"
+// @Success      200   {object} NoteResponse
+// @Failure      404   {object} ErrorResponse
+// @Failure      500   {object} ErrorResponse
+// @Router       /notes/by-slug/{slug} [get]
+func (h *Handler) GetNoteBySlug(w http.ResponseWriter, r *http.Request) {
+	note, err := h.Repo.GetBySlug(r.Context(), chi.URLParam(r, "slug"))
+	if err != nil {
+		if respondNotFound(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get note")
+		return
+	}
+
+	h.recordView(r, note.ID)
+	respondWithJSON(w, http.StatusOK, newNoteResponse(note))
+}
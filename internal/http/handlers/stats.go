@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/*
+====================
+NOTE STATS
+====================
+*/
+
+// NoteStats godoc
+// @Summary      Статистика заметки
+// @Description  Число изменений, их частота, просмотры и время последней правки.
+// @Tags         notes
+// @Produce      json
+// @Param        id   path     int  true  "ID"
+// @Success      200  {object} core.NoteStats
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /notes/{id}/stats [get]
+func (h *Handler) NoteStats(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
+		return
+	}
+
+	stats, err := h.Repo.NoteStats(r.Context(), id)
+	if err != nil {
+		if respondNotFound(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get note stats")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, stats)
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"example.com/notes-api/internal/core"
+	"example.com/notes-api/internal/keywords"
+	"github.com/go-chi/chi/v5"
+)
+
+// TagsKey — ключ metadata с тегами заметки: массив строк. Отдельной модели
+// тегов нет, теги проставляются через PATCH metadata.
+const TagsKey = "tags"
+
+const (
+	defaultTagSuggestions = 10
+	maxTagSuggestions     = 50
+	// tagVocabularyNotes — из скольких недавно изменённых заметок собирается
+	// словарь тегов.
+	tagVocabularyNotes = 500
+)
+
+// TagSuggestionsResponse — предложенные теги заметки.
+type TagSuggestionsResponse struct {
+	NoteID int64 `json:"note_id" example:"1"`
+	// Tags — теги, которые уже стоят на заметке; в подсказки они не попадают.
+	Tags        []string              `json:"tags"`
+	Suggestions []keywords.Suggestion `json:"suggestions"`
+}
+
+/*
+====================
+SUGGEST TAGS
+====================
+*/
+
+// SuggestNoteTags godoc
+// @Summary      Предложить теги заметки
+// @Description  Сначала теги из metadata.tags других заметок, которые встречаются в заголовке и content,
+// @Description  затем ключевые слова текста. Заметка не меняется.
+// @Tags         notes
+// @Produce      json
+// @Param        id     path     int  true   "ID"
+// @Param        limit  query    int  false  "Количество (по умолчанию 10, максимум 50)"
+// @Success      200    {object} TagSuggestionsResponse
+// @Failure      400    {object} ErrorResponse
+// @Failure      404    {object} ErrorResponse
+// @Failure      409    {object} ErrorResponse
+// @Failure      500    {object} ErrorResponse
+// @Router       /notes/{id}/suggest-tags [get]
+func (h *Handler) SuggestNoteTags(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
+		return
+	}
+	limit := defaultTagSuggestions
+	if s := r.URL.Query().Get("limit"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid limit")
+			return
+		}
+		limit = min(v, maxTagSuggestions)
+	}
+
+	note, err := h.Repo.GetByID(r.Context(), id)
+	if err != nil {
+		if respondNotFound(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get note")
+		return
+	}
+	if note.Encrypted {
+		respondWithError(w, r, http.StatusConflict, CodeNoteEncrypted, "Encrypted note cannot be tagged on the server")
+		return
+	}
+
+	recent, err := h.Repo.ListRecentlyUpdated(r.Context(), tagVocabularyNotes)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to list notes")
+		return
+	}
+
+	tags := noteTags(note)
+	text := note.Title + "\n" + note.Content
+	respondWithJSON(w, http.StatusOK, TagSuggestionsResponse{
+		NoteID:      note.ID,
+		Tags:        tags,
+		Suggestions: keywords.Suggest(text, note.Lang, tagVocabulary(recent), tags, limit),
+	})
+}
+
+// noteTags возвращает строки из metadata[TagsKey]; другие значения ключа
+// и не-строки в массиве пропускаются.
+func noteTags(n *core.Note) []string {
+	var meta map[string]json.RawMessage
+	if err := json.Unmarshal(n.Metadata, &meta); err != nil || meta[TagsKey] == nil {
+		return []string{}
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(meta[TagsKey], &raw); err != nil {
+		return []string{}
+	}
+	tags := make([]string, 0, len(raw))
+	for _, v := range raw {
+		var t string
+		if json.Unmarshal(v, &t) == nil && t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// tagVocabulary собирает теги notes без повторов: сначала самые частые, при
+// равенстве — встреченные раньше (в более свежих заметках).
+func tagVocabulary(notes []core.Note) []string {
+	counts := map[string]int{}
+	var vocabulary []string
+	for i := range notes {
+		for _, t := range noteTags(&notes[i]) {
+			if counts[t] == 0 {
+				vocabulary = append(vocabulary, t)
+			}
+			counts[t]++
+		}
+	}
+	sort.SliceStable(vocabulary, func(i, j int) bool { return counts[vocabulary[i]] > counts[vocabulary[j]] })
+	return vocabulary
+}
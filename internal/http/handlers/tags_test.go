@@ -0,0 +1,58 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	httpx "example.com/notes-api/internal/http"
+	"example.com/notes-api/internal/http/handlers"
+	"example.com/notes-api/internal/keywords"
+	"example.com/notes-api/internal/testutil"
+)
+
+func TestTagSuggestionResponses(t *testing.T) {
+	s := testutil.NewMemoryServer(t, httpx.Config{AdminToken: adminToken})
+
+	runGolden(t, s, []goldenStep{
+		{"tagged", http.MethodPost, "/api/v1/notes", `{"title":"Поездка в Казань","content":"Билеты","metadata":{"tags":["Поездка","билеты"]}}`, http.StatusCreated, ""},
+		{"tagged again", http.MethodPost, "/api/v1/notes", `{"title":"Отчёт","content":"Итоги квартала","metadata":{"tags":["работа","поездка"]}}`, http.StatusCreated, ""},
+		{"create", http.MethodPost, "/api/v1/notes", `{"title":"Поездка на море","content":"Купить билеты на поезд, забронировать отель. Отель у моря, поезд ночной.","metadata":{"tags":["море"]}}`, http.StatusCreated, ""},
+		{"suggest", http.MethodGet, "/api/v1/notes/3/suggest-tags", ``, http.StatusOK, "tags_suggest"},
+		{"suggest limited", http.MethodGet, "/api/v1/notes/3/suggest-tags?limit=3", ``, http.StatusOK, "tags_suggest_limited"},
+		{"untagged", http.MethodPost, "/api/v1/notes", `{"title":"Пусто","content":""}`, http.StatusCreated, ""},
+		{"suggest untagged", http.MethodGet, "/api/v1/notes/4/suggest-tags", ``, http.StatusOK, "tags_suggest_empty"},
+	})
+}
+
+func TestSuggestNoteTags(t *testing.T) {
+	s := testutil.NewMemoryServer(t, httpx.Config{})
+
+	s.Request(http.MethodPost, "/api/v1/notes").
+		Body(`{"title":"План","content":"Релиз","metadata":{"tags":["релиз",1,"",{"x":1}]}}`).
+		Do(t).AssertStatus(t, http.StatusCreated)
+	s.Request(http.MethodPost, "/api/v1/notes").
+		Body(`{"title":"Релиз 2.0","content":"Релиз в пятницу, релиз после тестов","metadata":{"tags":"релиз"}}`).
+		Do(t).AssertStatus(t, http.StatusCreated)
+
+	resp := s.Request(http.MethodGet, "/api/v1/notes/2/suggest-tags").Do(t)
+	resp.AssertStatus(t, http.StatusOK)
+	var got handlers.TagSuggestionsResponse
+	resp.Decode(t, &got)
+
+	// Строка вместо массива в metadata.tags — не теги; из массива берутся только непустые строки.
+	if len(got.Tags) != 0 {
+		t.Errorf("tags = %v, want none", got.Tags)
+	}
+	want := keywords.Suggestion{Tag: "релиз", Source: keywords.SourceVocabulary, Count: 3}
+	if len(got.Suggestions) == 0 || got.Suggestions[0] != want {
+		t.Errorf("suggestions = %+v, want %+v first", got.Suggestions, want)
+	}
+
+	s.Request(http.MethodPost, "/api/v1/notes").
+		Body(`{"title":"Секрет","encrypted":true,"ciphertext":"AAAA","nonce":"AAAA","key_id":"k"}`).
+		Do(t).AssertStatus(t, http.StatusCreated)
+	resp = s.Request(http.MethodGet, "/api/v1/notes/3/suggest-tags").Do(t)
+	resp.AssertStatus(t, http.StatusConflict)
+
+	s.Request(http.MethodGet, "/api/v1/notes/99/suggest-tags").Do(t).AssertStatus(t, http.StatusNotFound)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Режимы поиска по заголовку.
+const (
+	titleMatchExact  = "exact"
+	titleMatchPrefix = "prefix"
+)
+
+const (
+	defaultTitleLimit = 10
+	maxTitleLimit     = 50
+)
+
+/*
+====================
+GET NOTES BY TITLE
+====================
+*/
+
+// GetNotesByTitle godoc
+// @Summary      Найти заметки по заголовку
+// @Description  Для вики-ссылок вида [[Заголовок]]: совпадение без учёта регистра, точное (по умолчанию)
+// @Description  или по префиксу. Точные совпадения идут первыми. Пустой массив — заметки нет.
+// @Tags         notes
+// @Produce      json
+// @Param        title  query    string  true   "Заголовок или его начало"
+// @Param        match  query    string  false  "exact (по умолчанию) или prefix"
+// @Param        limit  query    int     false  "Количество (по умолчанию 10, максимум 50)"
+// @Success      200    {array}  NoteResponse
+// @Failure      400    {object} ErrorResponse
+// @Failure      500    {object} ErrorResponse
+// @Router       /notes/by-title [get]
+func (h *Handler) GetNotesByTitle(w http.ResponseWriter, r *http.Request) {
+	title := strings.TrimSpace(r.URL.Query().Get("title"))
+	if title == "" {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Title is required")
+		return
+	}
+
+	var prefix bool
+	switch r.URL.Query().Get("match") {
+	case "", titleMatchExact:
+	case titleMatchPrefix:
+		prefix = true
+	default:
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid match")
+		return
+	}
+
+	limit := defaultTitleLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid limit")
+			return
+		}
+		limit = min(v, maxTitleLimit)
+	}
+
+	notes, err := h.Repo.FindByTitle(r.Context(), title, prefix, limit)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to list notes")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, newNoteResponses(notes))
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"example.com/notes-api/internal/changes"
+	"example.com/notes-api/internal/core"
+	"example.com/notes-api/internal/lang"
+	"github.com/go-chi/chi/v5"
+)
+
+// TranslationOfKey — ключ metadata сохранённого перевода с ID исходной
+// заметки. ID хранится строкой, потому что фильтр ?meta.translation_of=<id>
+// сравнивает строки.
+const TranslationOfKey = "translation_of"
+
+// TranslationResponse — перевод заголовка и текста заметки.
+type TranslationResponse struct {
+	NoteID int64 `json:"note_id" example:"1"`
+	// From — язык заметки; auto — определён сервисом перевода.
+	From    string `json:"from" example:"ru"`
+	To      string `json:"to" example:"en"`
+	Title   string `json:"title" example:"New note"`
+	Content string `json:"content" example:"Note text"`
+	// Copy — заметка-перевод, если запрошено сохранение (save=true).
+	Copy *NoteResponse `json:"copy,omitempty"`
+}
+
+/*
+====================
+TRANSLATE NOTE
+====================
+*/
+
+// TranslateNote godoc
+// @Summary      Перевести заметку
+// @Description  Переводит заголовок и content внешним сервисом (LibreTranslate). С save=true перевод
+// @Description  сохраняется новой заметкой с цветом, иконкой и metadata исходной и ключом translation_of.
+// @Tags         notes
+// @Produce      json
+// @Param        id    path     int     true   "ID"
+// @Param        to    query    string  true   "Язык перевода (ISO 639-1)"  Enums(ru, uk, en, de, fr, es)
+// @Param        save  query    bool    false  "Сохранить перевод новой заметкой"
+// @Param        X-Lock-Owner  header  string  false  "Владелец блокировки"
+// @Success      200   {object} TranslationResponse
+// @Success      201   {object} TranslationResponse  "Перевод сохранён"
+// @Failure      400   {object} ErrorResponse
+// @Failure      404   {object} ErrorResponse
+// @Failure      409   {object} ErrorResponse
+// @Failure      422   {object} ErrorResponse  "Перевод длиннее лимита"
+// @Failure      423   {object} LockedResponse
+// @Failure      500   {object} ErrorResponse
+// @Failure      501   {object} ErrorResponse
+// @Failure      502   {object} ErrorResponse
+// @Router       /notes/{id}/translate [post]
+func (h *Handler) TranslateNote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidNoteID, "Invalid note ID")
+		return
+	}
+
+	to := r.URL.Query().Get("to")
+	if !lang.Valid(to) {
+		respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid to")
+		return
+	}
+	var save bool
+	if s := r.URL.Query().Get("save"); s != "" {
+		if save, err = strconv.ParseBool(s); err != nil {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid save")
+			return
+		}
+	}
+	if h.Translator == nil {
+		respondWithError(w, r, http.StatusNotImplemented, CodeNotConfigured, "Translation is not configured")
+		return
+	}
+
+	note, err := h.Repo.GetByID(r.Context(), id)
+	if err != nil {
+		if respondNotFound(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to get note")
+		return
+	}
+	if note.Encrypted {
+		respondWithError(w, r, http.StatusConflict, CodeNoteEncrypted, "Encrypted note cannot be translated on the server")
+		return
+	}
+	// Перевод заблокированной заметки сохраняется только её владельцем:
+	// остальные получили бы копию текста, который сейчас правят.
+	if save && !h.checkLock(w, r, id) {
+		return
+	}
+
+	from := note.Lang
+	if from == "" {
+		from = "auto"
+	}
+	texts := []string{note.Title}
+	if note.Content != "" {
+		texts = append(texts, note.Content)
+	}
+	translated, err := h.Translator.Translate(r.Context(), texts, from, to)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadGateway, CodeUpstreamFailed, "Translation service failed")
+		return
+	}
+
+	resp := TranslationResponse{NoteID: note.ID, From: from, To: to, Title: translated[0]}
+	if len(translated) > 1 {
+		resp.Content = translated[1]
+	}
+	if !save {
+		respondWithJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	copyReq, err := translationCopy(note, resp)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to create note")
+		return
+	}
+	if code, msg := ValidateCreate(copyReq, h.now(), h.Limits); code != "" {
+		respondWithError(w, r, validationStatus(code), code, msg)
+		return
+	}
+	copyID, err := h.Repo.CreateWithLogTx(r.Context(), copyReq)
+	if err != nil {
+		if respondTitleTaken(w, r, err) {
+			return
+		}
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to create note")
+		return
+	}
+	created, err := h.Repo.GetByID(r.Context(), copyID)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to retrieve created note")
+		return
+	}
+	h.publish(copyID, changes.NoteCreated)
+
+	copyResp := newNoteResponse(created)
+	resp.Copy = &copyResp
+	respondWithJSON(w, http.StatusCreated, resp)
+}
+
+// translationCopy собирает новую заметку из перевода: оформление и metadata
+// берутся у исходной, в metadata добавляется TranslationOfKey.
+func translationCopy(note *core.Note, t TranslationResponse) (core.NoteCreate, error) {
+	meta := map[string]json.RawMessage{}
+	if len(note.Metadata) > 0 {
+		if err := json.Unmarshal(note.Metadata, &meta); err != nil {
+			return core.NoteCreate{}, err
+		}
+	}
+	meta[TranslationOfKey] = json.RawMessage(strconv.Quote(strconv.FormatInt(note.ID, 10)))
+	raw, err := json.Marshal(meta)
+	if err != nil {
+		return core.NoteCreate{}, err
+	}
+
+	return core.NoteCreate{
+		Title:    t.Title,
+		Content:  t.Content,
+		Metadata: raw,
+		Color:    note.Color,
+		Icon:     note.Icon,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	httpx "example.com/notes-api/internal/http"
+	"example.com/notes-api/internal/http/handlers"
+	"example.com/notes-api/internal/repo"
+	"example.com/notes-api/internal/testutil"
+)
+
+func TestTranslateResponses(t *testing.T) {
+	s := testutil.NewMemoryServer(t, httpx.Config{AdminToken: adminToken})
+	s.Handler.Translator = prefixTranslator{}
+
+	runGolden(t, s, []goldenStep{
+		{"create", http.MethodPost, "/api/v1/notes", `{"title":"Покупки","content":"Молоко"}`, http.StatusCreated, ""},
+		{"translate", http.MethodPost, "/api/v1/notes/1/translate?to=en", ``, http.StatusOK, "translate"},
+		{"translate and save", http.MethodPost, "/api/v1/notes/1/translate?to=de&save=true", ``, http.StatusCreated, "translate_save"},
+	})
+}
+
+func TestTranslateNoteSave(t *testing.T) {
+	s := testutil.NewServer(t, &handlers.Handler{Repo: repo.NewNoteRepoMemory(), Translator: prefixTranslator{}}, httpx.Config{})
+
+	resp := s.Request(http.MethodPost, "/api/v1/notes").
+		JSON(map[string]any{"title": "Покупки", "content": "Молоко", "color": "yellow", "metadata": map[string]string{"list": "home"}}).
+		Do(t)
+	resp.AssertStatus(t, http.StatusCreated)
+	var note handlers.NoteResponse
+	resp.Decode(t, &note)
+
+	resp = s.Request(http.MethodPost, note.Links.Self+"/translate?to=en&save=true").Do(t)
+	resp.AssertStatus(t, http.StatusCreated)
+	var got handlers.TranslationResponse
+	resp.Decode(t, &got)
+	if got.Title != "en: Покупки" || got.Content != "en: Молоко" || got.Copy == nil {
+		t.Fatalf("translation = %+v", got)
+	}
+	if got.Copy.Color != "yellow" || got.Copy.Title != got.Title {
+		t.Errorf("copy = %+v", got.Copy)
+	}
+
+	resp = s.Request(http.MethodGet, "/api/v1/notes?meta.translation_of="+strconv.FormatInt(note.ID, 10)).Do(t)
+	resp.AssertStatus(t, http.StatusOK)
+	var linked []handlers.NoteResponse
+	resp.Decode(t, &linked)
+	if len(linked) != 1 || linked[0].ID != got.Copy.ID {
+		t.Fatalf("translations of the note = %+v, want the copy", linked)
+	}
+	if !strings.Contains(string(linked[0].Metadata), `"home"`) {
+		t.Errorf("copy metadata = %s, want the original keys", linked[0].Metadata)
+	}
+}
@@ -0,0 +1,191 @@
+package handlers_test
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+	"time"
+
+	"example.com/notes-api/internal/auth"
+	httpx "example.com/notes-api/internal/http"
+	"example.com/notes-api/internal/http/handlers"
+	"example.com/notes-api/internal/repo"
+	"example.com/notes-api/internal/testutil"
+)
+
+// basicAuth — значение заголовка Authorization для HTTP Basic.
+func basicAuth(email, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(email+":"+password))
+}
+
+func TestUserResponses(t *testing.T) {
+	s := testutil.NewMemoryServer(t, httpx.Config{})
+	s.Handler.Tokens = newTokens(t)
+	alice := basicAuth("alice@example.com", "correct horse")
+
+	steps := []struct {
+		name   string
+		method string
+		path   string
+		auth   string
+		body   string
+		status int
+		golden string
+	}{
+		{"register", http.MethodPost, "/api/v1/auth/register", "", `{"email":"Alice@Example.com","password":"correct horse","name":"Алиса"}`, http.StatusCreated, "user_register"},
+		{"register taken", http.MethodPost, "/api/v1/auth/register", "", `{"email":"alice@example.com","password":"another one"}`, http.StatusConflict, "user_register_taken"},
+		{"me", http.MethodGet, "/api/v1/me", alice, ``, http.StatusOK, "user_me"},
+		{"me wrong password", http.MethodGet, "/api/v1/me", basicAuth("alice@example.com", "wrong horse"), ``, http.StatusUnauthorized, "user_me_unauthorized"},
+		{"patch me", http.MethodPatch, "/api/v1/me", alice, `{"name":"Алиса Л."}`, http.StatusOK, "user_patch_me"},
+		// Токен и срок зависят от настоящего времени выпуска и не сверяются
+		{"login", http.MethodPost, "/api/v1/auth/login", "", `{"email":"alice@example.com","password":"correct horse"}`, http.StatusOK, "user_login"},
+		{"login wrong password", http.MethodPost, "/api/v1/auth/login", "", `{"email":"alice@example.com","password":"wrong horse"}`, http.StatusUnauthorized, "user_login_unauthorized"},
+	}
+	for _, tt := range steps {
+		req := s.Request(tt.method, tt.path)
+		if tt.auth != "" {
+			req.Header("Authorization", tt.auth)
+		}
+		if tt.body != "" {
+			req.Body(tt.body)
+		}
+		resp := req.Do(t)
+		if resp.Status != tt.status {
+			t.Fatalf("%s: status = %d, want %d; body: %s", tt.name, resp.Status, tt.status, resp.Body)
+		}
+		resp.AssertGolden(t, tt.golden, "request_id", "token", "expires_at")
+	}
+}
+
+func TestUserAccount(t *testing.T) {
+	s := testutil.NewServer(t, &handlers.Handler{Users: repo.NewUserRepoMemory()}, httpx.Config{})
+
+	resp := s.Request(http.MethodPost, "/api/v1/auth/register").
+		JSON(map[string]string{"email": "Alice@Example.com", "password": "correct horse", "name": "Алиса"}).
+		Do(t)
+	resp.AssertStatus(t, http.StatusCreated)
+	var user handlers.UserResponse
+	resp.Decode(t, &user)
+	if user.Email != "alice@example.com" || user.Name != "Алиса" {
+		t.Fatalf("user = %+v", user)
+	}
+
+	s.Request(http.MethodPost, "/api/v1/auth/register").
+		JSON(map[string]string{"email": "alice@example.com", "password": "another one"}).
+		Do(t).AssertStatus(t, http.StatusConflict)
+
+	tests := []struct {
+		name   string
+		auth   string
+		status int
+	}{
+		{"no credentials", "", http.StatusUnauthorized},
+		{"wrong password", basicAuth("alice@example.com", "wrong horse"), http.StatusUnauthorized},
+		{"unknown email", basicAuth("bob@example.com", "correct horse"), http.StatusUnauthorized},
+		{"valid", basicAuth("ALICE@example.com", "correct horse"), http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := s.Request(http.MethodGet, "/api/v1/me")
+			if tt.auth != "" {
+				req.Header("Authorization", tt.auth)
+			}
+			req.Do(t).AssertStatus(t, tt.status)
+		})
+	}
+
+	resp = s.Request(http.MethodPatch, "/api/v1/me").
+		Header("Authorization", basicAuth("alice@example.com", "correct horse")).
+		JSON(map[string]string{"name": "Алиса Л.", "password": "battery staple"}).
+		Do(t)
+	resp.AssertStatus(t, http.StatusOK)
+	resp.Decode(t, &user)
+	if user.Name != "Алиса Л." || user.UpdatedAt == nil {
+		t.Errorf("updated user = %+v", user)
+	}
+
+	s.Request(http.MethodGet, "/api/v1/me").
+		Header("Authorization", basicAuth("alice@example.com", "correct horse")).
+		Do(t).AssertStatus(t, http.StatusUnauthorized)
+	s.Request(http.MethodGet, "/api/v1/me").
+		Header("Authorization", basicAuth("alice@example.com", "battery staple")).
+		Do(t).AssertStatus(t, http.StatusOK)
+}
+
+func TestLoginLockout(t *testing.T) {
+	users := repo.NewUserRepoMemory()
+	s := testutil.NewServer(t, &handlers.Handler{
+		Users:        users,
+		LoginLockout: auth.NewLockout(2, time.Minute, time.Hour),
+	}, httpx.Config{})
+
+	s.Request(http.MethodPost, "/api/v1/auth/register").
+		JSON(map[string]string{"email": "alice@example.com", "password": "correct horse"}).
+		Do(t).AssertStatus(t, http.StatusCreated)
+
+	me := func(password string) *testutil.Response {
+		return s.Request(http.MethodGet, "/api/v1/me").
+			Header("Authorization", basicAuth("alice@example.com", password)).
+			Do(t)
+	}
+	me("wrong one").AssertStatus(t, http.StatusUnauthorized)
+	me("wrong two").AssertStatus(t, http.StatusUnauthorized)
+
+	resp := me("correct horse")
+	resp.AssertStatus(t, http.StatusTooManyRequests)
+	if resp.Header.Get("Retry-After") == "" {
+		t.Error("Retry-After is not set")
+	}
+	var body handlers.ErrorResponse
+	resp.Decode(t, &body)
+	if body.Code != "too_many_attempts" {
+		t.Errorf("code = %q, want too_many_attempts", body.Code)
+	}
+}
+
+func TestTokenLogin(t *testing.T) {
+	tokens := newTokens(t)
+	s := testutil.NewServer(t, &handlers.Handler{
+		Repo:   repo.NewNoteRepoMemory(),
+		Users:  repo.NewUserRepoMemory(),
+		Tokens: tokens,
+	}, httpx.Config{AdminToken: adminToken, JWT: tokens})
+
+	s.Request(http.MethodPost, "/api/v1/auth/register").
+		JSON(map[string]string{"email": "alice@example.com", "password": "correct horse"}).
+		Do(t).AssertStatus(t, http.StatusCreated)
+
+	s.Request(http.MethodPost, "/api/v1/auth/login").
+		JSON(map[string]string{"email": "alice@example.com", "password": "wrong horse"}).
+		Do(t).AssertStatus(t, http.StatusUnauthorized)
+
+	resp := s.Request(http.MethodPost, "/api/v1/auth/login").
+		JSON(map[string]string{"email": "alice@example.com", "password": "correct horse"}).
+		Do(t)
+	resp.AssertStatus(t, http.StatusOK)
+	var login handlers.TokenResponse
+	resp.Decode(t, &login)
+	if login.TokenType != "Bearer" || login.Token == "" {
+		t.Fatalf("login = %+v", login)
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"anonymous", "", http.StatusUnauthorized},
+		{"tampered", login.Token + "x", http.StatusUnauthorized},
+		{"user token", login.Token, http.StatusOK},
+		{"admin token", adminToken, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := s.Request(http.MethodGet, "/api/v1/notes")
+			if tt.token != "" {
+				req.Header("Authorization", "Bearer "+tt.token)
+			}
+			req.Do(t).AssertStatus(t, tt.status)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultRecentLimit = 20
+	maxRecentLimit     = 100
+)
+
+/*
+====================
+RECENTLY VIEWED NOTES
+====================
+*/
+
+// RecentNotes godoc
+// @Summary      Недавно просмотренные заметки
+// @Tags         notes
+// @Produce      json
+// @Param        limit  query    int  false  "Количество (по умолчанию 20, максимум 100)"
+// @Success      200    {array}  NoteResponse
+// @Failure      400    {object} ErrorResponse
+// @Failure      500    {object} ErrorResponse
+// @Router       /notes/recent [get]
+func (h *Handler) RecentNotes(w http.ResponseWriter, r *http.Request) {
+	limit := defaultRecentLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			respondWithError(w, r, http.StatusBadRequest, CodeInvalidParameter, "Invalid limit")
+			return
+		}
+		limit = min(v, maxRecentLimit)
+	}
+
+	notes, err := h.Repo.ListRecentlyViewed(r.Context(), limit)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to list recent notes")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, newNoteResponses(notes))
+}
+
+// recordView асинхронно учитывает просмотр заметки.
+func (h *Handler) recordView(r *http.Request, noteID int64) {
+	if h.Views != nil {
+		h.Views.Record(noteID, clientKey(r))
+	}
+}
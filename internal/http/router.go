@@ -1,38 +1,170 @@
 package httpx
 
 import (
+	"expvar"
 	"net/http"
+	"os"
+	"time"
 
+	"example.com/notes-api/internal/auth"
+	"example.com/notes-api/internal/health"
 	"example.com/notes-api/internal/http/handlers"
+	"example.com/notes-api/internal/logx"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 )
 
-func NewRouter(h *handlers.Handler) *chi.Mux {
+// Config — настройки HTTP-слоя, не относящиеся к отдельным обработчикам.
+type Config struct {
+	// LogQueryAllowlist — параметры запроса, значения которых пишутся в лог открыто.
+	LogQueryAllowlist []string
+	// AccessLogSampleRate — доля обычных запросов в журнале доступа; 0 — все.
+	AccessLogSampleRate float64
+	// AccessLogSlow — запросы дольше этого пишутся в журнал всегда; 0 — без порога.
+	AccessLogSlow time.Duration
+	// AdminToken открывает /api/v1/admin; пустой — админские маршруты закрыты.
+	AdminToken string
+	// AdminLockout ограничивает перебор админского токена; nil — без ограничения.
+	AdminLockout *auth.Lockout
+	// Signer принимает HMAC-подписанные запросы к /api/v1/admin вместо токена; nil — выключено.
+	Signer *auth.Signer
+	// JWT требует токен пользователя (/auth/login) для заметок; nil — заметки
+	// доступны без входа.
+	JWT *auth.JWT
+	// MaxDecompressedBody ограничивает размер тела после распаковки gzip; 0 — 10 МиБ.
+	MaxDecompressedBody int64
+	// ReadOnly — публичный режим: без аутентификации API доступно только для
+	// чтения, запись требует токена пользователя (если задан JWT), админского
+	// токена или подписи.
+	ReadOnly bool
+	// MCP — HTTP-транспорт Model Context Protocol на /mcp; nil — выключен.
+	MCP http.Handler
+	// Health — зависимости, которые опрашивает /health.
+	Health []health.Check
+}
+
+func NewRouter(h *handlers.Handler, cfg Config) *chi.Mux {
 	r := chi.NewRouter()
 
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
+	// Журнал доступа в JSON без значений параметров и slug: в них могут быть
+	// токены и текст заметок
+	accessLog := logx.NewAccessLog(os.Stdout, logx.NewRedactor(cfg.LogQueryAllowlist))
+	accessLog.SampleRate = cfg.AccessLogSampleRate
+	accessLog.Slow = cfg.AccessLogSlow
 	r.Use(middleware.RequestID)
+	r.Use(accessLog.Handler)
+	r.Use(middleware.Recoverer)
+	r.Use(requestIDHeader)
+	r.Use(decompressBody(cfg.MaxDecompressedBody))
 
 	r.Route("/api/v1", func(r chi.Router) {
-		r.Route("/notes", func(r chi.Router) {
-			r.Post("/", h.CreateNote)
-			r.Get("/", h.ListNotes)
-			r.Route("/{id}", func(r chi.Router) {
-				r.Get("/", h.GetNote)
-				r.Patch("/", h.PatchNote)
-				r.Delete("/", h.DeleteNote)
+		r.Group(func(r chi.Router) {
+			if cfg.ReadOnly {
+				r.Use(auth.Signed(cfg.Signer))
+				r.Use(auth.ReadOnly(cfg.AdminToken, cfg.AdminLockout, cfg.JWT))
+			}
+
+			// Учётные записи: регистрация открыта, /me — по HTTP Basic
+			r.Post("/auth/register", h.RegisterUser)
+			r.Get("/me", h.GetMe)
+			r.Patch("/me", h.PatchMe)
+
+			// Заметки и всё, что их показывает, — по токену пользователя
+			r.Group(func(r chi.Router) {
+				// Подписанные запросы проходят без токена; в режиме только для
+				// чтения подпись уже проверена выше
+				if cfg.JWT != nil && !cfg.ReadOnly {
+					r.Use(auth.Signed(cfg.Signer))
+				}
+				r.Use(auth.RequireJWT(cfg.JWT, cfg.AdminToken))
+
+				r.Route("/notes", func(r chi.Router) {
+					r.Post("/", h.CreateNote)
+					r.Get("/", h.ListNotes)
+					r.Patch("/", h.PatchNotes)
+					r.Get("/changes", h.ListChanges)
+					r.Get("/recent", h.RecentNotes)
+					r.Get("/calendar", h.NotesCalendar)
+					r.Get("/export", h.ExportNotes)
+					r.Get("/daily/{date}", h.GetDailyNote)
+					r.Post("/daily/{date}", h.CreateDailyNote)
+					r.Get("/nearby", h.NearbyNotes)
+					r.Get("/by-slug/{slug}", h.GetNoteBySlug)
+					r.Get("/by-title", h.GetNotesByTitle)
+					r.Route("/{id}", func(r chi.Router) {
+						r.Get("/", h.GetNote)
+						r.Patch("/", h.PatchNote)
+						r.Delete("/", h.DeleteNote)
+						r.Post("/lock", h.LockNote)
+						r.Post("/unlock", h.UnlockNote)
+						r.Post("/move", h.MoveNote)
+						r.Post("/diff", h.DiffNote)
+						r.Post("/proofread", h.ProofreadNote)
+						r.Post("/translate", h.TranslateNote)
+						r.Get("/suggest-tags", h.SuggestNoteTags)
+						r.Post("/draft", h.DraftNote)
+						r.Post("/review", h.SubmitNoteForReview)
+						r.Post("/approve", h.ApproveNote)
+						r.Post("/reject", h.RejectNote)
+						r.Get("/stats", h.NoteStats)
+						r.Get("/print", h.PrintNote)
+						r.Get("/export", h.ExportNote)
+					})
+				})
+
+				r.Get("/activity", h.ListActivity)
+				r.Get("/overview", h.Overview)
 
+				r.Route("/integrations/notes", func(r chi.Router) {
+					r.Get("/new", h.NewNotesTrigger)
+					r.Get("/sample", h.SampleNotesTrigger)
+					r.Get("/search", h.SearchNotesAction)
+					r.Post("/", h.CreateNote)
+				})
 			})
 		})
-	})
 
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status": "ok"}`))
+		// Вход не меняет данных и нужен для чтения, поэтому открыт и в режиме только для чтения
+		r.Post("/auth/login", h.Login)
+
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(auth.Signed(cfg.Signer))
+			r.Use(auth.AdminToken(cfg.AdminToken, cfg.AdminLockout))
+			r.Get("/retention/preview", h.PreviewRetention)
+			r.Post("/retention/run", h.RunRetention)
+			r.Get("/retention/tables", h.TableStats)
+			r.Get("/integrity", h.CheckIntegrity)
+			r.Handle("/metrics", expvar.Handler())
+			r.Post("/integrity/repair", h.RepairIntegrity)
+			r.Get("/backups", h.ListBackups)
+			r.Post("/backups", h.CreateBackup)
+			r.Get("/notes", h.AdminListNotes)
+			r.Get("/notes/{id}", h.AdminGetNote)
+			r.Post("/notes/{id}/restore", h.RestoreNote)
+			r.Post("/notes/{id}/hold", h.PlaceLegalHold)
+			r.Delete("/notes/{id}/hold", h.ReleaseLegalHold)
+			r.Put("/notes/{id}/announcement", h.AnnounceNote)
+			r.Delete("/notes/{id}/announcement", h.UnannounceNote)
+		})
 	})
 
+	if cfg.MCP != nil {
+		r.Handle("/mcp", cfg.MCP)
+	}
+
+	r.Get("/health", health.Handler(cfg.Health...))
+	r.Get("/health/live", health.Live)
+
 	return r
 }
+
+// requestIDHeader возвращает ID запроса клиенту в X-Request-ID, чтобы обращения
+// в поддержку можно было сопоставить с логами.
+func requestIDHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := middleware.GetReqID(r.Context()); id != "" {
+			w.Header().Set(middleware.RequestIDHeader, id)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
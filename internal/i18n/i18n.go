@@ -0,0 +1,71 @@
+// Package i18n переводит сообщения об ошибках API по заголовку Accept-Language.
+//
+// Ключ каталога — английский текст сообщения, он же ответ по умолчанию.
+package i18n
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Поддерживаемые языки.
+const (
+	EN = "en"
+	RU = "ru"
+)
+
+// catalogs — переводы по языкам; английский не нужен, ключи уже на нём.
+var catalogs = map[string]map[string]string{
+	RU: ru,
+}
+
+// Language выбирает поддерживаемый язык из Accept-Language с наибольшим q.
+// Без подходящего языка возвращает EN.
+func Language(r *http.Request) string {
+	best, bestQ := EN, 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if base != EN && base != RU {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ {
+			best, bestQ = base, q
+		}
+	}
+	return best
+}
+
+// Translate переводит msg на lang. Для сообщений вида "Префикс: подробности"
+// переводится префикс, подробности остаются как есть. Неизвестные сообщения
+// возвращаются без изменений.
+func Translate(lang, msg string) string {
+	catalog, ok := catalogs[lang]
+	if !ok {
+		return msg
+	}
+	if t, ok := catalog[msg]; ok {
+		return t
+	}
+	if prefix, detail, ok := strings.Cut(msg, ": "); ok {
+		if t, ok := catalog[prefix]; ok {
+			return t + ": " + detail
+		}
+	}
+	return msg
+}
+
+// Message — перевод msg на язык запроса r.
+func Message(r *http.Request, msg string) string {
+	return Translate(Language(r), msg)
+}
@@ -0,0 +1,143 @@
+package i18n
+
+var ru = map[string]string{
+	// Запрос
+	"Failed to read request body":  "Не удалось прочитать тело запроса",
+	"Invalid gzip body":            "Некорректное gzip-тело запроса",
+	"Unsupported Content-Encoding": "Неподдерживаемый Content-Encoding",
+	"Invalid JSON":                 "Некорректный JSON",
+	"Invalid note ID":              "Некорректный ID заметки",
+	"Invalid before":               "Некорректный параметр before",
+	"Invalid limit":                "Некорректный параметр limit",
+	"Failed to check integrity":    "Не удалось проверить целостность данных",
+	"Failed to repair integrity":   "Не удалось исправить нарушения целостности",
+	"Failed to build overview":     "Не удалось собрать сводку",
+	"Invalid match":                "Некорректный режим поиска match",
+	"Invalid since_id":             "Некорректный параметр since_id",
+	"Search query is required":     "Не задан поисковый запрос",
+	"Invalid since":                "Некорректный параметр since",
+	"Invalid wait":                 "Некорректный параметр wait",
+	"Invalid sort":                 "Некорректный порядок сортировки",
+	"Invalid archived":             "Некорректный параметр archived",
+	"Invalid review_state":         "Некорректное состояние согласования",
+	"Invalid lang":                 "Некорректный код языка",
+	"Invalid from":                 "Некорректный параметр from",
+	"Invalid to":                   "Некорректный параметр to",
+	"Invalid tz":                   "Неизвестный часовой пояс",
+	"Invalid date":                 "Некорректная дата",
+	"Invalid format":               "Неизвестный формат",
+	"Invalid atomic":               "Некорректный параметр atomic",
+	"Invalid dry_run":              "Некорректный параметр dry_run",
+	"Invalid save":                 "Некорректный параметр save",
+
+	// Валидация заметки
+	"Title is too long":                "Заголовок слишком длинный",
+	"Content is too large":             "Текст заметки слишком большой",
+	"Title is required":                "Заголовок обязателен",
+	"Content is required":              "Текст обязателен",
+	"Title cannot be empty":            "Заголовок не может быть пустым",
+	"No fields to update":              "Нет полей для обновления",
+	"Note not found":                   "Заметка не найдена",
+	"Invalid metadata":                 "Некорректные metadata",
+	"Invalid metadata filter":          "Некорректный фильтр по metadata",
+	"Invalid color":                    "Недопустимый цвет",
+	"Invalid icon":                     "Недопустимая иконка",
+	"Invalid location":                 "Некорректные координаты",
+	"Invalid coordinates":              "Некорректные координаты",
+	"Invalid radius":                   "Некорректный радиус",
+	"expires_at must be in the future": "expires_at должен быть в будущем",
+
+	// Пакетные операции
+	"No notes to update":                            "Нет заметок для обновления",
+	"Too many notes in batch":                       "Слишком много заметок в пакете",
+	"Not applied: another note in the batch failed": "Не применено: ошибка в другой заметке пакета",
+
+	// Шифрование
+	"ciphertext, nonce and key_id require encrypted=true":       "ciphertext, nonce и key_id допустимы только с encrypted=true",
+	"Encrypted note must not have plaintext content":            "Зашифрованная заметка не может содержать открытый content",
+	"Encrypted note requires ciphertext, nonce and key_id":      "Для зашифрованной заметки нужны ciphertext, nonce и key_id",
+	"Note is not encrypted":                                     "Заметка не зашифрована",
+	"Encrypted note content is opaque; send ciphertext instead": "Содержимое зашифрованной заметки недоступно серверу, передайте ciphertext",
+	"ciphertext and nonce must be updated together":             "ciphertext и nonce обновляются вместе",
+	"key_id cannot be empty":                                    "key_id не может быть пустым",
+	"Encrypted note cannot be compared on the server":           "Зашифрованную заметку нельзя сравнить на сервере",
+	"Encrypted note cannot be proofread on the server":          "Зашифрованную заметку нельзя проверить на сервере",
+	"Encrypted note cannot be translated on the server":         "Зашифрованную заметку нельзя перевести на сервере",
+	"Encrypted note cannot be tagged on the server":             "Для зашифрованной заметки нельзя подобрать теги на сервере",
+	"Texts differ too much to compare":                          "Тексты слишком сильно различаются для сравнения",
+
+	// Блокировки, версии, перемещение
+	"Owner is required":                                       "Владелец обязателен",
+	"Invalid ttl_seconds":                                     "Некорректный ttl_seconds",
+	"Note is locked":                                          "Заметка заблокирована",
+	"Note is locked by another owner":                         "Заметка заблокирована другим владельцем",
+	"Note was modified on the server":                         "Заметка изменена на сервере",
+	"until must be after from":                                "Конец показа должен быть позже начала",
+	"until must be in the future":                             "Конец показа должен быть в будущем",
+	"Reviewer is required":                                    "Укажите рецензента",
+	"Transition is not allowed from the current review state": "Переход недопустим из текущего состояния согласования",
+	"Only the assigned reviewer can decide":                   "Решение принимает только назначенный рецензент",
+	"Invalid move target":                                     "Недопустимая цель перемещения",
+	"Only one of after_id and before_id is allowed":           "Допускается только один из after_id и before_id",
+	"Title is already taken":                                  "Заголовок уже занят другой заметкой",
+	"Note is under legal hold":                                "Заметка находится на юридическом удержании",
+
+	// Учётные записи
+	"Invalid email":               "Некорректный email",
+	"Name is too long":            "Имя слишком длинное",
+	"Password is too short":       "Пароль слишком короткий",
+	"Password is too long":        "Пароль слишком длинный",
+	"Email is already registered": "Email уже зарегистрирован",
+	"Authentication required":     "Требуется вход",
+	"Invalid email or password":   "Неверный email или пароль",
+	"Invalid or expired token":    "Токен недействителен или истёк",
+
+	// Доступ
+	"Admin access required":     "Требуется доступ администратора",
+	"Too many failed attempts":  "Слишком много неудачных попыток",
+	"API is read-only":          "API доступно только для чтения",
+	"Invalid request signature": "Неверная подпись запроса",
+
+	// Настройки сервера
+	"Backups are not configured":       "Резервное копирование не настроено",
+	"Retention is not configured":      "Правила хранения не настроены",
+	"User accounts are not configured": "Учётные записи не настроены",
+	"Token login is not configured":    "Вход по токену не настроен",
+	"Proofreading is not configured":   "Проверка правописания не настроена",
+	"Translation is not configured":    "Перевод не настроен",
+	"Translation service failed":       "Сервис перевода не ответил",
+	"Proofreading service failed":      "Сервис проверки правописания не ответил",
+
+	// Ошибки сервера
+	"Failed to create note":           "Не удалось создать заметку",
+	"Failed to get note":              "Не удалось получить заметку",
+	"Failed to get note stats":        "Не удалось получить статистику заметки",
+	"Failed to search notes":          "Не удалось выполнить поиск заметок",
+	"Failed to list notes":            "Не удалось получить список заметок",
+	"Failed to update note":           "Не удалось обновить заметку",
+	"Failed to delete note":           "Не удалось удалить заметку",
+	"Failed to restore note":          "Не удалось восстановить заметку",
+	"Failed to retrieve created note": "Не удалось получить созданную заметку",
+	"Failed to retrieve updated note": "Не удалось получить обновлённую заметку",
+	"Failed to retrieve moved note":   "Не удалось получить перемещённую заметку",
+	"Failed to move note":             "Не удалось переместить заметку",
+	"Failed to lock note":             "Не удалось заблокировать заметку",
+	"Failed to unlock note":           "Не удалось снять блокировку",
+	"Failed to check note lock":       "Не удалось проверить блокировку заметки",
+	"Failed to list activity":         "Не удалось получить ленту активности",
+	"Failed to list recent notes":     "Не удалось получить недавние заметки",
+	"Failed to list nearby notes":     "Не удалось получить заметки поблизости",
+	"Failed to build calendar":        "Не удалось построить календарь",
+	"Failed to preview retention":     "Не удалось рассчитать правила хранения",
+	"Failed to apply retention":       "Не удалось применить правила хранения",
+	"Failed to get table stats":       "Не удалось получить размер таблиц",
+	"Failed to update review state":   "Не удалось изменить состояние согласования",
+	"Failed to update announcement":   "Не удалось изменить объявление",
+	"Failed to update legal hold":     "Не удалось изменить юридическое удержание",
+	"Failed to list backups":          "Не удалось получить список резервных копий",
+	"Failed to create user":           "Не удалось создать пользователя",
+	"Failed to get user":              "Не удалось получить пользователя",
+	"Failed to update user":           "Не удалось изменить пользователя",
+	"Failed to issue token":           "Не удалось выдать токен",
+	"Failed to create backup":         "Не удалось создать резервную копию",
+}
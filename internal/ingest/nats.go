@@ -0,0 +1,188 @@
+// Package ingest создаёт заметки из сообщений NATS — для систем, которым
+// проще публиковать в брокер, чем ходить в HTTP API.
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"time"
+
+	"example.com/notes-api/internal/changes"
+	"example.com/notes-api/internal/clock"
+	"example.com/notes-api/internal/core"
+	"example.com/notes-api/internal/http/handlers"
+	"example.com/notes-api/internal/retry"
+	"github.com/nats-io/nats.go"
+)
+
+// Заголовки сообщения в очереди недоставленных: почему оно отклонено и откуда пришло.
+const (
+	HeaderErrorCode = "Notes-Error-Code"
+	HeaderError     = "Notes-Error"
+	HeaderSubject   = "Notes-Source-Subject"
+)
+
+// publishRetry — повтор публикации ответов и недоставленных сообщений, пока
+// клиент NATS переподключается. Закрытое соединение не восстановится.
+var publishRetry = retry.Policy{
+	Name:     "nats_publish",
+	Attempts: 5,
+	Base:     50 * time.Millisecond,
+	Max:      2 * time.Second,
+	Retryable: func(err error) bool {
+		return !errors.Is(err, nats.ErrConnectionClosed)
+	},
+}
+
+// Creator — часть репозитория, которой нужен потребитель.
+type Creator interface {
+	CreateWithLogTx(ctx context.Context, n core.NoteCreate) (int64, error)
+}
+
+// Config — настройки потребителя.
+type Config struct {
+	// URL сервера NATS, например "nats://localhost:4222".
+	URL string
+	// Subject — тема, из которой читаются заметки.
+	Subject string
+	// Queue — группа очереди: несколько экземпляров API делят сообщения между собой.
+	Queue string
+	// DeadLetter — тема для отклонённых сообщений; пустая — они только пишутся в лог.
+	DeadLetter string
+	// Limits — предельные размеры заметки, как у POST /notes.
+	Limits core.Limits
+}
+
+// Consumer читает из Config.Subject JSON в формате POST /notes и создаёт заметки.
+// Сообщения, не прошедшие проверку или не сохранённые, уходят в DeadLetter
+// с кодом ошибки в заголовках. Если у сообщения есть reply-тема, отправителю
+// отвечают {"id": ...} или ошибкой в формате API.
+type Consumer struct {
+	cfg     Config
+	conn    *nats.Conn
+	repo    Creator
+	changes *changes.Feed
+	clock   clock.Clock
+}
+
+// Connect подключается к NATS. changes может быть nil.
+func Connect(cfg Config, repo Creator, feed *changes.Feed) (*Consumer, error) {
+	if cfg.Subject == "" {
+		return nil, errors.New("ingest: subject is required")
+	}
+	conn, err := nats.Connect(cfg.URL, nats.Name("notes-api ingest"))
+	if err != nil {
+		return nil, err
+	}
+	return &Consumer{cfg: cfg, conn: conn, repo: repo, changes: feed, clock: clock.System}, nil
+}
+
+// Run обрабатывает сообщения по одному до отмены ctx, затем закрывает соединение.
+func (c *Consumer) Run(ctx context.Context) {
+	defer c.conn.Close()
+
+	sub, err := c.conn.QueueSubscribeSync(c.cfg.Subject, c.cfg.Queue)
+	if err != nil {
+		log.Printf("ingest: subscribe to %s failed: %v", c.cfg.Subject, err)
+		return
+	}
+	log.Printf("ingest: consuming notes from %s", c.cfg.Subject)
+
+	for {
+		msg, err := sub.NextMsgWithContext(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("ingest: %v", err)
+			}
+			_ = sub.Drain()
+			return
+		}
+		c.handle(ctx, msg)
+	}
+}
+
+// Ping проверяет связь с сервером NATS — для /health.
+func (c *Consumer) Ping(ctx context.Context) error {
+	return c.conn.FlushWithContext(ctx)
+}
+
+func (c *Consumer) handle(ctx context.Context, msg *nats.Msg) {
+	req, code, text := decode(msg.Data)
+	if code == "" {
+		code, text = handlers.ValidateCreate(req, c.clock.Now(), c.cfg.Limits)
+	}
+	if code != "" {
+		c.reject(ctx, msg, code, text)
+		return
+	}
+
+	id, err := c.repo.CreateWithLogTx(ctx, req)
+	if errors.Is(err, core.ErrTitleTaken) {
+		c.reject(ctx, msg, handlers.CodeTitleTaken, "Title is already taken")
+		return
+	}
+	if err != nil {
+		log.Printf("ingest: create note failed: %v", err)
+		c.reject(ctx, msg, handlers.CodeInternal, "Failed to create note")
+		return
+	}
+	if c.changes != nil {
+		c.changes.Publish(id, changes.NoteCreated)
+	}
+	c.reply(ctx, msg, map[string]int64{"id": id})
+}
+
+// decode разбирает тело строго: неизвестные поля и лишние данные после
+// объекта — ошибка, чтобы опечатка в схеме отправителя не терялась молча.
+func decode(data []byte) (req core.NoteCreate, code, msg string) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return req, handlers.CodeInvalidJSON, "Invalid JSON: " + err.Error()
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return req, handlers.CodeInvalidJSON, "Invalid JSON: trailing data"
+	}
+	return req, "", ""
+}
+
+// reject отправляет сообщение в очередь недоставленных и отвечает отправителю ошибкой.
+func (c *Consumer) reject(ctx context.Context, msg *nats.Msg, code, text string) {
+	log.Printf("ingest: rejected message from %s: %s (%s)", msg.Subject, text, code)
+	c.reply(ctx, msg, handlers.ErrorResponse{Error: text, Code: code})
+
+	if c.cfg.DeadLetter == "" {
+		return
+	}
+	dead := nats.NewMsg(c.cfg.DeadLetter)
+	dead.Data = msg.Data
+	dead.Header.Set(HeaderErrorCode, code)
+	dead.Header.Set(HeaderError, text)
+	dead.Header.Set(HeaderSubject, msg.Subject)
+	if err := c.publish(ctx, dead); err != nil {
+		log.Printf("ingest: dead-letter publish failed: %v", err)
+	}
+}
+
+func (c *Consumer) reply(ctx context.Context, msg *nats.Msg, v any) {
+	if msg.Reply == "" {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	if err := c.publish(ctx, &nats.Msg{Subject: msg.Reply, Data: data}); err != nil {
+		log.Printf("ingest: reply failed: %v", err)
+	}
+}
+
+func (c *Consumer) publish(ctx context.Context, m *nats.Msg) error {
+	return publishRetry.Do(ctx, func(context.Context) error {
+		return c.conn.PublishMsg(m)
+	})
+}
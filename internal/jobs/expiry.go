@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"log"
+)
+
+// expiryBatch — сколько заметок удаляется за один запрос.
+const expiryBatch = 500
+
+// ExpiredPurger удаляет заметки с истёкшим сроком жизни.
+type ExpiredPurger interface {
+	PurgeExpired(ctx context.Context, limit int) (int64, error)
+}
+
+// PurgeExpired возвращает задачу, удаляющую истёкшие заметки пачками до конца очереди.
+func PurgeExpired(p ExpiredPurger) func(context.Context) error {
+	return func(ctx context.Context) error {
+		var total int64
+		for {
+			n, err := p.PurgeExpired(ctx, expiryBatch)
+			if err != nil {
+				return err
+			}
+			total += n
+			if n < expiryBatch {
+				break
+			}
+		}
+		if total > 0 {
+			log.Printf("Purged %d expired notes", total)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,26 @@
+// Package jobs запускает периодические фоновые задачи сервера.
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Every вызывает fn каждые interval, пока не отменён ctx.
+// Ошибки задачи логируются и не останавливают расписание.
+func Every(ctx context.Context, name string, interval time.Duration, fn func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := fn(ctx); err != nil {
+				log.Printf("Job %s failed: %v", name, err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"context"
+	"log"
+)
+
+// partitionMonthsAhead — на сколько месяцев вперёд держать секции notes_log.
+const partitionMonthsAhead = 3
+
+// LogPartitioner создаёт секции notes_log заранее.
+type LogPartitioner interface {
+	EnsureLogPartitions(ctx context.Context, months int) (int, error)
+}
+
+// LogPartitions возвращает задачу, создающую секции notes_log на несколько месяцев вперёд.
+func LogPartitions(p LogPartitioner) func(context.Context) error {
+	return func(ctx context.Context) error {
+		n, err := p.EnsureLogPartitions(ctx, partitionMonthsAhead)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			log.Printf("Created %d notes_log partitions", n)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,147 @@
+// Package keywords подбирает теги для заметки: из словаря уже используемых
+// тегов (те, что встречаются в тексте) и из ключевых слов самого текста.
+// Ключевые слова — частые слова без служебных; морфологии и синонимов пакет
+// не знает, поэтому формы одного слова считаются разными словами.
+package keywords
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Источники подсказки.
+const (
+	SourceVocabulary = "vocabulary"
+	SourceKeyword    = "keyword"
+)
+
+// Suggestion — предложенный тег.
+type Suggestion struct {
+	Tag string `json:"tag" example:"отпуск"`
+	// Source — SourceVocabulary (тег уже используется) или SourceKeyword.
+	Source string `json:"source" example:"vocabulary" enums:"vocabulary,keyword"`
+	// Count — сколько раз тег встречается в тексте.
+	Count int `json:"count" example:"3"`
+}
+
+// minWordLen — более короткие слова ключевыми не считаются.
+const minWordLen = 3
+
+// stopwords — служебные слова по кодам языков (lang.Codes). Для текста без
+// определённого языка отбрасываются слова всех списков.
+var stopwords = map[string][]string{
+	"ru": {"это", "как", "так", "что", "чтобы", "его", "она", "они", "оно", "мне", "меня", "нас", "вас", "для", "при", "или", "если", "когда", "где", "уже", "ещё", "еще", "только", "тоже", "также", "был", "была", "было", "были", "будет", "есть", "нет", "над", "под", "без", "после", "перед", "через", "все", "всё", "весь", "этот", "эта", "эти", "того", "тот", "там", "тут", "здесь", "очень", "можно", "нужно", "надо"},
+	"uk": {"це", "як", "так", "що", "щоб", "його", "вона", "вони", "воно", "мені", "мене", "нас", "вас", "для", "при", "або", "якщо", "коли", "де", "вже", "ще", "тільки", "теж", "також", "був", "була", "було", "були", "буде", "є", "немає", "над", "під", "без", "після", "перед", "через", "все", "всі", "цей", "ця", "ці", "там", "тут", "дуже", "можна", "треба"},
+	"en": {"the", "and", "are", "was", "were", "you", "not", "for", "with", "this", "that", "these", "those", "from", "have", "has", "had", "but", "all", "can", "will", "would", "should", "about", "into", "than", "then", "them", "they", "their", "there", "what", "when", "where", "which", "who", "our", "your", "its", "also", "just", "only", "very", "been", "being", "some", "any"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "einen", "ich", "mit", "den", "dem", "des", "von", "auf", "sie", "sind", "war", "für", "auch", "aber", "wie", "wenn", "noch", "nur", "oder", "bei", "aus", "nach", "sich", "wir", "ihr", "zum", "zur"},
+	"fr": {"les", "est", "une", "des", "pas", "que", "qui", "pour", "dans", "ce", "sur", "avec", "par", "plus", "mais", "sont", "nous", "vous", "ils", "elle", "leur", "aux", "ces", "cette", "tout", "être", "avoir", "comme"},
+	"es": {"los", "las", "del", "que", "por", "para", "con", "una", "uno", "como", "más", "pero", "sus", "este", "esta", "estos", "son", "está", "hay", "ser", "todo", "nos", "les", "muy", "sin", "sobre"},
+}
+
+// Words разбивает text на слова в нижнем регистре (NFC): буквы и цифры,
+// остальное — разделители.
+func Words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(norm.NFC.String(text)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Extract возвращает до limit ключевых слов text на языке lang ("" —
+// неизвестен): слова не короче minWordLen, не служебные и не из одних цифр,
+// по убыванию частоты, при равной — в порядке первого появления.
+func Extract(text, lang string, limit int) []Suggestion {
+	stop := stopSet(lang)
+	counts := map[string]int{}
+	var order []string
+	for _, w := range Words(text) {
+		if len([]rune(w)) < minWordLen || stop[w] || isNumber(w) {
+			continue
+		}
+		if counts[w] == 0 {
+			order = append(order, w)
+		}
+		counts[w]++
+	}
+
+	out := make([]Suggestion, 0, len(order))
+	for _, w := range order {
+		out = append(out, Suggestion{Tag: w, Source: SourceKeyword, Count: counts[w]})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Count > out[j].Count })
+	return head(out, limit)
+}
+
+// Suggest предлагает до limit тегов для text: сначала теги из vocabulary,
+// которые встречаются в тексте целыми словами (многословные — подряд), по
+// убыванию числа вхождений, затем ключевые слова Extract. Теги из exclude
+// (уже стоящие на заметке) и повторы без учёта регистра пропускаются;
+// найденный словарный тег возвращается в написании словаря.
+func Suggest(text, lang string, vocabulary, exclude []string, limit int) []Suggestion {
+	seen := map[string]bool{}
+	for _, t := range exclude {
+		seen[normTag(t)] = true
+	}
+
+	padded := " " + strings.Join(Words(text), " ") + " "
+	out := []Suggestion{}
+	for _, t := range vocabulary {
+		key := normTag(t)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if n := strings.Count(padded, " "+key+" "); n > 0 {
+			out = append(out, Suggestion{Tag: t, Source: SourceVocabulary, Count: n})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Count > out[j].Count })
+
+	for _, s := range Extract(text, lang, 0) {
+		if len(out) == limit {
+			break
+		}
+		if !seen[s.Tag] {
+			seen[s.Tag] = true
+			out = append(out, s)
+		}
+	}
+	return head(out, limit)
+}
+
+// normTag приводит тег к виду слов Words, соединённых пробелом.
+func normTag(t string) string {
+	return strings.Join(Words(t), " ")
+}
+
+func stopSet(lang string) map[string]bool {
+	set := map[string]bool{}
+	for code, list := range stopwords {
+		if lang != "" && code != lang {
+			continue
+		}
+		for _, w := range list {
+			set[w] = true
+		}
+	}
+	return set
+}
+
+func isNumber(w string) bool {
+	for _, r := range w {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// head обрезает s до limit элементов; limit <= 0 — без ограничения.
+func head(s []Suggestion, limit int) []Suggestion {
+	if limit > 0 && len(s) > limit {
+		return s[:limit]
+	}
+	return s
+}
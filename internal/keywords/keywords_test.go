@@ -0,0 +1,72 @@
+package keywords
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		lang  string
+		limit int
+		want  []Suggestion
+	}{
+		{
+			name: "frequency then first occurrence",
+			text: "Купить билеты на поезд. Билеты в Казань, поезд вечером, билеты дешевле.",
+			lang: "ru",
+			want: []Suggestion{
+				{Tag: "билеты", Source: SourceKeyword, Count: 3},
+				{Tag: "поезд", Source: SourceKeyword, Count: 2},
+				{Tag: "купить", Source: SourceKeyword, Count: 1},
+				{Tag: "казань", Source: SourceKeyword, Count: 1},
+				{Tag: "вечером", Source: SourceKeyword, Count: 1},
+				{Tag: "дешевле", Source: SourceKeyword, Count: 1},
+			},
+		},
+		{
+			name:  "stopwords, short words and numbers skipped",
+			text:  "The plan for the release: ship it in 2024, the release is close",
+			lang:  "en",
+			limit: 2,
+			want: []Suggestion{
+				{Tag: "release", Source: SourceKeyword, Count: 2},
+				{Tag: "plan", Source: SourceKeyword, Count: 1},
+			},
+		},
+		{
+			name: "unknown language uses all lists",
+			text: "und the это",
+			want: []Suggestion{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Extract(tt.text, tt.lang, tt.limit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Extract() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	text := "Отпуск в горах: маршрут, снаряжение. Маршрут через перевал, Горный Алтай."
+	vocabulary := []string{"Маршрут", "горный алтай", "работа", "снаряжение", "маршрут"}
+
+	got := Suggest(text, "ru", vocabulary, []string{"СНАРЯЖЕНИЕ"}, 4)
+	want := []Suggestion{
+		{Tag: "Маршрут", Source: SourceVocabulary, Count: 2},
+		{Tag: "горный алтай", Source: SourceVocabulary, Count: 1},
+		{Tag: "отпуск", Source: SourceKeyword, Count: 1},
+		{Tag: "горах", Source: SourceKeyword, Count: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Suggest() = %+v, want %+v", got, want)
+	}
+
+	if got := Suggest("", "", vocabulary, nil, 5); len(got) != 0 {
+		t.Errorf("Suggest(empty) = %+v, want none", got)
+	}
+}
@@ -0,0 +1,104 @@
+// Package lang определяет язык текста заметки: по письменности и частым
+// служебным словам. Этого хватает, чтобы выбрать конфигурацию полнотекстового
+// поиска; точной классификации коротких текстов он не обещает.
+package lang
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Коды языков (ISO 639-1), которые умеет определять Detect.
+const (
+	Russian   = "ru"
+	Ukrainian = "uk"
+	English   = "en"
+	German    = "de"
+	French    = "fr"
+	Spanish   = "es"
+)
+
+// Codes — все коды, которые может вернуть Detect. Конфигурации поиска для них
+// задаёт SQL-функция note_fts_config (migrations/0017_note_language.sql).
+var Codes = []string{Russian, Ukrainian, English, German, French, Spanish}
+
+// minLetters — на более коротком тексте язык не определяется.
+const minLetters = 3
+
+// stopwords — частые служебные слова латинских языков.
+var stopwords = map[string][]string{
+	English: {"the", "and", "is", "are", "of", "to", "in", "it", "that", "for", "with", "this", "was", "you", "not", "on"},
+	German:  {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "ich", "zu", "mit", "den", "von", "auf", "sie", "es"},
+	French:  {"le", "la", "les", "et", "est", "un", "une", "des", "du", "pas", "que", "pour", "dans", "je", "ce", "sur"},
+	Spanish: {"el", "los", "las", "y", "es", "un", "una", "del", "que", "por", "para", "con", "no", "en", "se", "lo"},
+}
+
+// markers — буквы, которые встречаются только в одном из языков.
+var markers = map[rune]string{
+	'ß': German, 'ä': German, 'ö': German, 'ü': German,
+	'ñ': Spanish, '¿': Spanish, '¡': Spanish,
+	'ç': French, 'œ': French, 'è': French, 'ê': French, 'à': French,
+	'і': Ukrainian, 'ї': Ukrainian, 'є': Ukrainian, 'ґ': Ukrainian,
+}
+
+// Valid сообщает, что code — один из Codes.
+func Valid(code string) bool {
+	for _, c := range Codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Detect возвращает код языка текста или "", если язык определить не удалось.
+func Detect(text string) string {
+	text = strings.ToLower(text)
+
+	var cyrillic, latin int
+	found := map[string]int{}
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+		if code, ok := markers[r]; ok {
+			found[code]++
+		}
+	}
+	if cyrillic+latin < minLetters {
+		return ""
+	}
+
+	if cyrillic >= latin {
+		if found[Ukrainian] > 0 {
+			return Ukrainian
+		}
+		return Russian
+	}
+
+	scores := map[string]int{}
+	for code, n := range found {
+		scores[code] = 2 * n
+	}
+	words := strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) })
+	for _, w := range words {
+		for code, list := range stopwords {
+			for _, s := range list {
+				if w == s {
+					scores[code]++
+				}
+			}
+		}
+	}
+
+	best, bestScore := "", 0
+	for _, code := range Codes {
+		if scores[code] > bestScore {
+			best, bestScore = code, scores[code]
+		}
+	}
+	return best
+}
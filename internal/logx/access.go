@@ -0,0 +1,153 @@
+package logx
+
+import (
+	"encoding/json"
+	"expvar"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"example.com/notes-api/internal/auth"
+	"example.com/notes-api/internal/dbtime"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// AccessEntry — одна строка журнала доступа.
+type AccessEntry struct {
+	Time       time.Time `json:"time"`
+	Method     string    `json:"method"`
+	Route      string    `json:"route,omitempty"`
+	Path       string    `json:"path"`
+	Status     int       `json:"status"`
+	Bytes      int       `json:"bytes"`
+	DurationMS float64   `json:"duration_ms"`
+	ClientIP   string    `json:"client_ip"`
+	RequestID  string    `json:"request_id,omitempty"`
+	// UserID — пользователь из токена (auth.RequireJWT); пусто для анонимных запросов.
+	UserID string `json:"user_id,omitempty"`
+	// DBQueries и DBMS — число запросов к БД и их общее время.
+	DBQueries int     `json:"db_queries,omitempty"`
+	DBMS      float64 `json:"db_ms,omitempty"`
+	// Slow — запрос дольше порога AccessLog.Slow.
+	Slow bool `json:"slow,omitempty"`
+}
+
+// slowRequests — счётчик медленных запросов, виден в /api/v1/admin/metrics.
+var slowRequests = expvar.NewInt("slow_requests")
+
+// AccessLog пишет по JSON-строке на запрос. Путь маскируется Redactor, а
+// Route — шаблон маршрута chi (/api/v1/notes/{id}), по которому удобно
+// группировать. Чтобы ID запроса попал в запись, middleware.RequestID
+// должен стоять раньше; ID пользователя записывается, если дальше по
+// цепочке токен проверил auth.RequireJWT.
+type AccessLog struct {
+	out      io.Writer
+	redactor *Redactor
+
+	// SampleRate — доля записываемых обычных запросов, (0, 1]; 0 — все.
+	// Ошибки (статус 5xx) пишутся всегда.
+	SampleRate float64
+	// Slow — порог медленного запроса; 0 — без порога. Медленный запрос
+	// пишется всегда, с пометкой slow, дополнительно попадает в
+	// стандартный лог предупреждением с раскладкой времени БД и
+	// учитывается в счётчике slow_requests.
+	Slow time.Duration
+
+	mu     sync.Mutex // сериализует запись строк в out
+	sample func() float64
+}
+
+// NewAccessLog создаёт журнал доступа, пишущий в out.
+func NewAccessLog(out io.Writer, redactor *Redactor) *AccessLog {
+	return &AccessLog{out: out, redactor: redactor, sample: rand.Float64}
+}
+
+// Handler — middleware журнала.
+func (a *AccessLog) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		ctx, db := dbtime.WithRecorder(auth.TrackUser(r.Context()))
+		r = r.WithContext(ctx)
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		defer func() {
+			a.write(r, ww, db.Stats(), started, time.Since(started))
+		}()
+		next.ServeHTTP(ww, r)
+	})
+}
+
+func (a *AccessLog) write(r *http.Request, ww middleware.WrapResponseWriter, db dbtime.Stats, started time.Time, elapsed time.Duration) {
+	status := ww.Status()
+	if status == 0 {
+		status = http.StatusOK
+	}
+	slow := a.Slow > 0 && elapsed >= a.Slow
+	if slow {
+		slowRequests.Add(1)
+	}
+	if !slow && !a.keep(status) {
+		return
+	}
+
+	entry := AccessEntry{
+		Time:       started,
+		Method:     r.Method,
+		Path:       a.redactor.URI(r.RequestURI),
+		Status:     status,
+		Bytes:      ww.BytesWritten(),
+		DurationMS: milliseconds(elapsed),
+		ClientIP:   clientIP(r),
+		RequestID:  middleware.GetReqID(r.Context()),
+		DBQueries:  db.Queries,
+		DBMS:       milliseconds(db.Total),
+		Slow:       slow,
+	}
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		entry.Route = rctx.RoutePattern()
+	}
+	if id, ok := auth.UserID(r.Context()); ok {
+		entry.UserID = strconv.FormatInt(id, 10)
+	}
+	if slow {
+		log.Printf("WARNING: slow request %s %s (%s) took %s: %d DB queries in %s, slowest %s",
+			entry.Method, entry.Route, entry.RequestID, elapsed.Round(time.Millisecond),
+			db.Queries, db.Total.Round(time.Millisecond), db.Slowest.Round(time.Millisecond))
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.out.Write(append(line, '\n'))
+}
+
+// keep решает, попадает ли обычный (не медленный) запрос в журнал при
+// выборочной записи.
+func (a *AccessLog) keep(status int) bool {
+	if a.SampleRate <= 0 || a.SampleRate >= 1 || status >= 500 {
+		return true
+	}
+	return a.sample() < a.SampleRate
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// clientIP — адрес клиента из соединения без порта. X-Forwarded-For не
+// учитывается: ему можно верить только за своим прокси.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,124 @@
+package logx
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/notes-api/internal/auth"
+	"example.com/notes-api/internal/dbtime"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	a := NewAccessLog(&buf, NewRedactor([]string{"limit"}))
+
+	r := chi.NewRouter()
+	r.Use(a.Handler)
+	r.Get("/notes/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/notes/7?limit=5&q=secret", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	var got AccessEntry
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid entry %q: %v", buf.String(), err)
+	}
+	if got.Route != "/notes/{id}" || got.Status != http.StatusOK || got.Bytes != 5 {
+		t.Errorf("got %+v", got)
+	}
+	if got.Path != "/notes/7?limit=5&q="+Redacted {
+		t.Errorf("path not redacted: %s", got.Path)
+	}
+	if got.ClientIP != "192.0.2.1" {
+		t.Errorf("client ip = %q", got.ClientIP)
+	}
+}
+
+func TestAccessLogUserID(t *testing.T) {
+	tokens, err := auth.NewJWT([]byte(strings.Repeat("s", auth.MinJWTSecret)), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, _, err := tokens.Issue(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	a := NewAccessLog(&buf, NewRedactor(nil))
+	r := chi.NewRouter()
+	r.Use(a.Handler)
+	r.Get("/public", func(w http.ResponseWriter, r *http.Request) {})
+	r.With(auth.RequireJWT(tokens, "")).Get("/notes", func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		path, token, want string
+	}{
+		{"/notes", token, "7"},
+		{"/notes", "", ""},
+		{"/public", token, ""},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		r.ServeHTTP(httptest.NewRecorder(), req)
+
+		var got AccessEntry
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("invalid entry %q: %v", buf.String(), err)
+		}
+		if got.UserID != tt.want {
+			t.Errorf("%s with token %t: user_id = %q, want %q", tt.path, tt.token != "", got.UserID, tt.want)
+		}
+	}
+}
+
+func TestAccessLogSlow(t *testing.T) {
+	var buf bytes.Buffer
+	a := NewAccessLog(&buf, NewRedactor(nil))
+	a.SampleRate = 0.1
+	a.sample = func() float64 { return 0.5 }
+	a.Slow = time.Millisecond
+
+	h := a.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dbtime.Observe(r.Context(), 3*time.Millisecond)
+		time.Sleep(2 * time.Millisecond)
+	}))
+	before := slowRequests.Value()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var got AccessEntry
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("slow request not logged: %q", buf.String())
+	}
+	if !got.Slow || got.DBQueries != 1 || got.DBMS != 3 {
+		t.Errorf("got %+v", got)
+	}
+	if slowRequests.Value() != before+1 {
+		t.Error("slow request not counted")
+	}
+}
+
+func TestAccessLogSampling(t *testing.T) {
+	a := NewAccessLog(nil, NewRedactor(nil))
+	a.SampleRate = 0.1
+	a.sample = func() float64 { return 0.5 }
+
+	if a.keep(http.StatusOK) {
+		t.Error("sampled-out request kept")
+	}
+	if !a.keep(http.StatusInternalServerError) {
+		t.Error("server error dropped")
+	}
+}
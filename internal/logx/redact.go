@@ -0,0 +1,76 @@
+// Package logx не даёт содержимому заметок, токенам и паролям попадать в логи.
+package logx
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Redacted подставляется вместо скрытых значений.
+const Redacted = "REDACTED"
+
+// DefaultQueryAllowlist — параметры запроса, значения которых безопасно логировать.
+var DefaultQueryAllowlist = []string{"since", "wait", "limit", "before", "sort", "color", "radius"}
+
+// secretSegments — сегменты пути, за которыми следует чувствительное значение
+// (slug строится из заголовка заметки).
+var secretSegments = map[string]bool{"by-slug": true}
+
+// Redactor маскирует URL запросов перед записью в лог.
+type Redactor struct {
+	allowQuery map[string]bool
+}
+
+// NewRedactor создаёт Redactor, оставляющий открытыми только параметры из allowlist.
+func NewRedactor(allowlist []string) *Redactor {
+	allow := make(map[string]bool, len(allowlist))
+	for _, name := range allowlist {
+		allow[strings.TrimSpace(name)] = true
+	}
+	return &Redactor{allowQuery: allow}
+}
+
+// URI возвращает RequestURI с замаскированными значениями параметров и секретными сегментами.
+func (rd *Redactor) URI(uri string) string {
+	path, rawQuery, hasQuery := strings.Cut(uri, "?")
+
+	segments := strings.Split(path, "/")
+	for i := 1; i < len(segments); i++ {
+		if secretSegments[segments[i-1]] && segments[i] != "" {
+			segments[i] = Redacted
+		}
+	}
+	path = strings.Join(segments, "/")
+
+	if !hasQuery {
+		return path
+	}
+
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return path + "?" + Redacted
+	}
+	for name, vals := range values {
+		if rd.allowQuery[name] {
+			continue
+		}
+		for i := range vals {
+			vals[i] = Redacted
+		}
+	}
+	return path + "?" + values.Encode()
+}
+
+var dsnPassword = regexp.MustCompile(`(password\s*=\s*)('[^']*'|\S+)`)
+
+// RedactDSN скрывает пароль в строке подключения к БД (URL или key=value).
+func RedactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), Redacted)
+		}
+		return u.String()
+	}
+	return dsnPassword.ReplaceAllString(dsn, "${1}"+Redacted)
+}
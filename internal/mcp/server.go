@@ -0,0 +1,352 @@
+// Package mcp — сервер Model Context Protocol: даёт LLM-ассистентам
+// инструменты search_notes, get_note и create_note поверх репозитория заметок.
+// Транспорты — stdio (команда mcp) и HTTP (POST /mcp с API-ключом).
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"example.com/notes-api/internal/changes"
+	"example.com/notes-api/internal/clock"
+	"example.com/notes-api/internal/core"
+	"example.com/notes-api/internal/http/handlers"
+)
+
+// Области доступа ключа: read — поиск и чтение, write — создание заметок.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+)
+
+// protocolVersions — поддерживаемые версии протокола, новые первыми.
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+const (
+	serverName    = "notes-api"
+	serverVersion = "1.0"
+
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// Коды ошибок JSON-RPC.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Notes — часть репозитория, которой пользуются инструменты.
+type Notes interface {
+	SearchTitles(ctx context.Context, query string, limit int) ([]core.Note, error)
+	GetByID(ctx context.Context, id int64) (*core.Note, error)
+	CreateWithLogTx(ctx context.Context, n core.NoteCreate) (int64, error)
+}
+
+// Server обрабатывает сообщения JSON-RPC протокола MCP.
+type Server struct {
+	// Limits — предельные размеры создаваемых заметок, как у POST /notes.
+	Limits core.Limits
+
+	notes   Notes
+	changes *changes.Feed
+	clock   clock.Clock
+}
+
+// NewServer создаёт сервер; feed может быть nil.
+func NewServer(notes Notes, feed *changes.Feed) *Server {
+	return &Server{notes: notes, changes: feed, clock: clock.System}
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Handle обрабатывает одно сообщение клиента с правами scopes и возвращает
+// ответ; для уведомлений (сообщений без id) ответа нет — nil.
+func (s *Server) Handle(ctx context.Context, scopes []string, raw []byte) []byte {
+	var req request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return encode(response{ID: json.RawMessage("null"), Error: &rpcError{codeParseError, "parse error"}})
+	}
+	if len(req.ID) == 0 {
+		return nil
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return encode(response{ID: req.ID, Error: &rpcError{codeInvalidRequest, "invalid request"}})
+	}
+
+	result, rerr := s.dispatch(ctx, scopes, req)
+	return encode(response{ID: req.ID, Result: result, Error: rerr})
+}
+
+func encode(resp response) []byte {
+	resp.JSONRPC = "2.0"
+	out, err := json.Marshal(resp)
+	if err != nil {
+		panic(err) // ответы строятся только из сериализуемых типов
+	}
+	return out
+}
+
+func (s *Server) dispatch(ctx context.Context, scopes []string, req request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		var p struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &p)
+		version := protocolVersions[0]
+		for _, v := range protocolVersions {
+			if v == p.ProtocolVersion {
+				version = v
+			}
+		}
+		return map[string]any{
+			"protocolVersion": version,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]string{"name": serverName, "version": serverVersion},
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		var list []tool
+		for _, t := range tools {
+			if hasScope(scopes, t.scope) {
+				list = append(list, t)
+			}
+		}
+		return map[string]any{"tools": list}, nil
+	case "tools/call":
+		var p struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return nil, &rpcError{codeInvalidParams, "invalid params"}
+		}
+		t, ok := findTool(p.Name)
+		if !ok || !hasScope(scopes, t.scope) {
+			return nil, &rpcError{codeInvalidParams, "unknown tool: " + p.Name}
+		}
+		if len(p.Arguments) == 0 {
+			p.Arguments = json.RawMessage(`{}`)
+		}
+		return s.call(ctx, p.Name, p.Arguments), nil
+	default:
+		return nil, &rpcError{codeMethodNotFound, "method not found: " + req.Method}
+	}
+}
+
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+====================
+TOOLS
+====================
+*/
+
+type tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+	scope       string
+}
+
+var tools = []tool{
+	{
+		Name:        "search_notes",
+		Description: "Full-text search over note titles. Returns matching notes (id, title, created_at), best matches first.",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{` +
+			`"query":{"type":"string","description":"Words to look for in note titles"},` +
+			`"limit":{"type":"integer","minimum":1,"maximum":100}},"required":["query"]}`),
+		scope: ScopeRead,
+	},
+	{
+		Name:        "get_note",
+		Description: "Get a note with its full content by ID.",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{` +
+			`"id":{"type":"integer"}},"required":["id"]}`),
+		scope: ScopeRead,
+	},
+	{
+		Name:        "create_note",
+		Description: "Create a note. Returns the created note.",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{` +
+			`"title":{"type":"string"},"content":{"type":"string"}},"required":["title"]}`),
+		scope: ScopeWrite,
+	},
+}
+
+func findTool(name string) (tool, bool) {
+	for _, t := range tools {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return tool{}, false
+}
+
+// toolNote — заметка в результатах инструментов.
+type toolNote struct {
+	ID        int64      `json:"id"`
+	Title     string     `json:"title"`
+	Content   string     `json:"content,omitempty"`
+	Encrypted bool       `json:"encrypted,omitempty"`
+	Lang      string     `json:"lang,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	URL       string     `json:"url"`
+}
+
+func newToolNote(n *core.Note, withContent bool) toolNote {
+	t := toolNote{
+		ID:        n.ID,
+		Title:     n.Title,
+		Encrypted: n.Encrypted,
+		Lang:      n.Lang,
+		CreatedAt: n.CreatedAt,
+		UpdatedAt: n.UpdatedAt,
+		URL:       "/api/v1/notes/" + strconv.FormatInt(n.ID, 10),
+	}
+	if withContent {
+		t.Content = n.Content
+	}
+	return t
+}
+
+// toolResult — результат tools/call. Ошибки инструмента (заметка не найдена,
+// неверные аргументы) возвращаются в нём с isError, а не ошибкой JSON-RPC,
+// чтобы модель видела их и могла исправить вызов.
+type toolResult struct {
+	Content []toolContent `json:"content"`
+	IsError bool          `json:"isError,omitempty"`
+}
+
+type toolContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func (s *Server) call(ctx context.Context, name string, args json.RawMessage) toolResult {
+	var (
+		out any
+		err error
+	)
+	switch name {
+	case "search_notes":
+		out, err = s.searchNotes(ctx, args)
+	case "get_note":
+		out, err = s.getNote(ctx, args)
+	case "create_note":
+		out, err = s.createNote(ctx, args)
+	}
+	if err != nil {
+		return toolResult{Content: []toolContent{{Type: "text", Text: err.Error()}}, IsError: true}
+	}
+	text, err := json.Marshal(out)
+	if err != nil {
+		return toolResult{Content: []toolContent{{Type: "text", Text: err.Error()}}, IsError: true}
+	}
+	return toolResult{Content: []toolContent{{Type: "text", Text: string(text)}}}
+}
+
+func (s *Server) searchNotes(ctx context.Context, args json.RawMessage) (any, error) {
+	var p struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit"`
+	}
+	if err := json.Unmarshal(args, &p); err != nil || p.Query == "" {
+		return nil, errors.New("query is required")
+	}
+	limit := defaultSearchLimit
+	if p.Limit > 0 {
+		limit = min(p.Limit, maxSearchLimit)
+	}
+
+	notes, err := s.notes.SearchTitles(ctx, p.Query, limit)
+	if err != nil {
+		return nil, errors.New("failed to search notes")
+	}
+	out := make([]toolNote, len(notes))
+	for i := range notes {
+		out[i] = newToolNote(&notes[i], false)
+	}
+	return out, nil
+}
+
+func (s *Server) getNote(ctx context.Context, args json.RawMessage) (any, error) {
+	var p struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.Unmarshal(args, &p); err != nil || p.ID <= 0 {
+		return nil, errors.New("id must be a positive integer")
+	}
+
+	note, err := s.notes.GetByID(ctx, p.ID)
+	if errors.Is(err, core.ErrNotFound) {
+		return nil, fmt.Errorf("note %d not found", p.ID)
+	}
+	if err != nil {
+		return nil, errors.New("failed to get note")
+	}
+	return newToolNote(note, true), nil
+}
+
+func (s *Server) createNote(ctx context.Context, args json.RawMessage) (any, error) {
+	var p struct {
+		Title   string `json:"title"`
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(args, &p); err != nil {
+		return nil, errors.New("title and content must be strings")
+	}
+	req := core.NoteCreate{Title: p.Title, Content: p.Content}
+	if code, msg := handlers.ValidateCreate(req, s.clock.Now(), s.Limits); code != "" {
+		return nil, errors.New(msg)
+	}
+
+	id, err := s.notes.CreateWithLogTx(ctx, req)
+	if errors.Is(err, core.ErrTitleTaken) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.New("failed to create note")
+	}
+	if s.changes != nil {
+		s.changes.Publish(id, changes.NoteCreated)
+	}
+	note, err := s.notes.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("failed to retrieve created note")
+	}
+	return newToolNote(note, true), nil
+}
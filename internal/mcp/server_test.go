@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/notes-api/internal/core"
+)
+
+type fakeNotes struct {
+	notes map[int64]*core.Note
+}
+
+func (f *fakeNotes) SearchTitles(ctx context.Context, query string, limit int) ([]core.Note, error) {
+	var out []core.Note
+	for _, n := range f.notes {
+		if strings.Contains(n.Title, query) {
+			out = append(out, *n)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeNotes) GetByID(ctx context.Context, id int64) (*core.Note, error) {
+	if n, ok := f.notes[id]; ok {
+		return n, nil
+	}
+	return nil, core.ErrNotFound
+}
+
+func (f *fakeNotes) CreateWithLogTx(ctx context.Context, n core.NoteCreate) (int64, error) {
+	id := int64(len(f.notes) + 1)
+	f.notes[id] = &core.Note{ID: id, Title: n.Title, Content: n.Content, CreatedAt: time.Now()}
+	return id, nil
+}
+
+func TestHandle(t *testing.T) {
+	read := []string{ScopeRead}
+	readWrite := []string{ScopeRead, ScopeWrite}
+
+	tests := []struct {
+		name   string
+		scopes []string
+		msg    string
+		want   string // подстрока ответа; пусто — ответа быть не должно
+	}{
+		{"initialize", read,
+			`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`,
+			`"protocolVersion":"2025-03-26"`},
+		{"notification", read, `{"jsonrpc":"2.0","method":"notifications/initialized"}`, ``},
+		{"parse error", read, `{`, `"code":-32700`},
+		{"unknown method", read, `{"jsonrpc":"2.0","id":2,"method":"resources/list"}`, `"code":-32601`},
+		{"list hides write tools", read,
+			`{"jsonrpc":"2.0","id":3,"method":"tools/list"}`, `"name":"get_note"`},
+		{"create needs write scope", read,
+			`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"create_note","arguments":{"title":"x"}}}`,
+			`unknown tool: create_note`},
+		{"create", readWrite,
+			`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"create_note","arguments":{"title":"Plan","content":"text"}}}`,
+			`\"title\":\"Plan\"`},
+		{"create validates title", readWrite,
+			`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"create_note","arguments":{"title":" "}}}`,
+			`"isError":true`},
+		{"get missing note", read,
+			`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"get_note","arguments":{"id":99}}}`,
+			`note 99 not found`},
+		{"search", read,
+			`{"jsonrpc":"2.0","id":8,"method":"tools/call","params":{"name":"search_notes","arguments":{"query":"Plan"}}}`,
+			`\"id\":1`},
+	}
+
+	s := NewServer(&fakeNotes{notes: map[int64]*core.Note{}}, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := s.Handle(context.Background(), tt.scopes, []byte(tt.msg))
+			if tt.want == "" {
+				if resp != nil {
+					t.Fatalf("want no response, got %s", resp)
+				}
+				return
+			}
+			if !json.Valid(resp) {
+				t.Fatalf("invalid JSON response: %s", resp)
+			}
+			if !strings.Contains(string(resp), tt.want) {
+				t.Fatalf("response %s does not contain %s", resp, tt.want)
+			}
+		})
+	}
+
+	resp := s.Handle(context.Background(), read, []byte(`{"jsonrpc":"2.0","id":9,"method":"tools/list"}`))
+	if strings.Contains(string(resp), "create_note") {
+		t.Fatalf("read scope lists create_note: %s", resp)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys("k1:read, k2:read+write")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys["k1"]) != 1 || len(keys["k2"]) != 2 {
+		t.Fatalf("got %v", keys)
+	}
+	for _, spec := range []string{"k1", "k1:", "k1:admin"} {
+		if _, err := ParseKeys(spec); err == nil {
+			t.Errorf("ParseKeys(%q): want error", spec)
+		}
+	}
+}
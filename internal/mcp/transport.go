@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxMessage — предельный размер одного сообщения клиента.
+const maxMessage = 1 << 20
+
+// ServeStdio читает сообщения из in по одному на строку и пишет ответы в out,
+// пока in не закроется. Права задаёт scopes: процесс запускает сам ассистент,
+// и ключ ему не нужен.
+func (s *Server) ServeStdio(ctx context.Context, scopes []string, in io.Reader, out io.Writer) error {
+	sc := bufio.NewScanner(in)
+	sc.Buffer(make([]byte, 64*1024), maxMessage)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		if resp := s.Handle(ctx, scopes, line); resp != nil {
+			if _, err := fmt.Fprintf(out, "%s\n", resp); err != nil {
+				return err
+			}
+		}
+	}
+	return sc.Err()
+}
+
+// ParseKeys разбирает API-ключи вида "key:scope+scope,key:scope", например
+// "k1:read,k2:read+write".
+func ParseKeys(spec string) (map[string][]string, error) {
+	keys := map[string][]string{}
+	for _, part := range strings.Split(spec, ",") {
+		key, scopeList, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok || key == "" || scopeList == "" {
+			return nil, fmt.Errorf("invalid key entry %q, want key:scope+scope", part)
+		}
+		scopes := strings.Split(scopeList, "+")
+		for _, scope := range scopes {
+			if scope != ScopeRead && scope != ScopeWrite {
+				return nil, fmt.Errorf("unknown scope %q", scope)
+			}
+		}
+		keys[key] = scopes
+	}
+	return keys, nil
+}
+
+// HTTPHandler — HTTP-транспорт: каждый POST несёт одно сообщение, ответ
+// приходит в теле (без потоковой передачи). Доступ — по заголовку
+// "Authorization: Bearer <key>", права — области этого ключа.
+func (s *Server) HTTPHandler(keys map[string][]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		scopes, ok := lookupKey(keys, r.Header.Get("Authorization"))
+		if !ok {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "invalid API key", http.StatusUnauthorized)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessage))
+		if err != nil {
+			http.Error(w, "message too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		resp := s.Handle(r.Context(), scopes, body)
+		if resp == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(resp)
+	})
+}
+
+// lookupKey сравнивает ключ со всеми известными за постоянное время.
+func lookupKey(keys map[string][]string, header string) ([]string, bool) {
+	got, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok || got == "" {
+		return nil, false
+	}
+	var found []string
+	for key, scopes := range keys {
+		if subtle.ConstantTimeCompare([]byte(got), []byte(key)) == 1 {
+			found = scopes
+		}
+	}
+	return found, found != nil
+}
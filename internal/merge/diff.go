@@ -0,0 +1,64 @@
+package merge
+
+// Op — вид участка diff.
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+// Chunk — подряд идущие строки одного вида; строки хранят свои "\n".
+type Chunk struct {
+	Op    Op
+	Lines []string
+}
+
+// Lines строит построчный diff от a к b. Общие начало и конец в LCS-таблицу
+// не попадают, поэтому небольшая правка длинного текста сравнивается дёшево.
+// ok = false, если различающаяся часть слишком велика (см. maxCells).
+func Lines(a, b string) (chunks []Chunk, ok bool) {
+	x, y := splitLines(a), splitLines(b)
+
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+	xm, ym := x[prefix:len(x)-suffix], y[prefix:len(y)-suffix]
+	if len(xm)*len(ym) > maxCells {
+		return nil, false
+	}
+
+	chunks = appendChunk(chunks, OpEqual, x[:prefix]...)
+	match := lcsMatch(xm, ym)
+	j := 0
+	for i, m := range match {
+		if m < 0 {
+			chunks = appendChunk(chunks, OpDelete, xm[i])
+			continue
+		}
+		chunks = appendChunk(chunks, OpInsert, ym[j:m]...)
+		chunks = appendChunk(chunks, OpEqual, xm[i])
+		j = m + 1
+	}
+	chunks = appendChunk(chunks, OpInsert, ym[j:]...)
+	chunks = appendChunk(chunks, OpEqual, x[len(x)-suffix:]...)
+	return chunks, true
+}
+
+// appendChunk добавляет строки к последнему участку того же вида или новым участком.
+func appendChunk(chunks []Chunk, op Op, lines ...string) []Chunk {
+	if len(lines) == 0 {
+		return chunks
+	}
+	if n := len(chunks); n > 0 && chunks[n-1].Op == op {
+		chunks[n-1].Lines = append(chunks[n-1].Lines, lines...)
+		return chunks
+	}
+	return append(chunks, Chunk{Op: op, Lines: append([]string(nil), lines...)})
+}
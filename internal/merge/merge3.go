@@ -0,0 +1,130 @@
+// Package merge реализует построчное трёхстороннее слияние текстов (diff3).
+package merge
+
+import "strings"
+
+// maxCells ограничивает размер LCS-таблицы, чтобы огромные тексты
+// не съедали память: такие тексты считаются несливаемыми.
+const maxCells = 4_000_000
+
+// ThreeWay сливает изменения ours и theirs относительно общего предка base.
+// ok = false, если правки пересекаются и автоматическое слияние невозможно.
+func ThreeWay(base, ours, theirs string) (merged string, ok bool) {
+	switch {
+	case ours == theirs:
+		return ours, true
+	case ours == base:
+		return theirs, true
+	case theirs == base:
+		return ours, true
+	}
+
+	b, o, t := splitLines(base), splitLines(ours), splitLines(theirs)
+	if len(b)*len(o) > maxCells || len(b)*len(t) > maxCells {
+		return "", false
+	}
+
+	mo := lcsMatch(b, o)
+	mt := lcsMatch(b, t)
+
+	var out strings.Builder
+	i, j, k := 0, 0, 0
+	for {
+		// Ищем следующую строку base, сохранённую в обеих версиях.
+		p := i
+		for p < len(b) && (mo[p] < 0 || mt[p] < 0) {
+			p++
+		}
+
+		bEnd, oEnd, tEnd := len(b), len(o), len(t)
+		if p < len(b) {
+			bEnd, oEnd, tEnd = p, mo[p], mt[p]
+		}
+
+		chunk, ok := resolve(b[i:bEnd], o[j:oEnd], t[k:tEnd])
+		if !ok {
+			return "", false
+		}
+		for _, line := range chunk {
+			out.WriteString(line)
+		}
+
+		if p >= len(b) {
+			break
+		}
+		out.WriteString(b[p])
+		i, j, k = p+1, mo[p]+1, mt[p]+1
+	}
+
+	return out.String(), true
+}
+
+// resolve выбирает результат для участка между стабильными строками.
+func resolve(base, ours, theirs []string) ([]string, bool) {
+	switch {
+	case equal(ours, theirs):
+		return ours, true
+	case equal(ours, base):
+		return theirs, true
+	case equal(theirs, base):
+		return ours, true
+	default:
+		return nil, false
+	}
+}
+
+// lcsMatch возвращает для каждой строки a индекс совпавшей строки b
+// в наибольшей общей подпоследовательности (или -1).
+func lcsMatch(a, b []string) []int {
+	n, m := len(a), len(b)
+	dp := make([][]int32, n+1)
+	for i := range dp {
+		dp[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				dp[i][j] = dp[i+1][j+1] + 1
+			} else {
+				dp[i][j] = max(dp[i+1][j], dp[i][j+1])
+			}
+		}
+	}
+
+	match := make([]int, n)
+	for i := range match {
+		match[i] = -1
+	}
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case a[i] == b[j]:
+			match[i] = j
+			i++
+			j++
+		case dp[i+1][j] >= dp[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return match
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.SplitAfter(s, "\n")
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package merge
+
+import "testing"
+
+func TestThreeWay(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, ours, theirs string
+		want               string
+		ok                 bool
+	}{
+		// Чистые слияния
+		{"only ours changed", "a\nb\n", "a\nB\n", "a\nb\n", "a\nB\n", true},
+		{"only theirs changed", "a\nb\n", "a\nb\n", "A\nb\n", "A\nb\n", true},
+		{"edits in different lines", "a\nb\nc\n", "A\nb\nc\n", "a\nb\nC\n", "A\nb\nC\n", true},
+		{"insertions in different places", "a\nc\n", "a\nb\nc\n", "a\nc\nd\n", "a\nb\nc\nd\n", true},
+		{"deletion and edit elsewhere", "a\nb\nc\nd\n", "a\nc\nd\n", "a\nb\nc\nD\n", "a\nc\nD\n", true},
+		{"no trailing newline", "a\nb\nc", "A\nb\nc", "a\nb\nC", "A\nb\nC", true},
+
+		// Одинаковые правки с обеих сторон
+		{"same change on both sides", "a\nb\n", "a\nX\n", "a\nX\n", "a\nX\n", true},
+		{"same chunk plus separate edit", "a\nb\nc\n", "X\nb\nc\n", "X\nb\nC\n", "X\nb\nC\n", true},
+		{"both deleted the same line", "a\nb\nc\n", "a\nc\n", "a\nc\n", "a\nc\n", true},
+
+		// Пересекающиеся правки
+		{"same line changed differently", "a\nb\nc\n", "a\nB1\nc\n", "a\nB2\nc\n", "", false},
+		{"edit against deletion", "a\nb\nc\n", "a\nB\nc\n", "a\nc\n", "", false},
+		// Как в diff3: правки соседних строк считаются пересекающимися
+		{"adjacent lines changed", "a\nb\n", "A\nb\n", "a\nB\n", "", false},
+		{"different insertions at one place", "a\nc\n", "a\nx\nc\n", "a\ny\nc\n", "", false},
+
+		// Пустой base
+		{"empty base, only ours added", "", "a\n", "", "a\n", true},
+		{"empty base, same text added", "", "a\nb\n", "a\nb\n", "a\nb\n", true},
+		{"empty base, different text added", "", "a\n", "b\n", "", false},
+		{"everything empty", "", "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ThreeWay(tt.base, tt.ours, tt.theirs)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("ThreeWay(%q, %q, %q) = %q, %v; want %q, %v", tt.base, tt.ours, tt.theirs, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Package preview готовит данные карточки заметки: короткое превью текста
+// без разметки Markdown и вид содержимого (обычный текст, Markdown, список
+// дел), чтобы клиенты рисовали списки, не запрашивая полный текст.
+package preview
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Runes — длина превью в символах.
+const Runes = 200
+
+// Виды содержимого заметки.
+const (
+	KindPlain     = "plain"
+	KindMarkdown  = "markdown"
+	KindChecklist = "checklist"
+)
+
+var (
+	fence    = regexp.MustCompile("(?m)^\\s*(```|~~~).*$")
+	image    = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	link     = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	block    = regexp.MustCompile(`(?m)^\s{0,3}(#{1,6}\s+|>\s?|[-*+]\s+(\[[ xX]\]\s+)?|\d+[.)]\s+)`)
+	emphasis = regexp.MustCompile("(\\*\\*|__|~~|[*_`])")
+	rule     = regexp.MustCompile(`(?m)^\s*([-*_]\s*){3,}$`)
+	task     = regexp.MustCompile(`^\s*[-*+]\s+\[[ xX]\]\s`)
+	markdown = regexp.MustCompile("(?m)(^\\s{0,3}(#{1,6}\\s|>|[-*+]\\s|\\d+[.)]\\s|```|~~~|\\|)|\\*\\*|__|\\]\\(|`)")
+)
+
+// Text — начало content одной строкой без разметки, не длиннее Runes символов.
+func Text(content string) string {
+	s := fence.ReplaceAllString(content, "")
+	s = rule.ReplaceAllString(s, "")
+	s = image.ReplaceAllString(s, "$1")
+	s = link.ReplaceAllString(s, "$1")
+	s = block.ReplaceAllString(s, "")
+	s = emphasis.ReplaceAllString(s, "")
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > Runes {
+		return strings.TrimRight(string(r[:Runes]), " ") + "…"
+	}
+	return s
+}
+
+// Kind определяет вид содержимого: checklist — если хотя бы половина
+// непустых строк — пункты списка дел ("- [ ] ..."), markdown — если есть
+// разметка, иначе plain.
+func Kind(content string) string {
+	var lines, tasks int
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines++
+		if task.MatchString(line) {
+			tasks++
+		}
+	}
+	switch {
+	case tasks > 0 && tasks*2 >= lines:
+		return KindChecklist
+	case markdown.MatchString(content):
+		return KindMarkdown
+	}
+	return KindPlain
+}
@@ -0,0 +1,46 @@
+package preview
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"empty", "", ""},
+		{"short", "  коротко  ", "коротко"},
+		{"lines", "первая строка\n\nвторая   строка", "первая строка вторая строка"},
+		{"markdown", "# План\n\n- **купить** [молоко](https://shop)\n- [x] `позвонить`\n> цитата", "План купить молоко позвонить цитата"},
+		{"code fence", "```go\nx := 1\n```", "x := 1"},
+		{"exact", strings.Repeat("я", Runes), strings.Repeat("я", Runes)},
+		{"long", strings.Repeat("я", Runes+1), strings.Repeat("я", Runes) + "…"},
+		{"cut at space", strings.Repeat("a", Runes-1) + " b", strings.Repeat("a", Runes-1) + "…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Text(tt.content); got != tt.want {
+				t.Errorf("Text = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKind(t *testing.T) {
+	tests := map[string]string{
+		"": KindPlain,
+		"просто текст, без разметки":               KindPlain,
+		"## Заголовок\nтекст":                      KindMarkdown,
+		"см. [доку](https://example.com)":          KindMarkdown,
+		"Покупки:\n- [ ] хлеб\n- [x] молоко":       KindChecklist,
+		"- [ ] одно дело\nа дальше\nмного\nтекста": KindMarkdown,
+	}
+	for content, want := range tests {
+		if got := Kind(content); got != want {
+			t.Errorf("Kind(%q) = %q, want %q", content, got, want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Package proofread проверяет орфографию и грамматику текста внешним
+// сервисом. Сервер ничего не исправляет сам: он возвращает найденные ошибки
+// с позициями, а применять ли замены, решает клиент.
+package proofread
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Suggestion — найденная ошибка. Offset и Length считаются в единицах UTF-16,
+// как у LanguageTool и строк JavaScript.
+type Suggestion struct {
+	Offset  int    `json:"offset" example:"7"`
+	Length  int    `json:"length" example:"5"`
+	Message string `json:"message" example:"Возможно, найдена орфографическая ошибка."`
+	// Rule — идентификатор правила проверки.
+	Rule         string   `json:"rule,omitempty" example:"MORFOLOGIK_RULE_RU_RU"`
+	Replacements []string `json:"replacements"`
+}
+
+// Checker проверяет text на языке lang: код ISO 639-1 или "auto".
+type Checker interface {
+	Check(ctx context.Context, text, lang string) ([]Suggestion, error)
+}
+
+// ltVariants — варианты языков, без которых LanguageTool не проверяет орфографию.
+var ltVariants = map[string]string{"en": "en-US", "de": "de-DE", "ru": "ru-RU", "uk": "uk-UA"}
+
+// maxReplacements — сколько вариантов замены отдавать на одну ошибку.
+const maxReplacements = 5
+
+// LanguageTool — Checker поверх HTTP API LanguageTool (обычно self-hosted).
+type LanguageTool struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewLanguageTool создаёт клиент сервера LanguageTool по адресу baseURL,
+// например "http://languagetool:8010".
+func NewLanguageTool(baseURL string) *LanguageTool {
+	return &LanguageTool{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type ltResponse struct {
+	Matches []struct {
+		Message      string `json:"message"`
+		Offset       int    `json:"offset"`
+		Length       int    `json:"length"`
+		Replacements []struct {
+			Value string `json:"value"`
+		} `json:"replacements"`
+		Rule struct {
+			ID string `json:"id"`
+		} `json:"rule"`
+	} `json:"matches"`
+}
+
+// Check отправляет text в /v2/check.
+func (lt *LanguageTool) Check(ctx context.Context, text, lang string) ([]Suggestion, error) {
+	if v, ok := ltVariants[lang]; ok {
+		lang = v
+	}
+	form := url.Values{"text": {text}, "language": {lang}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, lt.baseURL+"/v2/check", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := lt.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("languagetool: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var lr ltResponse
+	if err := json.NewDecoder(resp.Body).Decode(&lr); err != nil {
+		return nil, fmt.Errorf("languagetool: decode response: %w", err)
+	}
+
+	out := make([]Suggestion, len(lr.Matches))
+	for i, m := range lr.Matches {
+		out[i] = Suggestion{
+			Offset:       m.Offset,
+			Length:       m.Length,
+			Message:      m.Message,
+			Rule:         m.Rule.ID,
+			Replacements: make([]string, 0, min(len(m.Replacements), maxReplacements)),
+		}
+		for _, r := range m.Replacements[:min(len(m.Replacements), maxReplacements)] {
+			out[i].Replacements = append(out[i].Replacements, r.Value)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,40 @@
+package proofread
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLanguageToolCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/check" || r.FormValue("language") != "ru-RU" || r.FormValue("text") != "Привет, мир" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"matches":[{"message":"Опечатка","offset":8,"length":3,
+			"replacements":[{"value":"мир"},{"value":"мор"}],"rule":{"id":"SPELL"}}]}`))
+	}))
+	defer srv.Close()
+
+	got, err := NewLanguageTool(srv.URL+"/").Check(context.Background(), "Привет, мир", "ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("suggestions = %+v, want one", got)
+	}
+	s := got[0]
+	if s.Offset != 8 || s.Length != 3 || s.Rule != "SPELL" || len(s.Replacements) != 2 || s.Replacements[0] != "мир" {
+		t.Errorf("suggestion = %+v", s)
+	}
+
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	})
+	if _, err := NewLanguageTool(srv.URL).Check(context.Background(), "x", "auto"); err == nil {
+		t.Error("want error for non-200 response")
+	}
+}
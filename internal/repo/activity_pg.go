@@ -0,0 +1,49 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"example.com/notes-api/internal/core"
+)
+
+// execer — общий интерфейс *sql.DB и *sql.Tx для записи.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// logAction пишет действие над заметкой в notes_log.
+func (r *NoteRepoPG) logAction(ctx context.Context, ex execer, noteID int64, action string) error {
+	_, err := ex.ExecContext(ctx,
+		`INSERT INTO notes_log (note_id, action, created_at) VALUES ($1, $2, $3)`,
+		noteID, action, r.clock.Now(),
+	)
+	return err
+}
+
+// ListActivity возвращает записи notes_log с ID меньше beforeID (0 — с начала),
+// от новых к старым. Заголовок подтягивается для ещё существующих заметок.
+func (r *NoteRepoPG) ListActivity(ctx context.Context, beforeID int64, limit int) ([]core.ActivityEntry, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT l.id, l.note_id, l.action, n.title, l.created_at
+		FROM notes_log l
+		LEFT JOIN notes n ON n.id = l.note_id
+		WHERE ($1::bigint = 0 OR l.id < $1)
+		ORDER BY l.id DESC
+		LIMIT $2
+	`, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []core.ActivityEntry{}
+	for rows.Next() {
+		var e core.ActivityEntry
+		if err := rows.Scan(&e.ID, &e.NoteID, &e.Action, &e.NoteTitle, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
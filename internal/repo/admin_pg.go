@@ -0,0 +1,91 @@
+package repo
+
+import (
+	"context"
+
+	"example.com/notes-api/internal/core"
+)
+
+// ListAllNotes возвращает заметки с ID меньше beforeID (0 — с начала), от новых
+// к старым, включая удалённые и истёкшие, но ещё не вычищенные.
+func (r *NoteRepoPG) ListAllNotes(ctx context.Context, beforeID int64, limit int) ([]core.Note, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT `+noteColumns+`
+		FROM notes
+		WHERE ($1::bigint = 0 OR id < $1)
+		ORDER BY id DESC
+		LIMIT $2
+	`, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanNotes(rows)
+}
+
+// GetAnyByID возвращает заметку по ID независимо от удаления и срока жизни.
+func (r *NoteRepoPG) GetAnyByID(ctx context.Context, id int64) (*core.Note, error) {
+	n, err := r.scanNote(r.conn(ctx).QueryRowContext(ctx, `
+		SELECT `+noteColumns+`
+		FROM notes
+		WHERE id = $1
+	`, id))
+	return n, notFound(err)
+}
+
+// Restore возвращает удалённую или истёкшую заметку: снимает пометку удаления
+// и прошедший срок жизни. Пишет запись в notes_log, если что-то изменилось;
+// если заметки нет (или она уже вычищена) — core.ErrNotFound.
+func (r *NoteRepoPG) Restore(ctx context.Context, id int64) error {
+	tx, err := r.begin(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := r.clock.Now()
+	res, err := tx.ExecContext(ctx, `
+		UPDATE notes
+		SET deleted_at = NULL,
+		    expires_at = CASE WHEN expires_at <= $2 THEN NULL ELSE expires_at END,
+		    version = version + 1,
+		    updated_at = $2
+		WHERE id = $1
+		  AND (deleted_at IS NOT NULL OR expires_at <= $2)
+	`, id, now)
+	if err != nil {
+		// Пока заметка была удалена, её заголовок мог занять кто-то ещё
+		var title string
+		if r.db.QueryRowContext(ctx, `SELECT title FROM notes WHERE id = $1`, id).Scan(&title) == nil {
+			return r.titleTaken(ctx, err, title)
+		}
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return noteExists(ctx, tx, id)
+	}
+
+	if err := r.logAction(ctx, tx, id, core.ActionRestored); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// noteExists возвращает core.ErrNotFound, если строки заметки нет вовсе,
+// независимо от удаления и срока жизни.
+func noteExists(ctx context.Context, q queryer, id int64) error {
+	var exists bool
+	if err := q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM notes WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return core.ErrNotFound
+	}
+	return nil
+}
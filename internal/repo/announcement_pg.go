@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"context"
+
+	"example.com/notes-api/internal/core"
+)
+
+// SetAnnouncement делает заметку объявлением с окном показа a или, при a == nil,
+// снимает объявление, и пишет изменение в notes_log. Повторное объявление
+// только меняет окно; удалённую или истёкшую заметку объявить нельзя —
+// core.ErrNotFound. Снятие с заметки, которая не объявление, ничего не меняет.
+func (r *NoteRepoPG) SetAnnouncement(ctx context.Context, id int64, a *core.NoteAnnouncement) error {
+	tx, err := r.begin(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE notes
+		SET announced_at = NULL, announce_from = NULL, announce_until = NULL
+		WHERE id = $1 AND announced_at IS NOT NULL
+	`
+	args := []any{id}
+	action := core.ActionUnannounced
+	if a != nil {
+		query = `
+			UPDATE notes
+			SET announced_at = COALESCE(announced_at, $2), announce_from = $3, announce_until = $4
+			WHERE id = $1 AND ` + visible + `
+		`
+		args = append(args, r.clock.Now(), a.From, a.Until)
+		action = core.ActionAnnounced
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		if a != nil {
+			return core.ErrNotFound
+		}
+		return nil
+	}
+
+	if err := r.logAction(ctx, tx, id, action); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,120 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"testing"
+	"time"
+
+	"example.com/notes-api/internal/core"
+	_ "github.com/lib/pq"
+)
+
+// Бенчмарки репозитория идут против настоящего Postgres с применёнными
+// миграциями; без NOTES_TEST_DATABASE_URL они пропускаются. Одноразовую БД
+// поднимает make bench:
+//
+//	NOTES_TEST_DATABASE_URL=postgres://... go test -bench . -run ^$ ./internal/repo
+//
+// Тестовые заметки получают ID от benchBaseID и удаляются после бенчмарка.
+
+const (
+	benchBaseID = 1 << 40
+	benchNotes  = 5000
+)
+
+// benchRepo подключается к тестовой БД и очищает диапазон тестовых ID.
+func benchRepo(b *testing.B) (*NoteRepoPG, func()) {
+	b.Helper()
+	dsn := os.Getenv("NOTES_TEST_DATABASE_URL")
+	if dsn == "" {
+		b.Skip("NOTES_TEST_DATABASE_URL is not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		b.Fatal(err)
+	}
+	cleanup := func() {
+		if _, err := db.Exec(`DELETE FROM notes WHERE id >= $1`, int64(benchBaseID)); err != nil {
+			b.Fatal(err)
+		}
+	}
+	cleanup()
+	b.Cleanup(func() {
+		cleanup()
+		db.Close()
+	})
+	return NewNoteRepoPG(db), cleanup
+}
+
+// benchSeed заполняет БД count тестовыми заметками.
+func benchSeed(b *testing.B, r *NoteRepoPG, count int) []core.Note {
+	b.Helper()
+	notes := benchBackupNotes(count)
+	for start := 0; start < len(notes); start += 1000 {
+		end := min(start+1000, len(notes))
+		if _, err := r.RestoreBatch(context.Background(), notes[start:end]); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return notes
+}
+
+// benchBackupNotes собирает заметки, похожие на строки резервной копии;
+// created_at убывает с ростом ID, как у обычной ленты.
+func benchBackupNotes(count int) []core.Note {
+	now := time.Now().UTC()
+	notes := make([]core.Note, count)
+	for i := range notes {
+		id := int64(benchBaseID + i)
+		created := now.Add(-time.Duration(i) * time.Second)
+		notes[i] = core.Note{
+			ID:        id,
+			Title:     fmt.Sprintf("Bench note %d", i),
+			Content:   "Lorem ipsum dolor sit amet, consectetur adipiscing elit.",
+			Slug:      fmt.Sprintf("bench-note-%d", id),
+			Version:   1,
+			Metadata:  []byte(`{"source":"bench"}`),
+			Color:     core.DefaultColor,
+			Position:  float64(i),
+			CreatedAt: created,
+			UpdatedAt: &created,
+		}
+	}
+	return notes
+}
+
+func BenchmarkGetByID(b *testing.B) {
+	r, _ := benchRepo(b)
+	notes := benchSeed(b, r, benchNotes)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n := notes[rand.IntN(len(notes))]
+		if _, err := r.GetByID(ctx, n.ID); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkListAfterCursor(b *testing.B) {
+	r, _ := benchRepo(b)
+	notes := benchSeed(b, r, benchNotes)
+	ctx := context.Background()
+
+	for _, limit := range []int{20, 100} {
+		b.Run(fmt.Sprintf("limit=%d", limit), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				n := notes[rand.IntN(len(notes))]
+				cursor := core.NoteCursor{CreatedAt: n.CreatedAt, ID: n.ID}
+				if _, err := r.ListAfterCursor(ctx, cursor, limit); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
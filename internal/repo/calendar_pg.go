@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"example.com/notes-api/internal/core"
+)
+
+// Calendar группирует заметки, созданные в [from, to), по дням в часовом поясе tz.
+// Для каждого дня возвращает общее число заметок и не более perDay первых из них.
+func (r *NoteRepoPG) Calendar(ctx context.Context, from, to time.Time, tz string, perDay int) ([]core.CalendarDay, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		WITH ranked AS (
+			SELECT id, title,
+			       date_trunc('day', created_at AT TIME ZONE $3)::date AS day,
+			       count(*) OVER w AS total,
+			       row_number() OVER (w ORDER BY created_at, id) AS rn
+			FROM notes
+			WHERE created_at >= $1 AND created_at < $2 AND `+visible+`
+			WINDOW w AS (PARTITION BY date_trunc('day', created_at AT TIME ZONE $3))
+		)
+		SELECT to_char(day, 'YYYY-MM-DD'), total, id, title
+		FROM ranked
+		WHERE rn <= $4
+		ORDER BY day, rn
+	`, from, to, tz, perDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	days := []core.CalendarDay{}
+	for rows.Next() {
+		var (
+			date  string
+			total int
+			n     core.NoteShort
+		)
+		if err := rows.Scan(&date, &total, &n.ID, &n.Title); err != nil {
+			return nil, err
+		}
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, core.CalendarDay{Date: date, Count: total, Notes: []core.NoteShort{}})
+		}
+		last := &days[len(days)-1]
+		last.Notes = append(last.Notes, n)
+	}
+	return days, rows.Err()
+}
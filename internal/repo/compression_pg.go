@@ -0,0 +1,116 @@
+package repo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// compressContent сжимает текст gzip; результат — base64, чтобы храниться в
+// той же текстовой колонке content.
+func compressContent(content string) (string, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := io.WriteString(zw, content); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decompressContent восстанавливает текст, сжатый compressContent.
+func decompressContent(packed string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(packed)
+	if err != nil {
+		return "", fmt.Errorf("compressed content: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return "", fmt.Errorf("compressed content: %w", err)
+	}
+	defer zr.Close()
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		return "", fmt.Errorf("compressed content: %w", err)
+	}
+	return string(out), nil
+}
+
+// CompressBatch сжимает content до limit заметок с ID больше afterID, которые
+// записаны до включения сжатия и длиннее порога. Возвращает ID последней
+// просмотренной заметки (курсор для следующего вызова), число просмотренных
+// и число сжатых.
+func (r *NoteRepoPG) CompressBatch(ctx context.Context, afterID int64, limit int) (lastID int64, scanned, compressed int, err error) {
+	if r.compressAbove <= 0 {
+		return 0, 0, 0, errors.New("compression is not configured")
+	}
+
+	tx, err := r.begin(ctx, nil)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer tx.Rollback()
+
+	// Длина зашифрованного content больше исходной, поэтому порог ещё раз
+	// проверяется по открытому тексту в sealContent.
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, content, content_key_id
+		FROM notes
+		WHERE NOT content_compressed AND length(content) > $1 AND id > $2
+		ORDER BY id
+		LIMIT $3
+		FOR UPDATE
+	`, r.compressAbove, afterID, limit)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	type pending struct {
+		id      int64
+		content string
+		keyID   *string
+	}
+	var batch []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.id, &p.content, &p.keyID); err != nil {
+			rows.Close()
+			return 0, 0, 0, err
+		}
+		batch = append(batch, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, 0, err
+	}
+
+	lastID = afterID
+	for _, p := range batch {
+		lastID = p.id
+		plaintext, err := r.openContent(p.content, p.keyID, false)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("note %d: %w", p.id, err)
+		}
+		sealed, keyID, packed, err := r.sealContent(plaintext)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		if !packed {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE notes SET content = $1, content_key_id = $2, content_compressed = true WHERE id = $3`,
+			sealed, keyID, p.id,
+		); err != nil {
+			return 0, 0, 0, err
+		}
+		compressed++
+	}
+	return lastID, len(batch), compressed, tx.Commit()
+}
@@ -0,0 +1,60 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"example.com/notes-api/internal/core"
+)
+
+// dailyLockKey — ключ advisory-блокировки, сериализующей создание ежедневных заметок.
+const dailyLockKey = 698
+
+// DailyNoteID возвращает ID ежедневной заметки за day (YYYY-MM-DD) или 0, если её нет.
+func (r *NoteRepoPG) DailyNoteID(ctx context.Context, day string) (int64, error) {
+	return dailyNoteID(ctx, r.conn(ctx), day)
+}
+
+// CreateDaily возвращает ежедневную заметку за day, при отсутствии создавая её из n.
+// created сообщает, была ли заметка создана этим вызовом.
+func (r *NoteRepoPG) CreateDaily(ctx context.Context, day string, n core.NoteCreate) (id int64, created bool, err error) {
+	tx, err := r.begin(ctx, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, dailyLockKey); err != nil {
+		return 0, false, err
+	}
+
+	id, err = dailyNoteID(ctx, tx, day)
+	if err != nil || id != 0 {
+		return id, false, err
+	}
+
+	id, err = r.insertNote(ctx, tx, n)
+	if err != nil {
+		return 0, false, err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE notes SET daily_date = $2 WHERE id = $1`, id, day); err != nil {
+		return 0, false, err
+	}
+	if err := r.logAction(ctx, tx, id, core.ActionCreated); err != nil {
+		return 0, false, err
+	}
+	return id, true, tx.Commit()
+}
+
+func dailyNoteID(ctx context.Context, q queryer, day string) (int64, error) {
+	var id int64
+	err := q.QueryRowContext(ctx, `
+		SELECT id FROM notes
+		WHERE daily_date = $1 AND `+visible+`
+	`, day).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return id, err
+}
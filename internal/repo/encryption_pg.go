@@ -0,0 +1,120 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// sealContent готовит content к записи: сжимает, если он длиннее порога
+// сжатия, и шифрует текущим ключом, если шифрование включено. Пустой content
+// не трогается: у зашифрованных клиентом заметок он всегда пуст.
+func (r *NoteRepoPG) sealContent(content string) (sealed string, keyID *string, compressed bool, err error) {
+	if content == "" {
+		return content, nil, false, nil
+	}
+	if r.compressAbove > 0 && len(content) > r.compressAbove {
+		packed, err := compressContent(content)
+		if err != nil {
+			return "", nil, false, err
+		}
+		// Несжимаемый текст (base64, уже сжатые данные) хранится как есть
+		if len(packed) < len(content) {
+			content, compressed = packed, true
+		}
+	}
+	if r.keyring == nil {
+		return content, nil, compressed, nil
+	}
+	sealed, id, err := r.keyring.Encrypt(content)
+	if err != nil {
+		return "", nil, false, err
+	}
+	return sealed, &id, compressed, nil
+}
+
+// openContent возвращает исходный content: расшифровывает значение,
+// сохранённое ключом keyID (nil — открытый текст), и распаковывает сжатое.
+func (r *NoteRepoPG) openContent(sealed string, keyID *string, compressed bool) (string, error) {
+	content := sealed
+	if keyID != nil {
+		if r.keyring == nil {
+			return "", fmt.Errorf("note content is encrypted with key %q, but encryption is not configured", *keyID)
+		}
+		var err error
+		if content, err = r.keyring.Decrypt(sealed, *keyID); err != nil {
+			return "", err
+		}
+	}
+	if compressed {
+		return decompressContent(content)
+	}
+	return content, nil
+}
+
+// ReencryptBatch перешифровывает текущим ключом до limit заметок, чей content
+// хранится открытым текстом или под другим ключом. Возвращает число обработанных.
+func (r *NoteRepoPG) ReencryptBatch(ctx context.Context, limit int) (int, error) {
+	if r.keyring == nil {
+		return 0, errors.New("encryption is not configured")
+	}
+
+	tx, err := r.begin(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, content, content_key_id, content_compressed
+		FROM notes
+		WHERE content <> ''
+		  AND content_key_id IS DISTINCT FROM $1
+		ORDER BY id
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`, r.keyring.CurrentKeyID(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	type pending struct {
+		id         int64
+		content    string
+		keyID      *string
+		compressed bool
+	}
+	var batch []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.id, &p.content, &p.keyID, &p.compressed); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		batch = append(batch, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, p := range batch {
+		plaintext, err := r.openContent(p.content, p.keyID, p.compressed)
+		if err != nil {
+			return 0, fmt.Errorf("note %d: %w", p.id, err)
+		}
+
+		sealed, keyID, compressed, err := r.sealContent(plaintext)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE notes SET content = $1, content_key_id = $2, content_compressed = $3 WHERE id = $4`,
+			sealed, keyID, compressed, p.id,
+		); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(batch), tx.Commit()
+}
@@ -0,0 +1,30 @@
+package repo
+
+import (
+	"context"
+
+	"example.com/notes-api/internal/core"
+)
+
+// PurgeExpired удаляет до limit истёкших заметок (кроме удерживаемых) и пишет для каждой запись в notes_log.
+// Возвращает число удалённых заметок.
+func (r *NoteRepoPG) PurgeExpired(ctx context.Context, limit int) (int64, error) {
+	res, err := r.conn(ctx).ExecContext(ctx, `
+		WITH purged AS (
+			DELETE FROM notes
+			WHERE id IN (
+				SELECT id FROM notes
+				WHERE expires_at <= now() AND legal_hold_at IS NULL
+				ORDER BY expires_at
+				LIMIT $1
+			)
+			RETURNING id
+		)
+		INSERT INTO notes_log (note_id, action, created_at)
+		SELECT id, $2, $3 FROM purged
+	`, limit, core.ActionExpired, r.clock.Now())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,47 @@
+package repo
+
+import (
+	"context"
+
+	"example.com/notes-api/internal/core"
+)
+
+// ListNearby возвращает заметки в радиусе radius метров от точки, ближайшие первыми.
+// earth_box отсекает кандидатов по GiST-индексу, earth_distance уточняет радиус.
+func (r *NoteRepoPG) ListNearby(ctx context.Context, lat, lon, radius float64, limit int) ([]core.NearbyNote, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT `+noteColumns+`, d.distance
+		FROM notes,
+		     LATERAL (SELECT earth_distance(ll_to_earth($1, $2), ll_to_earth(latitude, longitude)) AS distance) d
+		WHERE latitude IS NOT NULL AND `+visible+`
+		  AND earth_box(ll_to_earth($1, $2), $3) @> ll_to_earth(latitude, longitude)
+		  AND d.distance <= $3
+		ORDER BY d.distance
+		LIMIT $4
+	`, lat, lon, radius, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []core.NearbyNote{}
+	for rows.Next() {
+		var distance float64
+		n, err := r.scanNote(rowWithExtra{rows, []any{&distance}})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, core.NearbyNote{Note: *n, DistanceMeters: distance})
+	}
+	return result, rows.Err()
+}
+
+// rowWithExtra дописывает к Scan из scanNote дополнительные колонки выборки.
+type rowWithExtra struct {
+	row   rowScanner
+	extra []any
+}
+
+func (r rowWithExtra) Scan(dest ...any) error {
+	return r.row.Scan(append(dest, r.extra...)...)
+}
@@ -0,0 +1,45 @@
+package repo
+
+import (
+	"context"
+
+	"example.com/notes-api/internal/core"
+)
+
+// SetLegalHold ставит (hold = true) или снимает юридическое удержание заметки
+// и пишет изменение в notes_log. Повторный вызов с тем же состоянием ничего не меняет;
+// если заметки нет — core.ErrNotFound.
+func (r *NoteRepoPG) SetLegalHold(ctx context.Context, id int64, hold bool) error {
+	tx, err := r.begin(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE notes
+		SET legal_hold_at = CASE WHEN $2 THEN $3::timestamptz END
+		WHERE id = $1
+		  AND (legal_hold_at IS NULL) = $2
+	`, id, hold, r.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return noteExists(ctx, tx, id)
+	}
+
+	action := core.ActionHoldReleased
+	if hold {
+		action = core.ActionHoldPlaced
+	}
+	if err := r.logAction(ctx, tx, id, action); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
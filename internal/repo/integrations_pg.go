@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"context"
+
+	"example.com/notes-api/internal/core"
+)
+
+// ListCreatedAfter возвращает до limit заметок с ID больше afterID, новые
+// первыми. ID растут монотонно, поэтому последний увиденный ID — стабильный
+// курсор для опроса новых заметок.
+func (r *NoteRepoPG) ListCreatedAfter(ctx context.Context, afterID int64, limit int) ([]core.Note, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT `+noteColumns+`
+		FROM notes
+		WHERE id > $1 AND `+visible+`
+		ORDER BY id DESC
+		LIMIT $2
+	`, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanNotes(rows)
+}
+
+// SearchTitles ищет заметки по словам заголовка полнотекстовым поиском в
+// конфигурации языка каждой заметки (см. idx_notes_title_fts). Зашифрованные
+// заметки не ищутся. Лучшие совпадения — первыми.
+func (r *NoteRepoPG) SearchTitles(ctx context.Context, query string, limit int) ([]core.Note, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT `+noteColumns+`
+		FROM notes,
+		     LATERAL plainto_tsquery(note_fts_config(lang), $1) AS q
+		WHERE NOT encrypted
+		  AND to_tsvector(note_fts_config(lang), title) @@ q
+		  AND `+visible+`
+		ORDER BY ts_rank(to_tsvector(note_fts_config(lang), title), q) DESC, id DESC
+		LIMIT $2
+	`, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanNotes(rows)
+}
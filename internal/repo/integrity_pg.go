@@ -0,0 +1,59 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"example.com/notes-api/internal/core"
+)
+
+// integrityTargets — подзапросы, выбирающие проблемные строки каждой проверки
+// на момент $1.
+var integrityTargets = map[string]string{
+	core.CheckOrphanLog: `
+		SELECT l.id FROM notes_log l
+		WHERE l.created_at <= $1
+		  AND NOT EXISTS (SELECT 1 FROM notes n WHERE n.id = l.note_id)
+		  AND NOT EXISTS (
+			SELECT 1 FROM notes_log t
+			WHERE t.note_id = l.note_id AND t.action = '` + core.ActionExpired + `'
+		  )`,
+	core.CheckExpiredLocks: `
+		SELECT note_id FROM note_locks WHERE expires_at <= $1`,
+}
+
+// integrityRepairs — удаление до $2 строк из подзапроса integrityTargets.
+var integrityRepairs = map[string]string{
+	core.CheckOrphanLog:    `DELETE FROM notes_log WHERE id IN (%s ORDER BY l.id LIMIT $2)`,
+	core.CheckExpiredLocks: `DELETE FROM note_locks WHERE note_id IN (%s ORDER BY note_id LIMIT $2)`,
+}
+
+// CountIntegrity возвращает число проблемных строк для проверки check.
+func (r *NoteRepoPG) CountIntegrity(ctx context.Context, check string) (int64, error) {
+	target, ok := integrityTargets[check]
+	if !ok {
+		return 0, fmt.Errorf("unknown integrity check %q", check)
+	}
+
+	var n int64
+	err := r.conn(ctx).QueryRowContext(ctx,
+		`SELECT count(*) FROM (`+target+`) t`, r.clock.Now(),
+	).Scan(&n)
+	return n, err
+}
+
+// RepairIntegrity удаляет до limit проблемных строк проверки check.
+// Возвращает число удалённых; меньше limit — исправлять больше нечего.
+func (r *NoteRepoPG) RepairIntegrity(ctx context.Context, check string, limit int) (int64, error) {
+	target, ok := integrityTargets[check]
+	if !ok {
+		return 0, fmt.Errorf("unknown integrity check %q", check)
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx,
+		fmt.Sprintf(integrityRepairs[check], target), r.clock.Now(), limit)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
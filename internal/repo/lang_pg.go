@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+)
+
+// DetectLangBatch определяет язык до limit заметок с пустым lang и ID больше
+// afterID. Возвращает ID последней просмотренной заметки (курсор для
+// следующего вызова), число просмотренных и число заметок, чей язык определился.
+func (r *NoteRepoPG) DetectLangBatch(ctx context.Context, afterID int64, limit int) (lastID int64, scanned, detected int, err error) {
+	tx, err := r.begin(ctx, nil)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, title, content, content_key_id, content_compressed
+		FROM notes
+		WHERE lang = '' AND id > $1
+		ORDER BY id
+		LIMIT $2
+	`, afterID, limit)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	type pending struct {
+		id         int64
+		title      string
+		content    string
+		keyID      *string
+		compressed bool
+	}
+	var batch []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.id, &p.title, &p.content, &p.keyID, &p.compressed); err != nil {
+			rows.Close()
+			return 0, 0, 0, err
+		}
+		batch = append(batch, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, 0, err
+	}
+
+	lastID = afterID
+	for _, p := range batch {
+		lastID = p.id
+		content, err := r.openContent(p.content, p.keyID, p.compressed)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("note %d: %w", p.id, err)
+		}
+		code := detectLang(p.title, content)
+		if code == "" {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE notes SET lang = $1 WHERE id = $2`, code, p.id); err != nil {
+			return 0, 0, 0, err
+		}
+		detected++
+	}
+	return lastID, len(batch), detected, tx.Commit()
+}
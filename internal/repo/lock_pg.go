@@ -0,0 +1,97 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"example.com/notes-api/internal/core"
+)
+
+// Lock ставит или продлевает блокировку заметки для owner.
+// Если заметку держит другой владелец и блокировка не истекла,
+// возвращает текущую блокировку и core.ErrNoteLocked. Удалённую, истёкшую
+// или несуществующую заметку заблокировать нельзя — core.ErrNotFound.
+func (r *NoteRepoPG) Lock(ctx context.Context, noteID int64, owner string, ttl time.Duration) (*core.NoteLock, error) {
+	now := r.clock.Now()
+
+	lock := core.NoteLock{NoteID: noteID}
+	err := r.conn(ctx).QueryRowContext(ctx, `
+		INSERT INTO note_locks (note_id, owner, expires_at)
+		SELECT id, $2, $3
+		FROM notes
+		WHERE id = $1 AND `+visible+`
+		ON CONFLICT (note_id) DO UPDATE
+		SET owner = EXCLUDED.owner,
+		    expires_at = EXCLUDED.expires_at
+		WHERE note_locks.owner = EXCLUDED.owner
+		   OR note_locks.expires_at <= $4
+		RETURNING owner, expires_at
+	`, noteID, owner, now.Add(ttl), now).Scan(&lock.Owner, &lock.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		var exists bool
+		err := r.conn(ctx).QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM notes WHERE id = $1 AND `+visible+`)`, noteID,
+		).Scan(&exists)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, core.ErrNotFound
+		}
+		current, err := r.GetLock(ctx, noteID)
+		if err != nil {
+			return nil, err
+		}
+		return current, core.ErrNoteLocked
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lock, nil
+}
+
+// Unlock снимает блокировку owner. Истёкшую блокировку может снять кто угодно.
+func (r *NoteRepoPG) Unlock(ctx context.Context, noteID int64, owner string) error {
+	res, err := r.conn(ctx).ExecContext(ctx, `
+		DELETE FROM note_locks
+		WHERE note_id = $1
+		  AND (owner = $2 OR expires_at <= $3)
+	`, noteID, owner, r.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		current, err := r.GetLock(ctx, noteID)
+		if err != nil {
+			return err
+		}
+		if current != nil {
+			return core.ErrNoteLocked
+		}
+	}
+	return nil
+}
+
+// GetLock возвращает действующую блокировку заметки или nil, если её нет.
+func (r *NoteRepoPG) GetLock(ctx context.Context, noteID int64) (*core.NoteLock, error) {
+	lock := core.NoteLock{NoteID: noteID}
+	err := r.conn(ctx).QueryRowContext(ctx, `
+		SELECT owner, expires_at
+		FROM note_locks
+		WHERE note_id = $1 AND expires_at > $2
+	`, noteID, r.clock.Now()).Scan(&lock.Owner, &lock.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lock, nil
+}
@@ -0,0 +1,1076 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/notes-api/internal/clock"
+	"example.com/notes-api/internal/core"
+	"example.com/notes-api/internal/preview"
+	"example.com/notes-api/internal/slug"
+	"example.com/notes-api/internal/textnorm"
+)
+
+// earthRadius — радиус Земли в метрах, как у earth() из earthdistance.
+const earthRadius = 6378168
+
+// NoteRepoMemory — репозиторий заметок в памяти процесса для разработки и
+// тестов (STORAGE=memory). Повторяет поведение NoteRepoPG, кроме того, что
+// требует PostgreSQL: шифрования и сжатия content при хранении, уникальности
+// заголовков, полнотекстового поиска (ищутся подстроки слов) и статистики
+// таблиц. Данные пропадают при перезапуске.
+type NoteRepoMemory struct {
+	clock clock.Clock
+
+	// txMu сериализует WithinTx и DryRun: откат восстанавливает снимок,
+	// поэтому запись вне транзакции в это время тоже откатится.
+	txMu sync.Mutex
+
+	mu    sync.Mutex
+	state memoryState
+}
+
+// memoryState — всё содержимое репозитория; копируется для отката транзакции.
+type memoryState struct {
+	notes  map[int64]core.Note
+	log    []core.ActivityEntry
+	locks  map[int64]core.NoteLock
+	daily  map[string]int64
+	nextID int64
+}
+
+func (s memoryState) clone() memoryState {
+	c := memoryState{
+		notes:  make(map[int64]core.Note, len(s.notes)),
+		log:    slices.Clone(s.log),
+		locks:  make(map[int64]core.NoteLock, len(s.locks)),
+		daily:  make(map[string]int64, len(s.daily)),
+		nextID: s.nextID,
+	}
+	for id, n := range s.notes {
+		c.notes[id] = n
+	}
+	for id, l := range s.locks {
+		c.locks[id] = l
+	}
+	for day, id := range s.daily {
+		c.daily[day] = id
+	}
+	return c
+}
+
+// memoryTxKey — ключ контекста, отмечающий вызов внутри WithinTx или DryRun.
+type memoryTxKey struct{}
+
+// MemoryOption настраивает репозитории в памяти.
+type MemoryOption func(*memoryConfig)
+
+type memoryConfig struct {
+	clock clock.Clock
+}
+
+// WithMemoryClock подменяет часы, по которым репозитории в памяти ставят
+// метки времени и проверяют сроки (истечение заметок, блокировки).
+func WithMemoryClock(c clock.Clock) MemoryOption {
+	return func(cfg *memoryConfig) {
+		cfg.clock = c
+	}
+}
+
+func newMemoryConfig(opts []MemoryOption) memoryConfig {
+	cfg := memoryConfig{clock: clock.System}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// NewNoteRepoMemory создаёт пустой репозиторий в памяти.
+func NewNoteRepoMemory(opts ...MemoryOption) *NoteRepoMemory {
+	return &NoteRepoMemory{
+		clock: newMemoryConfig(opts).clock,
+		state: memoryState{
+			notes: map[int64]core.Note{},
+			locks: map[int64]core.NoteLock{},
+			daily: map[string]int64{},
+		},
+	}
+}
+
+var _ core.NoteRepository = (*NoteRepoMemory)(nil)
+
+/*
+====================
+TRANSACTIONS
+====================
+*/
+
+// WithinTx выполняет fn атомарно: при ошибке все изменения откатываются.
+// Вложенный вызов выполняется в транзакции внешнего.
+func (r *NoteRepoMemory) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.atomic(ctx, fn, false)
+}
+
+// DryRun выполняет fn и откатывает все изменения; возвращает ошибку fn.
+func (r *NoteRepoMemory) DryRun(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.atomic(ctx, fn, true)
+}
+
+func (r *NoteRepoMemory) atomic(ctx context.Context, fn func(ctx context.Context) error, rollback bool) error {
+	if ctx.Value(memoryTxKey{}) != nil {
+		return fn(ctx)
+	}
+	r.txMu.Lock()
+	defer r.txMu.Unlock()
+
+	r.mu.Lock()
+	snapshot := r.state.clone()
+	r.mu.Unlock()
+
+	err := fn(context.WithValue(ctx, memoryTxKey{}, true))
+	if err != nil || rollback {
+		r.mu.Lock()
+		r.state = snapshot
+		r.mu.Unlock()
+	}
+	return err
+}
+
+/*
+====================
+CRUD
+====================
+*/
+
+// CreateWithLogTx создаёт заметку и запись о создании в журнале.
+func (r *NoteRepoMemory) CreateWithLogTx(ctx context.Context, n core.NoteCreate) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := r.insert(n)
+	r.logAction(id, core.ActionCreated)
+	return id, nil
+}
+
+// insert нормализует и сохраняет новую заметку так же, как NoteRepoPG.insertNote.
+func (r *NoteRepoMemory) insert(c core.NoteCreate) int64 {
+	c.Title, c.Content = textnorm.Title(c.Title), textnorm.Content(c.Content)
+
+	r.state.nextID++
+	n := core.Note{
+		ID:         r.state.nextID,
+		Title:      c.Title,
+		Content:    c.Content,
+		Lang:       detectLang(c.Title, c.Content),
+		Slug:       r.uniqueSlug(c.Title, 0),
+		Version:    1,
+		Metadata:   c.Metadata,
+		Color:      c.Color,
+		Icon:       c.Icon,
+		Position:   r.topPosition(),
+		Latitude:   c.Latitude,
+		Longitude:  c.Longitude,
+		ExpiresAt:  c.ExpiresAt,
+		Encrypted:  c.Encrypted,
+		Ciphertext: c.Ciphertext,
+		Nonce:      c.Nonce,
+		CreatedAt:  r.clock.Now(),
+	}
+	if len(n.Metadata) == 0 {
+		n.Metadata = json.RawMessage(`{}`)
+	}
+	if n.Color == "" {
+		n.Color = core.DefaultColor
+	}
+	if c.KeyID != "" {
+		n.KeyID = &c.KeyID
+	}
+	if !c.Encrypted {
+		n.ContentKind, n.Preview = preview.Kind(c.Content), preview.Text(c.Content)
+	}
+	r.state.notes[n.ID] = n
+	return n.ID
+}
+
+// topPosition — позиция новой заметки: перед всеми остальными.
+func (r *NoteRepoMemory) topPosition() float64 {
+	top := 1.0
+	for _, n := range r.state.notes {
+		top = min(top, n.Position)
+	}
+	return top - 1
+}
+
+// uniqueSlug строит slug так же, как uniqueSlug для PostgreSQL.
+func (r *NoteRepoMemory) uniqueSlug(title string, excludeID int64) string {
+	base := slug.Make(title)
+	taken := map[string]bool{}
+	for id, n := range r.state.notes {
+		if id != excludeID {
+			taken[n.Slug] = true
+		}
+	}
+	if !taken[base] {
+		return base
+	}
+	for i := 2; ; i++ {
+		if candidate := base + "-" + strconv.Itoa(i); !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+// GetByID возвращает заметку по ID или core.ErrNotFound.
+func (r *NoteRepoMemory) GetByID(ctx context.Context, id int64) (*core.Note, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.state.notes[id]
+	if !ok || !r.visible(n) {
+		return nil, core.ErrNotFound
+	}
+	return r.view(n), nil
+}
+
+// Update меняет заметку по правилам NoteRepoPG.Update.
+func (r *NoteRepoMemory) Update(ctx context.Context, id int64, u core.NoteUpdate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.state.notes[id]
+	if !ok || !r.visible(n) {
+		return core.ErrNotFound
+	}
+	if u.BaseVersion != nil && n.Version != *u.BaseVersion {
+		return core.ErrVersionConflict
+	}
+
+	now := r.clock.Now()
+	if u.Title != nil {
+		n.Title = textnorm.Title(*u.Title)
+	}
+	if u.Content != nil {
+		n.Content = textnorm.Content(*u.Content)
+		n.ContentKind, n.Preview = preview.Kind(n.Content), preview.Text(n.Content)
+	}
+	if u.Title != nil || u.Content != nil {
+		n.Lang = detectLang(n.Title, n.Content)
+	}
+	if u.RegenerateSlug {
+		n.Slug = r.uniqueSlug(n.Title, id)
+	}
+	if u.Metadata != nil {
+		n.Metadata = u.Metadata
+	}
+	if u.Color != nil {
+		n.Color = *u.Color
+	}
+	if u.Icon != nil {
+		n.Icon = *u.Icon
+	}
+	switch {
+	case u.ClearLocation:
+		n.Latitude, n.Longitude = nil, nil
+	case u.Latitude != nil:
+		n.Latitude, n.Longitude = u.Latitude, u.Longitude
+	}
+	switch {
+	case u.ClearExpiry:
+		n.ExpiresAt = nil
+	case u.ExpiresAt != nil:
+		n.ExpiresAt = u.ExpiresAt
+	}
+	if len(u.Ciphertext) > 0 {
+		n.Ciphertext = u.Ciphertext
+	}
+	if len(u.Nonce) > 0 {
+		n.Nonce = u.Nonce
+	}
+	if u.KeyID != nil {
+		n.KeyID = u.KeyID
+	}
+	if u.Archived != nil {
+		switch {
+		case !*u.Archived:
+			n.ArchivedAt = nil
+		case n.ArchivedAt == nil:
+			n.ArchivedAt = &now
+		}
+	}
+	n.Version++
+	n.UpdatedAt = &now
+
+	r.state.notes[id] = n
+	r.logAction(id, core.ActionUpdated)
+	return nil
+}
+
+// Delete помечает заметку удалённой; заметку на удержании — core.ErrLegalHold.
+func (r *NoteRepoMemory) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.state.notes[id]
+	if !ok || n.DeletedAt != nil {
+		return core.ErrNotFound
+	}
+	if n.LegalHoldAt != nil {
+		return core.ErrLegalHold
+	}
+	now := r.clock.Now()
+	n.DeletedAt = &now
+	r.state.notes[id] = n
+	r.logAction(id, core.ActionDeleted)
+	return nil
+}
+
+// List возвращает заметки по фильтру в порядке NoteRepoPG.List.
+func (r *NoteRepoMemory) List(ctx context.Context, f core.NoteFilter) ([]core.Note, error) {
+	var filter any
+	if len(f.Metadata) > 0 {
+		if err := json.Unmarshal(f.Metadata, &filter); err != nil {
+			return nil, err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	notes := r.filter(func(n core.Note) bool {
+		if (n.ArchivedAt != nil) != f.Archived {
+			return false
+		}
+		if filter != nil && !metadataContains(n.Metadata, filter) {
+			return false
+		}
+		return (f.Color == "" || n.Color == f.Color) &&
+			(f.Lang == "" || n.Lang == f.Lang) &&
+			(f.ReviewState == "" || n.ReviewState == f.ReviewState)
+	})
+	sort.SliceStable(notes, func(i, j int) bool {
+		a, b := notes[i], notes[j]
+		if !f.Archived && a.Announced != b.Announced {
+			return a.Announced
+		}
+		if f.Sort == core.SortManual {
+			return a.Position < b.Position || a.Position == b.Position && a.ID < b.ID
+		}
+		return newerFirst(a, b)
+	})
+	return notes, nil
+}
+
+/*
+====================
+READ QUERIES
+====================
+*/
+
+// GetBySlug возвращает заметку по slug или core.ErrNotFound.
+func (r *NoteRepoMemory) GetBySlug(ctx context.Context, s string) (*core.Note, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, n := range r.state.notes {
+		if n.Slug == s && r.visible(n) {
+			return r.view(n), nil
+		}
+	}
+	return nil, core.ErrNotFound
+}
+
+// FindByTitle ищет заметки по заголовку без учёта регистра, как NoteRepoPG.FindByTitle.
+func (r *NoteRepoMemory) FindByTitle(ctx context.Context, title string, prefix bool, limit int) ([]core.Note, error) {
+	want := strings.ToLower(title)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	notes := r.filter(func(n core.Note) bool {
+		got := strings.ToLower(n.Title)
+		return got == want || prefix && strings.HasPrefix(got, want)
+	})
+	sort.SliceStable(notes, func(i, j int) bool {
+		a, b := strings.ToLower(notes[i].Title), strings.ToLower(notes[j].Title)
+		if (a == want) != (b == want) {
+			return a == want
+		}
+		return a < b || a == b && notes[i].ID < notes[j].ID
+	})
+	return head(notes, limit), nil
+}
+
+// SearchTitles ищет незашифрованные заметки, в заголовке которых есть все
+// слова query (подстрокой, без учёта регистра), новые первыми.
+func (r *NoteRepoMemory) SearchTitles(ctx context.Context, query string, limit int) ([]core.Note, error) {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return []core.Note{}, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	notes := r.filter(func(n core.Note) bool {
+		title := strings.ToLower(n.Title)
+		for _, w := range words {
+			if !strings.Contains(title, w) {
+				return false
+			}
+		}
+		return !n.Encrypted
+	})
+	sortByIDDesc(notes)
+	return head(notes, limit), nil
+}
+
+// ListCreatedAfter возвращает до limit заметок с ID больше afterID, новые первыми.
+func (r *NoteRepoMemory) ListCreatedAfter(ctx context.Context, afterID int64, limit int) ([]core.Note, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	notes := r.filter(func(n core.Note) bool { return n.ID > afterID })
+	sortByIDDesc(notes)
+	return head(notes, limit), nil
+}
+
+// ListRecentlyUpdated возвращает неархивные заметки, изменённые последними.
+func (r *NoteRepoMemory) ListRecentlyUpdated(ctx context.Context, limit int) ([]core.Note, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	notes := r.filter(func(n core.Note) bool { return n.ArchivedAt == nil })
+	touched := func(n core.Note) time.Time {
+		if n.UpdatedAt != nil {
+			return *n.UpdatedAt
+		}
+		return n.CreatedAt
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		a, b := touched(notes[i]), touched(notes[j])
+		return a.After(b) || a.Equal(b) && notes[i].ID > notes[j].ID
+	})
+	return head(notes, limit), nil
+}
+
+// ListRecentlyViewed возвращает недавно просмотренные заметки.
+func (r *NoteRepoMemory) ListRecentlyViewed(ctx context.Context, limit int) ([]core.Note, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	notes := r.filter(func(n core.Note) bool { return n.LastViewedAt != nil })
+	sort.SliceStable(notes, func(i, j int) bool {
+		return notes[i].LastViewedAt.After(*notes[j].LastViewedAt)
+	})
+	return head(notes, limit), nil
+}
+
+// ListExpiringBefore возвращает видимые заметки, срок жизни которых истекает до until.
+func (r *NoteRepoMemory) ListExpiringBefore(ctx context.Context, until time.Time, limit int) ([]core.Note, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	notes := r.filter(func(n core.Note) bool { return n.ExpiresAt != nil && !n.ExpiresAt.After(until) })
+	sort.SliceStable(notes, func(i, j int) bool {
+		a, b := *notes[i].ExpiresAt, *notes[j].ExpiresAt
+		return a.Before(b) || a.Equal(b) && notes[i].ID < notes[j].ID
+	})
+	return head(notes, limit), nil
+}
+
+// ListNearby возвращает заметки в радиусе radius метров от точки, ближайшие первыми.
+func (r *NoteRepoMemory) ListNearby(ctx context.Context, lat, lon, radius float64, limit int) ([]core.NearbyNote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := []core.NearbyNote{}
+	for _, n := range r.filter(func(n core.Note) bool { return n.Latitude != nil && n.Longitude != nil }) {
+		if d := distance(lat, lon, *n.Latitude, *n.Longitude); d <= radius {
+			result = append(result, core.NearbyNote{Note: n, DistanceMeters: d})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].DistanceMeters < result[j].DistanceMeters })
+	return head(result, limit), nil
+}
+
+// Calendar группирует заметки, созданные в [from, to), по дням в часовом поясе tz.
+func (r *NoteRepoMemory) Calendar(ctx context.Context, from, to time.Time, tz string, perDay int) ([]core.CalendarDay, error) {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	notes := r.filter(func(n core.Note) bool { return !n.CreatedAt.Before(from) && n.CreatedAt.Before(to) })
+	sort.SliceStable(notes, func(i, j int) bool {
+		a, b := notes[i].CreatedAt, notes[j].CreatedAt
+		return a.Before(b) || a.Equal(b) && notes[i].ID < notes[j].ID
+	})
+
+	days := []core.CalendarDay{}
+	for _, n := range notes {
+		date := n.CreatedAt.In(loc).Format(time.DateOnly)
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, core.CalendarDay{Date: date, Notes: []core.NoteShort{}})
+		}
+		last := &days[len(days)-1]
+		last.Count++
+		if len(last.Notes) < perDay {
+			last.Notes = append(last.Notes, core.NoteShort{ID: n.ID, Title: n.Title})
+		}
+	}
+	return days, nil
+}
+
+// NoteStats собирает статистику заметки по журналу изменений.
+func (r *NoteRepoMemory) NoteStats(ctx context.Context, id int64) (*core.NoteStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.state.notes[id]
+	if !ok || !r.visible(n) {
+		return nil, core.ErrNotFound
+	}
+	s := core.NoteStats{
+		NoteID:       id,
+		Version:      n.Version,
+		ViewCount:    n.ViewCount,
+		LastViewedAt: n.LastViewedAt,
+		CreatedAt:    n.CreatedAt,
+	}
+	for _, e := range r.state.log {
+		if e.NoteID == id && e.Action == core.ActionUpdated {
+			s.Edits++
+			at := e.CreatedAt
+			s.LastEditedAt = &at
+		}
+	}
+	days := max(time.Since(s.CreatedAt).Hours()/24, 1)
+	s.EditsPerDay = float64(s.Edits) / days
+	return &s, nil
+}
+
+// ListActivity возвращает записи журнала с ID меньше beforeID (0 — с начала),
+// от новых к старым.
+func (r *NoteRepoMemory) ListActivity(ctx context.Context, beforeID int64, limit int) ([]core.ActivityEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := []core.ActivityEntry{}
+	for i := len(r.state.log) - 1; i >= 0 && len(entries) < limit; i-- {
+		e := r.state.log[i]
+		if beforeID != 0 && e.ID >= beforeID {
+			continue
+		}
+		if n, ok := r.state.notes[e.NoteID]; ok {
+			title := n.Title
+			e.NoteTitle = &title
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+/*
+====================
+DAILY NOTES
+====================
+*/
+
+// DailyNoteID возвращает ID ежедневной заметки за day или 0, если её нет.
+func (r *NoteRepoMemory) DailyNoteID(ctx context.Context, day string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.dailyNoteID(day), nil
+}
+
+func (r *NoteRepoMemory) dailyNoteID(day string) int64 {
+	id, ok := r.state.daily[day]
+	if !ok {
+		return 0
+	}
+	if n, ok := r.state.notes[id]; !ok || !r.visible(n) {
+		return 0
+	}
+	return id
+}
+
+// CreateDaily возвращает ежедневную заметку за day, при отсутствии создавая её из n.
+func (r *NoteRepoMemory) CreateDaily(ctx context.Context, day string, n core.NoteCreate) (id int64, created bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id := r.dailyNoteID(day); id != 0 {
+		return id, false, nil
+	}
+	id = r.insert(n)
+	r.state.daily[day] = id
+	r.logAction(id, core.ActionCreated)
+	return id, true, nil
+}
+
+/*
+====================
+LOCKS, POSITION, REVIEW
+====================
+*/
+
+// Lock ставит или продлевает блокировку заметки для owner; чужая действующая
+// блокировка — core.ErrNoteLocked вместе с ней, невидимая заметка — core.ErrNotFound.
+func (r *NoteRepoMemory) Lock(ctx context.Context, noteID int64, owner string, ttl time.Duration) (*core.NoteLock, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n, ok := r.state.notes[noteID]; !ok || !r.visible(n) {
+		return nil, core.ErrNotFound
+	}
+	now := r.clock.Now()
+	if current, ok := r.state.locks[noteID]; ok && current.Owner != owner && current.ExpiresAt.After(now) {
+		return &current, core.ErrNoteLocked
+	}
+	lock := core.NoteLock{NoteID: noteID, Owner: owner, ExpiresAt: now.Add(ttl)}
+	r.state.locks[noteID] = lock
+	return &lock, nil
+}
+
+// Unlock снимает блокировку owner; истёкшую может снять кто угодно.
+func (r *NoteRepoMemory) Unlock(ctx context.Context, noteID int64, owner string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.state.locks[noteID]
+	if !ok {
+		return nil
+	}
+	if current.Owner != owner && current.ExpiresAt.After(r.clock.Now()) {
+		return core.ErrNoteLocked
+	}
+	delete(r.state.locks, noteID)
+	return nil
+}
+
+// GetLock возвращает действующую блокировку заметки или nil.
+func (r *NoteRepoMemory) GetLock(ctx context.Context, noteID int64) (*core.NoteLock, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.state.locks[noteID]
+	if !ok || !current.ExpiresAt.After(r.clock.Now()) {
+		return nil, nil
+	}
+	return &current, nil
+}
+
+// Move ставит заметку после m.AfterID или перед m.BeforeID, без якоря — в
+// начало. Позиции всех заметок перенумеровываются по порядку.
+func (r *NoteRepoMemory) Move(ctx context.Context, id int64, m core.NoteMove) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.notes[id]; !ok {
+		return core.ErrNotFound
+	}
+	order := make([]int64, 0, len(r.state.notes))
+	for other := range r.state.notes {
+		if other != id {
+			order = append(order, other)
+		}
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a, b := r.state.notes[order[i]], r.state.notes[order[j]]
+		return a.Position < b.Position || a.Position == b.Position && a.ID < b.ID
+	})
+
+	at := 0
+	if anchor := cmpOr(m.AfterID, m.BeforeID); anchor != nil {
+		i := slices.Index(order, *anchor)
+		if i < 0 {
+			return core.ErrInvalidMove
+		}
+		at = i
+		if m.AfterID != nil {
+			at++
+		}
+	}
+	order = slices.Insert(order, at, id)
+
+	for i, other := range order {
+		n := r.state.notes[other]
+		n.Position = float64(i + 1)
+		r.state.notes[other] = n
+	}
+	return nil
+}
+
+// Review выполняет переход согласования по правилам NoteRepoPG.Review.
+func (r *NoteRepoMemory) Review(ctx context.Context, id int64, t core.ReviewTransition, reviewer string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.state.notes[id]
+	if !ok || !r.visible(n) {
+		return core.ErrNotFound
+	}
+	if !slices.Contains(t.From, n.ReviewState) {
+		return core.ErrReviewState
+	}
+	if t.ByReviewer && (n.Reviewer == nil || *n.Reviewer != reviewer) {
+		return core.ErrNotReviewer
+	}
+
+	n.ReviewState = t.To
+	switch {
+	case t.Assign:
+		n.Reviewer, n.ReviewedAt = &reviewer, nil
+	case t.ByReviewer:
+		now := r.clock.Now()
+		n.ReviewedAt = &now
+	default:
+		n.Reviewer, n.ReviewedAt = nil, nil
+	}
+	r.state.notes[id] = n
+	r.logAction(id, t.Action)
+	return nil
+}
+
+/*
+====================
+ADMIN
+====================
+*/
+
+// GetAnyByID возвращает заметку по ID независимо от удаления и срока жизни.
+func (r *NoteRepoMemory) GetAnyByID(ctx context.Context, id int64) (*core.Note, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.state.notes[id]
+	if !ok {
+		return nil, core.ErrNotFound
+	}
+	return r.view(n), nil
+}
+
+// ListAllNotes возвращает заметки с ID меньше beforeID (0 — с начала), от
+// новых к старым, включая удалённые и истёкшие.
+func (r *NoteRepoMemory) ListAllNotes(ctx context.Context, beforeID int64, limit int) ([]core.Note, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var notes []core.Note
+	for _, n := range r.state.notes {
+		if beforeID == 0 || n.ID < beforeID {
+			notes = append(notes, *r.view(n))
+		}
+	}
+	sortByIDDesc(notes)
+	return head(notes, limit), nil
+}
+
+// Restore снимает пометку удаления и прошедший срок жизни.
+func (r *NoteRepoMemory) Restore(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	n, ok := r.state.notes[id]
+	if !ok {
+		return core.ErrNotFound
+	}
+	expired := n.ExpiresAt != nil && !n.ExpiresAt.After(now)
+	if n.DeletedAt == nil && !expired {
+		return nil
+	}
+	n.DeletedAt = nil
+	if expired {
+		n.ExpiresAt = nil
+	}
+	n.Version++
+	n.UpdatedAt = &now
+	r.state.notes[id] = n
+	r.logAction(id, core.ActionRestored)
+	return nil
+}
+
+// SetLegalHold ставит или снимает юридическое удержание заметки.
+func (r *NoteRepoMemory) SetLegalHold(ctx context.Context, id int64, hold bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.state.notes[id]
+	if !ok {
+		return core.ErrNotFound
+	}
+	if (n.LegalHoldAt != nil) == hold {
+		return nil
+	}
+	action := core.ActionHoldReleased
+	n.LegalHoldAt = nil
+	if hold {
+		now := r.clock.Now()
+		n.LegalHoldAt, action = &now, core.ActionHoldPlaced
+	}
+	r.state.notes[id] = n
+	r.logAction(id, action)
+	return nil
+}
+
+// SetAnnouncement делает заметку объявлением с окном a или, при a == nil, снимает его.
+func (r *NoteRepoMemory) SetAnnouncement(ctx context.Context, id int64, a *core.NoteAnnouncement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.state.notes[id]
+	if a == nil {
+		if !ok || n.AnnouncedAt == nil {
+			return nil
+		}
+		n.AnnouncedAt, n.AnnounceFrom, n.AnnounceUntil = nil, nil, nil
+		r.state.notes[id] = n
+		r.logAction(id, core.ActionUnannounced)
+		return nil
+	}
+
+	if !ok || !r.visible(n) {
+		return core.ErrNotFound
+	}
+	if n.AnnouncedAt == nil {
+		now := r.clock.Now()
+		n.AnnouncedAt = &now
+	}
+	n.AnnounceFrom, n.AnnounceUntil = a.From, a.Until
+	r.state.notes[id] = n
+	r.logAction(id, core.ActionAnnounced)
+	return nil
+}
+
+// TableStats возвращает число строк «таблиц» репозитория; размер не считается.
+func (r *NoteRepoMemory) TableStats(ctx context.Context) ([]core.TableStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return []core.TableStats{
+		{Table: "notes", EstimatedRows: int64(len(r.state.notes))},
+		{Table: "notes_log", EstimatedRows: int64(len(r.state.log))},
+		{Table: "note_locks", EstimatedRows: int64(len(r.state.locks))},
+	}, nil
+}
+
+// CountIntegrity возвращает число проблемных записей для проверки check.
+// Записи журнала без заметки здесь не появляются: заметки удаляются только
+// по сроку жизни, с записью expired.
+func (r *NoteRepoMemory) CountIntegrity(ctx context.Context, check string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch check {
+	case core.CheckOrphanLog:
+		return 0, nil
+	case core.CheckExpiredLocks:
+		return int64(len(r.expiredLocks())), nil
+	}
+	return 0, fmt.Errorf("unknown integrity check %q", check)
+}
+
+// RepairIntegrity удаляет до limit проблемных записей проверки check.
+func (r *NoteRepoMemory) RepairIntegrity(ctx context.Context, check string, limit int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch check {
+	case core.CheckOrphanLog:
+		return 0, nil
+	case core.CheckExpiredLocks:
+		ids := head(r.expiredLocks(), limit)
+		for _, id := range ids {
+			delete(r.state.locks, id)
+		}
+		return int64(len(ids)), nil
+	}
+	return 0, fmt.Errorf("unknown integrity check %q", check)
+}
+
+func (r *NoteRepoMemory) expiredLocks() []int64 {
+	now := r.clock.Now()
+	var ids []int64
+	for id, l := range r.state.locks {
+		if !l.ExpiresAt.After(now) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+/*
+====================
+BACKGROUND JOBS
+====================
+*/
+
+// AddViews увеличивает счётчики просмотров.
+func (r *NoteRepoMemory) AddViews(ctx context.Context, views map[int64]int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, count := range views {
+		if n, ok := r.state.notes[id]; ok {
+			n.ViewCount += count
+			n.LastViewedAt = &at
+			r.state.notes[id] = n
+		}
+	}
+	return nil
+}
+
+// PurgeExpired удаляет до limit истёкших заметок (кроме удерживаемых) и пишет
+// для каждой запись expired.
+func (r *NoteRepoMemory) PurgeExpired(ctx context.Context, limit int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	var expired []core.Note
+	for _, n := range r.state.notes {
+		if n.ExpiresAt != nil && !n.ExpiresAt.After(now) && n.LegalHoldAt == nil {
+			expired = append(expired, n)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool { return expired[i].ExpiresAt.Before(*expired[j].ExpiresAt) })
+	expired = head(expired, limit)
+	for _, n := range expired {
+		delete(r.state.notes, n.ID)
+		r.logAction(n.ID, core.ActionExpired)
+	}
+	return int64(len(expired)), nil
+}
+
+/*
+====================
+HELPERS
+====================
+*/
+
+// logAction пишет действие в журнал; вызывается под r.mu.
+func (r *NoteRepoMemory) logAction(noteID int64, action string) {
+	var id int64 = 1
+	if len(r.state.log) > 0 {
+		id = r.state.log[len(r.state.log)-1].ID + 1
+	}
+	r.state.log = append(r.state.log, core.ActivityEntry{
+		ID:        id,
+		NoteID:    noteID,
+		Action:    action,
+		CreatedAt: r.clock.Now(),
+	})
+}
+
+// visible — аналог условия visible: заметка не удалена и не истекла.
+func (r *NoteRepoMemory) visible(n core.Note) bool {
+	return n.DeletedAt == nil && (n.ExpiresAt == nil || n.ExpiresAt.After(r.clock.Now()))
+}
+
+// view — копия заметки для выдачи с вычисленным признаком Announced.
+func (r *NoteRepoMemory) view(n core.Note) *core.Note {
+	now := r.clock.Now()
+	n.Announced = n.AnnouncedAt != nil &&
+		(n.AnnounceFrom == nil || !n.AnnounceFrom.After(now)) &&
+		(n.AnnounceUntil == nil || n.AnnounceUntil.After(now))
+	return &n
+}
+
+// filter возвращает видимые заметки, для которых keep истинно.
+func (r *NoteRepoMemory) filter(keep func(core.Note) bool) []core.Note {
+	notes := []core.Note{}
+	for _, n := range r.state.notes {
+		if r.visible(n) {
+			if v := r.view(n); keep(*v) {
+				notes = append(notes, *v)
+			}
+		}
+	}
+	return notes
+}
+
+func newerFirst(a, b core.Note) bool {
+	return a.CreatedAt.After(b.CreatedAt) || a.CreatedAt.Equal(b.CreatedAt) && a.ID > b.ID
+}
+
+func sortByIDDesc(notes []core.Note) {
+	sort.Slice(notes, func(i, j int) bool { return notes[i].ID > notes[j].ID })
+}
+
+func head[T any](s []T, limit int) []T {
+	if limit > 0 && len(s) > limit {
+		return s[:limit]
+	}
+	return s
+}
+
+func cmpOr(ptrs ...*int64) *int64 {
+	for _, p := range ptrs {
+		if p != nil {
+			return p
+		}
+	}
+	return nil
+}
+
+// metadataContains — аналог jsonb @>: doc содержит все ключи и значения sub.
+func metadataContains(doc json.RawMessage, sub any) bool {
+	var v any
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return false
+	}
+	return jsonContains(v, sub)
+}
+
+func jsonContains(v, sub any) bool {
+	switch s := sub.(type) {
+	case map[string]any:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return false
+		}
+		for k, want := range s {
+			got, ok := m[k]
+			if !ok || !jsonContains(got, want) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		arr, ok := v.([]any)
+		if !ok {
+			return false
+		}
+		for _, want := range s {
+			if !slices.ContainsFunc(arr, func(got any) bool { return jsonContains(got, want) }) {
+				return false
+			}
+		}
+		return true
+	default:
+		return v == sub
+	}
+}
+
+// distance — расстояние по большому кругу между точками в метрах.
+func distance(lat1, lon1, lat2, lon2 float64) float64 {
+	const rad = math.Pi / 180
+	dLat, dLon := (lat2-lat1)*rad, (lon2-lon1)*rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(min(a, 1)))
+}
@@ -0,0 +1,45 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"example.com/notes-api/internal/core"
+)
+
+func TestMemoryTransactions(t *testing.T) {
+	ctx := context.Background()
+	r := NewNoteRepoMemory()
+	errAbort := errors.New("abort")
+
+	err := r.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := r.CreateWithLogTx(ctx, core.NoteCreate{Title: "Откат"}); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithinTx error = %v, want errAbort", err)
+	}
+
+	_ = r.DryRun(ctx, func(ctx context.Context) error {
+		_, err := r.CreateWithLogTx(ctx, core.NoteCreate{Title: "Пробный"})
+		return err
+	})
+
+	if err := r.WithinTx(ctx, func(ctx context.Context) error {
+		_, err := r.CreateWithLogTx(ctx, core.NoteCreate{Title: "Сохранённый"})
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	notes, err := r.List(ctx, core.NoteFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 1 || notes[0].Title != "Сохранённый" {
+		t.Fatalf("notes = %+v, want only the committed one", notes)
+	}
+}
@@ -0,0 +1,104 @@
+package repo
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"example.com/notes-api/internal/clock"
+	"example.com/notes-api/internal/core"
+)
+
+// UserRepoMemory — учётные записи в памяти процесса (STORAGE=memory и тесты).
+type UserRepoMemory struct {
+	clock clock.Clock
+
+	mu     sync.Mutex
+	users  map[int64]core.User
+	nextID int64
+}
+
+// NewUserRepoMemory создаёт пустой репозиторий пользователей в памяти.
+func NewUserRepoMemory(opts ...MemoryOption) *UserRepoMemory {
+	return &UserRepoMemory{clock: newMemoryConfig(opts).clock, users: map[int64]core.User{}}
+}
+
+var _ core.UserRepository = (*UserRepoMemory)(nil)
+
+// CreateUser сохраняет пользователя; email приводится к нижнему регистру.
+func (r *UserRepoMemory) CreateUser(ctx context.Context, u core.User) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u.Email = strings.ToLower(u.Email)
+	if r.emailTaken(u.Email, 0) {
+		return 0, core.ErrEmailTaken
+	}
+	r.nextID++
+	u.ID, u.CreatedAt, u.UpdatedAt = r.nextID, r.clock.Now(), nil
+	r.users[u.ID] = u
+	return u.ID, nil
+}
+
+// GetUserByID возвращает пользователя по ID.
+func (r *UserRepoMemory) GetUserByID(ctx context.Context, id int64) (*core.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[id]
+	if !ok {
+		return nil, core.ErrUserNotFound
+	}
+	return &u, nil
+}
+
+// GetUserByEmail возвращает пользователя по email без учёта регистра.
+func (r *UserRepoMemory) GetUserByEmail(ctx context.Context, email string) (*core.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	email = strings.ToLower(email)
+	for _, u := range r.users {
+		if u.Email == email {
+			return &u, nil
+		}
+	}
+	return nil, core.ErrUserNotFound
+}
+
+// UpdateUser меняет заданные поля пользователя и updated_at.
+func (r *UserRepoMemory) UpdateUser(ctx context.Context, id int64, upd core.UserUpdate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[id]
+	if !ok {
+		return core.ErrUserNotFound
+	}
+	if upd.Email != nil {
+		email := strings.ToLower(*upd.Email)
+		if r.emailTaken(email, id) {
+			return core.ErrEmailTaken
+		}
+		u.Email = email
+	}
+	if upd.Name != nil {
+		u.Name = *upd.Name
+	}
+	if upd.PasswordHash != nil {
+		u.PasswordHash = *upd.PasswordHash
+	}
+	now := r.clock.Now()
+	u.UpdatedAt = &now
+	r.users[id] = u
+	return nil
+}
+
+func (r *UserRepoMemory) emailTaken(email string, excludeID int64) bool {
+	for id, u := range r.users {
+		if id != excludeID && u.Email == email {
+			return true
+		}
+	}
+	return false
+}
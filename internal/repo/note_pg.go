@@ -3,139 +3,400 @@ package repo
 import (
 	"context"
 	"database/sql"
-	"time"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 
+	"example.com/notes-api/internal/clock"
 	"example.com/notes-api/internal/core"
+	"example.com/notes-api/internal/encryption"
+	"example.com/notes-api/internal/lang"
+	"example.com/notes-api/internal/preview"
+	"example.com/notes-api/internal/textnorm"
 )
 
+// noteColumns — список колонок, читаемых scanNote.
+const noteColumns = `id, title, content, slug, version, view_count, last_viewed_at, metadata,
+	color, icon, position, latitude, longitude, expires_at,
+	encrypted, ciphertext, nonce, key_id, content_key_id, content_compressed, lang, content_kind, preview, archived_at, legal_hold_at, deleted_at, created_at, updated_at,
+	announced_at, announce_from, announce_until, review_state, reviewer, reviewed_at, ` + announcementActive
+
+// announcementActive — условие: заметка — объявление, и окно показа идёт сейчас.
+const announcementActive = `(announced_at IS NOT NULL AND (announce_from IS NULL OR announce_from <= now())
+	AND (announce_until IS NULL OR announce_until > now()))`
+
+// notExpired — условие, скрывающее заметки с истёкшим сроком жизни.
+// Заметки на юридическом удержании не истекают.
+const notExpired = `(expires_at IS NULL OR expires_at > now() OR legal_hold_at IS NOT NULL)`
+
+// visible — условие для заметок, доступных через обычный API: не удалены и не истекли.
+const visible = `(deleted_at IS NULL AND ` + notExpired + `)`
+
 // NoteRepoPG — PostgreSQL реализация репозитория заметок.
 type NoteRepoPG struct {
-	db *sql.DB
+	db      *sql.DB
+	keyring *encryption.Keyring
+	clock   clock.Clock
+	tx      *TxManager
+	// compressAbove — content длиннее стольких байт хранится сжатым; 0 — не сжимать.
+	compressAbove int
 }
 
-// NewNoteRepoPG создаёт новый экземпляр репозитория PostgreSQL.
-func NewNoteRepoPG(db *sql.DB) *NoteRepoPG {
-	return &NoteRepoPG{db: db}
+var _ core.NoteRepository = (*NoteRepoPG)(nil)
+
+// Option настраивает NoteRepoPG.
+type Option func(*NoteRepoPG)
+
+// WithKeyring включает шифрование content при хранении.
+func WithKeyring(k *encryption.Keyring) Option {
+	return func(r *NoteRepoPG) {
+		r.keyring = k
+	}
 }
 
-// Create создаёт новую заметку и возвращает её ID.
-func (r *NoteRepoPG) Create(ctx context.Context, n core.NoteCreate) (int64, error) {
-	stmt, err := r.db.PrepareContext(ctx, `
-		INSERT INTO notes (title, content)
-		VALUES ($1, $2)
-		RETURNING id
-	`)
-	if err != nil {
-		return 0, err
+// WithCompression включает сжатие content длиннее threshold байт при записи.
+// Сжатые заметки читаются и без этой опции.
+func WithCompression(threshold int) Option {
+	return func(r *NoteRepoPG) {
+		r.compressAbove = threshold
 	}
-	defer stmt.Close()
+}
 
-	var id int64
-	if err := stmt.QueryRowContext(ctx, n.Title, n.Content).Scan(&id); err != nil {
-		return 0, err
+// WithClock подменяет часы, по которым репозиторий ставит метки времени
+// (updated_at, журнал, сроки блокировок). Условия видимости в SQL по-прежнему
+// считаются от now() базы.
+func WithClock(c clock.Clock) Option {
+	return func(r *NoteRepoPG) {
+		r.clock = c
 	}
-	return id, nil
 }
 
-// CreateWithLogTx демонстрирует транзакцию: создание заметки + лог в одной транзакции.
-func (r *NoteRepoPG) CreateWithLogTx(ctx context.Context, n core.NoteCreate) (int64, error) {
-	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
-		Isolation: sql.LevelReadCommitted,
+// NewNoteRepoPG создаёт новый экземпляр репозитория PostgreSQL.
+func NewNoteRepoPG(db *sql.DB, opts ...Option) *NoteRepoPG {
+	r := &NoteRepoPG{db: db, clock: clock.System, tx: NewTxManager(db)}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Create создаёт новую заметку и возвращает её ID.
+func (r *NoteRepoPG) Create(ctx context.Context, n core.NoteCreate) (int64, error) {
+	return r.insertNote(ctx, r.conn(ctx), n)
+}
+
+// CreateWithLogTx создаёт заметку и запись в notes_log в одной транзакции.
+func (r *NoteRepoPG) CreateWithLogTx(ctx context.Context, n core.NoteCreate) (id int64, err error) {
+	err = r.tx.WithinTx(ctx, func(ctx context.Context) error {
+		id, err = r.insertNote(ctx, r.conn(ctx), n)
+		if err != nil {
+			return err
+		}
+		return r.logAction(ctx, r.conn(ctx), id, core.ActionCreated)
 	})
+	return id, err
+}
+
+// queryer — общий интерфейс *sql.DB и *sql.Tx для чтения.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// insertNote нормализует заголовок и текст (textnorm), вставляет заметку и
+// возвращает её ID.
+func (r *NoteRepoPG) insertNote(ctx context.Context, q queryer, n core.NoteCreate) (int64, error) {
+	n.Title, n.Content = textnorm.Title(n.Title), textnorm.Content(n.Content)
+	content, contentKeyID, compressed, err := r.sealContent(n.Content)
 	if err != nil {
 		return 0, err
 	}
-	defer tx.Rollback() // откат если Commit не вызван
+	var kind, previewText string
+	if !n.Encrypted {
+		kind, previewText = r.card(n.Content)
+	}
 
-	// Вставка заметки
-	var noteID int64
-	err = tx.QueryRowContext(ctx,
-		`INSERT INTO notes (title, content) VALUES ($1, $2) RETURNING id`,
-		n.Title, n.Content,
-	).Scan(&noteID)
+	slug, err := uniqueSlug(ctx, q, n.Title, 0)
 	if err != nil {
 		return 0, err
 	}
 
-	// Вставка лог-действия
-	_, err = tx.ExecContext(ctx,
-		`INSERT INTO notes_log (note_id, action, created_at) VALUES ($1, $2, $3)`,
-		noteID, "created", time.Now(),
-	)
+	var id int64
+	err = q.QueryRowContext(ctx, `
+		INSERT INTO notes (title, content, slug, metadata, color, icon, latitude, longitude, expires_at,
+		                   encrypted, ciphertext, nonce, key_id, content_key_id, content_compressed, lang,
+		                   content_kind, preview, position)
+		VALUES ($1, $2, $3, COALESCE($4::jsonb, '{}'), COALESCE(NULLIF($5, ''), 'default'), $6, $7, $8, $9,
+		        $10, $11, $12, NULLIF($13, ''), $14, $15, $16, $17, $18,
+		        COALESCE((SELECT MIN(position) FROM notes), 1) - 1)
+		RETURNING id
+	`, n.Title, content, slug, jsonParam(n.Metadata), n.Color, n.Icon, n.Latitude, n.Longitude,
+		n.ExpiresAt, n.Encrypted, bytesParam(n.Ciphertext), bytesParam(n.Nonce), n.KeyID, contentKeyID,
+		compressed, detectLang(n.Title, n.Content), kind, previewText).Scan(&id)
 	if err != nil {
-		return 0, err
+		return 0, r.titleTaken(ctx, err, n.Title)
 	}
+	return id, nil
+}
 
-	// Коммит транзакции
-	if err := tx.Commit(); err != nil {
-		return 0, err
+// bytesParam передаёт пустой срез как NULL, а не как пустой bytea.
+func bytesParam(b []byte) any {
+	if len(b) == 0 {
+		return nil
 	}
+	return b
+}
 
-	return noteID, nil
+// jsonParam передаёт JSON в драйвер строкой (nil — NULL).
+func jsonParam(raw json.RawMessage) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
 }
 
-// GetByID возвращает заметку по ID.
+// GetByID возвращает заметку по ID или core.ErrNotFound.
 func (r *NoteRepoPG) GetByID(ctx context.Context, id int64) (*core.Note, error) {
-	stmt, err := r.db.PrepareContext(ctx, `
-		SELECT id, title, content, created_at, updated_at
+	stmt, err := r.conn(ctx).PrepareContext(ctx, `
+		SELECT `+noteColumns+`
 		FROM notes
-		WHERE id = $1
+		WHERE id = $1 AND `+visible+`
 	`)
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
 
-	var note core.Note
-	if err := stmt.QueryRowContext(ctx, id).Scan(
-		&note.ID,
-		&note.Title,
-		&note.Content,
-		&note.CreatedAt,
-		&note.UpdatedAt,
-	); err != nil {
-		return nil, err
-	}
-	return &note, nil
+	n, err := r.scanNote(stmt.QueryRowContext(ctx, id))
+	return n, notFound(err)
 }
 
-// Update обновляет заметку по ID.
+// Update обновляет заметку по ID, увеличивает её версию и пишет запись в notes_log.
+// Новые заголовок и текст нормализуются, как при создании.
+// Если задан u.BaseVersion и версия в БД уже другая, возвращает core.ErrVersionConflict;
+// если заметки нет — core.ErrNotFound.
 func (r *NoteRepoPG) Update(ctx context.Context, id int64, u core.NoteUpdate) error {
-	stmt, err := r.db.PrepareContext(ctx, `
+	tx, err := r.begin(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if u.Title != nil {
+		title := textnorm.Title(*u.Title)
+		u.Title = &title
+	}
+	if u.Content != nil {
+		text := textnorm.Content(*u.Content)
+		u.Content = &text
+	}
+
+	// Slug стабилен при смене заголовка и пересчитывается только по запросу.
+	var newSlug *string
+	if u.RegenerateSlug {
+		title := u.Title
+		if title == nil {
+			title = new(string)
+			err := tx.QueryRowContext(ctx, `SELECT title FROM notes WHERE id = $1`, id).Scan(title)
+			if errors.Is(err, sql.ErrNoRows) {
+				return core.ErrNotFound
+			}
+			if err != nil {
+				return err
+			}
+		}
+		s, err := uniqueSlug(ctx, tx, *title, id)
+		if err != nil {
+			return err
+		}
+		newSlug = &s
+	}
+
+	var (
+		content      *string
+		contentKeyID *string
+		compressed   bool
+		kind         string
+		previewText  string
+	)
+	if u.Content != nil {
+		sealed, keyID, packed, err := r.sealContent(*u.Content)
+		if err != nil {
+			return err
+		}
+		content, contentKeyID, compressed = &sealed, keyID, packed
+		kind, previewText = r.card(*u.Content)
+	}
+
+	var noteLang *string
+	if u.Title != nil || u.Content != nil {
+		title, text, err := r.currentText(ctx, tx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return core.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if u.Title != nil {
+			title = *u.Title
+		}
+		if u.Content != nil {
+			text = *u.Content
+		}
+		code := detectLang(title, text)
+		noteLang = &code
+	}
+
+	res, err := tx.ExecContext(ctx, `
 		UPDATE notes
 		SET title = COALESCE($1, title),
 		    content = COALESCE($2, content),
-		    updated_at = $3
-		WHERE id = $4
-	`)
+		    content_key_id = CASE WHEN $2::text IS NULL THEN content_key_id ELSE $18 END,
+		    content_compressed = CASE WHEN $2::text IS NULL THEN content_compressed ELSE $21 END,
+		    content_kind = CASE WHEN $2::text IS NULL THEN content_kind ELSE $22 END,
+		    preview = CASE WHEN $2::text IS NULL THEN preview ELSE $23 END,
+		    metadata = COALESCE($3::jsonb, metadata),
+		    color = COALESCE($4, color),
+		    icon = COALESCE($5, icon),
+		    latitude = CASE WHEN $8 THEN NULL ELSE COALESCE($6, latitude) END,
+		    longitude = CASE WHEN $8 THEN NULL ELSE COALESCE($7, longitude) END,
+		    slug = COALESCE($9, slug),
+		    expires_at = CASE WHEN $14 THEN NULL ELSE COALESCE($13, expires_at) END,
+		    ciphertext = COALESCE($15, ciphertext),
+		    nonce = COALESCE($16, nonce),
+		    key_id = COALESCE($17, key_id),
+		    archived_at = CASE
+		        WHEN $19::boolean IS NULL THEN archived_at
+		        WHEN $19 THEN COALESCE(archived_at, $10)
+		    END,
+		    lang = COALESCE($20, lang),
+		    version = version + 1,
+		    updated_at = $10
+		WHERE id = $11 AND `+visible+`
+		  AND ($12::bigint IS NULL OR version = $12)
+	`, u.Title, content, jsonParam(u.Metadata), u.Color, u.Icon,
+		u.Latitude, u.Longitude, u.ClearLocation, newSlug,
+		r.clock.Now(), id, u.BaseVersion, u.ExpiresAt, u.ClearExpiry,
+		bytesParam(u.Ciphertext), bytesParam(u.Nonce), u.KeyID, contentKeyID, u.Archived, noteLang, compressed,
+		kind, previewText)
 	if err != nil {
+		if u.Title != nil {
+			return r.titleTaken(ctx, err, *u.Title)
+		}
 		return err
 	}
-	defer stmt.Close()
 
-	_, err = stmt.ExecContext(ctx, u.Title, u.Content, time.Now(), id)
-	return err
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		if u.BaseVersion == nil {
+			return core.ErrNotFound
+		}
+		var exists bool
+		err := tx.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM notes WHERE id = $1 AND `+visible+`)`, id,
+		).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return core.ErrNotFound
+		}
+		return core.ErrVersionConflict
+	}
+
+	if err := r.logAction(ctx, tx, id, core.ActionUpdated); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// detectLang определяет язык заметки по заголовку и content.
+func detectLang(title, content string) string {
+	return lang.Detect(title + "\n" + content)
+}
+
+// card возвращает вид содержимого и превью для записи в БД. Если content
+// шифруется в БД, превью открытым не хранится (пустое) и считается при чтении.
+func (r *NoteRepoPG) card(content string) (kind, text string) {
+	kind = preview.Kind(content)
+	if r.keyring != nil {
+		return kind, ""
+	}
+	return kind, preview.Text(content)
+}
+
+// currentText возвращает заголовок и исходный content заметки.
+func (r *NoteRepoPG) currentText(ctx context.Context, q queryer, id int64) (title, content string, err error) {
+	var (
+		contentKeyID *string
+		compressed   bool
+	)
+	err = q.QueryRowContext(ctx,
+		`SELECT title, content, content_key_id, content_compressed FROM notes WHERE id = $1`, id,
+	).Scan(&title, &content, &contentKeyID, &compressed)
+	if err != nil {
+		return "", "", err
+	}
+	content, err = r.openContent(content, contentKeyID, compressed)
+	return title, content, err
 }
 
-// Delete удаляет заметку по ID.
+// Delete помечает заметку удалённой и пишет запись в notes_log. Строка остаётся
+// в БД до правила хранения purge_deleted и может быть восстановлена через Restore.
+// Заметку на юридическом удержании удалить нельзя: возвращает core.ErrLegalHold;
+// уже удалённую или несуществующую — core.ErrNotFound.
 func (r *NoteRepoPG) Delete(ctx context.Context, id int64) error {
-	stmt, err := r.db.PrepareContext(ctx, `
-		DELETE FROM notes WHERE id = $1
-	`)
+	tx, err := r.begin(ctx, nil)
 	if err != nil {
 		return err
 	}
-	defer stmt.Close()
+	defer tx.Rollback()
 
-	_, err = stmt.ExecContext(ctx, id)
-	return err
+	res, err := tx.ExecContext(ctx, `
+		UPDATE notes SET deleted_at = $2
+		WHERE id = $1 AND deleted_at IS NULL AND legal_hold_at IS NULL
+	`, id, r.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		var held bool
+		err := tx.QueryRowContext(ctx,
+			`SELECT legal_hold_at IS NOT NULL FROM notes WHERE id = $1 AND deleted_at IS NULL`, id,
+		).Scan(&held)
+		if errors.Is(err, sql.ErrNoRows) {
+			return core.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if held {
+			return core.ErrLegalHold
+		}
+		return nil
+	}
+
+	if err := r.logAction(ctx, tx, id, core.ActionDeleted); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 // ListFirstPage возвращает первые N заметок, отсортированных по дате создания.
 func (r *NoteRepoPG) ListFirstPage(ctx context.Context, limit int) ([]core.Note, error) {
-	stmt, err := r.db.PrepareContext(ctx, `
-		SELECT id, title, content, created_at, updated_at
+	stmt, err := r.conn(ctx).PrepareContext(ctx, `
+		SELECT `+noteColumns+`
 		FROM notes
+		WHERE `+visible+`
 		ORDER BY created_at DESC, id DESC
 		LIMIT $1
 	`)
@@ -150,23 +411,15 @@ func (r *NoteRepoPG) ListFirstPage(ctx context.Context, limit int) ([]core.Note,
 	}
 	defer rows.Close()
 
-	var notes []core.Note
-	for rows.Next() {
-		var n core.Note
-		if err := rows.Scan(&n.ID, &n.Title, &n.Content, &n.CreatedAt, &n.UpdatedAt); err != nil {
-			return nil, err
-		}
-		notes = append(notes, n)
-	}
-	return notes, nil
+	return r.scanNotes(rows)
 }
 
 // ListAfterCursor возвращает заметки после указанного курсора (keyset-пагинация).
 func (r *NoteRepoPG) ListAfterCursor(ctx context.Context, cursor core.NoteCursor, limit int) ([]core.Note, error) {
-	stmt, err := r.db.PrepareContext(ctx, `
-		SELECT id, title, content, created_at, updated_at
+	stmt, err := r.conn(ctx).PrepareContext(ctx, `
+		SELECT `+noteColumns+`
 		FROM notes
-		WHERE (created_at, id) < ($1, $2)
+		WHERE (created_at, id) < ($1, $2) AND `+visible+`
 		ORDER BY created_at DESC, id DESC
 		LIMIT $3
 	`)
@@ -181,15 +434,7 @@ func (r *NoteRepoPG) ListAfterCursor(ctx context.Context, cursor core.NoteCursor
 	}
 	defer rows.Close()
 
-	var notes []core.Note
-	for rows.Next() {
-		var n core.Note
-		if err := rows.Scan(&n.ID, &n.Title, &n.Content, &n.CreatedAt, &n.UpdatedAt); err != nil {
-			return nil, err
-		}
-		notes = append(notes, n)
-	}
-	return notes, nil
+	return r.scanNotes(rows)
 }
 
 // GetByIDs возвращает короткую информацию по массиву ID заметок (батчинг).
@@ -198,10 +443,10 @@ func (r *NoteRepoPG) GetByIDs(ctx context.Context, ids []int64) ([]core.NoteShor
 		return []core.NoteShort{}, nil
 	}
 
-	stmt, err := r.db.PrepareContext(ctx, `
+	stmt, err := r.conn(ctx).PrepareContext(ctx, `
 		SELECT id, title
 		FROM notes
-		WHERE id = ANY($1)
+		WHERE id = ANY($1) AND `+visible+`
 	`)
 	if err != nil {
 		return nil, err
@@ -227,23 +472,140 @@ func (r *NoteRepoPG) GetByIDs(ctx context.Context, ids []int64) ([]core.NoteShor
 
 // GetAll возвращает все заметки, отсортированные по дате создания.
 func (r *NoteRepoPG) GetAll(ctx context.Context) ([]core.Note, error) {
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, title, content, created_at, updated_at
-		FROM notes
-		ORDER BY created_at DESC, id DESC
-	`)
+	return r.List(ctx, core.NoteFilter{})
+}
+
+// List возвращает заметки, подходящие под фильтр, отсортированные по дате
+// создания; действующие объявления идут первыми.
+func (r *NoteRepoPG) List(ctx context.Context, f core.NoteFilter) ([]core.Note, error) {
+	var (
+		conds = []string{visible}
+		args  []any
+	)
+	if f.Archived {
+		conds = append(conds, "archived_at IS NOT NULL")
+	} else {
+		conds = append(conds, "archived_at IS NULL")
+	}
+	if len(f.Metadata) > 0 {
+		args = append(args, string(f.Metadata))
+		conds = append(conds, fmt.Sprintf("metadata @> $%d::jsonb", len(args)))
+	}
+	if f.Color != "" {
+		args = append(args, f.Color)
+		conds = append(conds, fmt.Sprintf("color = $%d", len(args)))
+	}
+	if f.Lang != "" {
+		args = append(args, f.Lang)
+		conds = append(conds, fmt.Sprintf("lang = $%d", len(args)))
+	}
+	if f.ReviewState != "" {
+		args = append(args, f.ReviewState)
+		conds = append(conds, fmt.Sprintf("review_state = $%d", len(args)))
+	}
+
+	// Действующие объявления — над остальными заметками; в архиве их нет
+	query := `SELECT ` + noteColumns + ` FROM notes WHERE ` + strings.Join(conds, " AND ") + ` ORDER BY `
+	if !f.Archived {
+		query += announcementActive + ` DESC, `
+	}
+	if f.Sort == core.SortManual {
+		query += `position, id`
+	} else {
+		query += `created_at DESC, id DESC`
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	return r.scanNotes(rows)
+}
+
+// rowScanner — общий интерфейс *sql.Row и *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanNote читает заметку в порядке noteColumns.
+func (r *NoteRepoPG) scanNote(row rowScanner) (*core.Note, error) {
+	var (
+		n            core.Note
+		metadata     []byte
+		contentKeyID *string
+		compressed   bool
+	)
+	if err := row.Scan(
+		&n.ID,
+		&n.Title,
+		&n.Content,
+		&n.Slug,
+		&n.Version,
+		&n.ViewCount,
+		&n.LastViewedAt,
+		&metadata,
+		&n.Color,
+		&n.Icon,
+		&n.Position,
+		&n.Latitude,
+		&n.Longitude,
+		&n.ExpiresAt,
+		&n.Encrypted,
+		&n.Ciphertext,
+		&n.Nonce,
+		&n.KeyID,
+		&contentKeyID,
+		&compressed,
+		&n.Lang,
+		&n.ContentKind,
+		&n.Preview,
+		&n.ArchivedAt,
+		&n.LegalHoldAt,
+		&n.DeletedAt,
+		&n.CreatedAt,
+		&n.UpdatedAt,
+		&n.AnnouncedAt,
+		&n.AnnounceFrom,
+		&n.AnnounceUntil,
+		&n.ReviewState,
+		&n.Reviewer,
+		&n.ReviewedAt,
+		&n.Announced,
+	); err != nil {
+		return nil, err
+	}
+	n.Metadata = metadata
+
+	content, err := r.openContent(n.Content, contentKeyID, compressed)
+	if err != nil {
+		return nil, err
+	}
+	n.Content = content
+	return &n, nil
+}
+
+// notFound переводит sql.ErrNoRows в core.ErrNotFound, остальные ошибки не меняет.
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.ErrNotFound
+	}
+	return err
+}
+
+// scanNotes читает все строки выборки заметок.
+func (r *NoteRepoPG) scanNotes(rows *sql.Rows) ([]core.Note, error) {
 	var notes []core.Note
 	for rows.Next() {
-		var n core.Note
-		if err := rows.Scan(&n.ID, &n.Title, &n.Content, &n.CreatedAt, &n.UpdatedAt); err != nil {
+		n, err := r.scanNote(rows)
+		if err != nil {
 			return nil, err
 		}
-		notes = append(notes, n)
+		notes = append(notes, *n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 	return notes, nil
 }
@@ -0,0 +1,44 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"example.com/notes-api/internal/core"
+)
+
+// ListRecentlyUpdated возвращает неархивные заметки, изменённые последними;
+// ни разу не изменённые сортируются по дате создания.
+func (r *NoteRepoPG) ListRecentlyUpdated(ctx context.Context, limit int) ([]core.Note, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT `+noteColumns+`
+		FROM notes
+		WHERE `+visible+` AND archived_at IS NULL
+		ORDER BY COALESCE(updated_at, created_at) DESC, id DESC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanNotes(rows)
+}
+
+// ListExpiringBefore возвращает видимые заметки, срок жизни которых истекает
+// до until, ближайшие первыми.
+func (r *NoteRepoPG) ListExpiringBefore(ctx context.Context, until time.Time, limit int) ([]core.Note, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT `+noteColumns+`
+		FROM notes
+		WHERE expires_at <= $1 AND `+visible+`
+		ORDER BY expires_at, id
+		LIMIT $2
+	`, until, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanNotes(rows)
+}
@@ -0,0 +1,51 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// EnsureLogPartitions создаёт месячные секции notes_log на months месяцев вперёд.
+// Если notes_log не секционирована (см. migrations/optional), ничего не делает.
+// Возвращает число созданных секций.
+func (r *NoteRepoPG) EnsureLogPartitions(ctx context.Context, months int) (int, error) {
+	var partitioned bool
+	err := r.conn(ctx).QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM pg_partitioned_table
+			WHERE partrelid = to_regclass('notes_log')
+		)
+	`).Scan(&partitioned)
+	if err != nil || !partitioned {
+		return 0, err
+	}
+
+	now := r.clock.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	created := 0
+	for i := 0; i <= months; i++ {
+		from := start.AddDate(0, i, 0)
+		to := from.AddDate(0, 1, 0)
+		name := fmt.Sprintf("notes_log_%04d_%02d", from.Year(), from.Month())
+
+		var exists bool
+		if err := r.conn(ctx).QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, name).Scan(&exists); err != nil {
+			return created, err
+		}
+		if exists {
+			continue
+		}
+
+		// Имя и границы формируются здесь же из дат, поэтому подстановка безопасна
+		_, err := r.conn(ctx).ExecContext(ctx, fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s PARTITION OF notes_log FOR VALUES FROM ('%s') TO ('%s')`,
+			name, from.Format(time.RFC3339), to.Format(time.RFC3339)))
+		if err != nil {
+			return created, fmt.Errorf("%s: %w", name, err)
+		}
+		created++
+	}
+	return created, nil
+}
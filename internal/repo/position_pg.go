@@ -0,0 +1,129 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+
+	"example.com/notes-api/internal/core"
+)
+
+// moveLockKey — ключ advisory-блокировки, сериализующей перестановки.
+const moveLockKey = 663
+
+// minPositionGap — при меньшем зазоре между соседями позиции перенумеровываются.
+const minPositionGap = 1e-9
+
+// Move ставит заметку сразу после m.AfterID или сразу перед m.BeforeID.
+// Если ни один якорь не задан, заметка поднимается в начало списка.
+func (r *NoteRepoPG) Move(ctx context.Context, id int64, m core.NoteMove) error {
+	tx, err := r.begin(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, moveLockKey); err != nil {
+		return err
+	}
+
+	pos, err := neighbourPosition(ctx, tx, id, m)
+	if err == errNoGap {
+		if err := rebalancePositions(ctx, tx); err != nil {
+			return err
+		}
+		pos, err = neighbourPosition(ctx, tx, id, m)
+	}
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `UPDATE notes SET position = $1 WHERE id = $2`, pos, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return core.ErrNotFound
+	}
+
+	return tx.Commit()
+}
+
+var errNoGap = errors.New("no gap between positions")
+
+// neighbourPosition вычисляет позицию между якорем и его соседом.
+func neighbourPosition(ctx context.Context, tx dbConn, id int64, m core.NoteMove) (float64, error) {
+	var anchorID int64
+	switch {
+	case m.AfterID != nil:
+		anchorID = *m.AfterID
+	case m.BeforeID != nil:
+		anchorID = *m.BeforeID
+	default:
+		var top sql.NullFloat64
+		err := tx.QueryRowContext(ctx,
+			`SELECT MIN(position) FROM notes WHERE id <> $1`, id,
+		).Scan(&top)
+		if err != nil {
+			return 0, err
+		}
+		return top.Float64 - 1, nil
+	}
+
+	if anchorID == id {
+		return 0, core.ErrInvalidMove
+	}
+
+	var anchor float64
+	err := tx.QueryRowContext(ctx, `SELECT position FROM notes WHERE id = $1`, anchorID).Scan(&anchor)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, core.ErrInvalidMove
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		neighbour sql.NullFloat64
+		query     string
+	)
+	if m.AfterID != nil {
+		query = `SELECT MIN(position) FROM notes WHERE id <> $1 AND position > $2`
+	} else {
+		query = `SELECT MAX(position) FROM notes WHERE id <> $1 AND position < $2`
+	}
+	if err := tx.QueryRowContext(ctx, query, id, anchor).Scan(&neighbour); err != nil {
+		return 0, err
+	}
+
+	if !neighbour.Valid {
+		if m.AfterID != nil {
+			return anchor + 1, nil
+		}
+		return anchor - 1, nil
+	}
+
+	if math.Abs(neighbour.Float64-anchor) < minPositionGap {
+		return 0, errNoGap
+	}
+	return (anchor + neighbour.Float64) / 2, nil
+}
+
+// rebalancePositions перенумеровывает все заметки целыми числами в текущем порядке.
+func rebalancePositions(ctx context.Context, tx dbConn) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE notes n
+		SET position = o.rn
+		FROM (
+			SELECT id, row_number() OVER (ORDER BY position, id) AS rn
+			FROM notes
+		) o
+		WHERE n.id = o.id
+	`)
+	return err
+}
@@ -0,0 +1,31 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+)
+
+// SearchIndexes — индексы, по которым работает поиск заметок.
+// Колонок tsvector в схеме нет: поиск идёт по индексам-выражениям
+// (конфигурация — по колонке lang), поэтому перестраиваются только они.
+var SearchIndexes = []string{
+	"idx_notes_title_fts",
+	"idx_notes_metadata",
+}
+
+// ReindexSearch перестраивает поисковый индекс без блокировки записи
+// (REINDEX CONCURRENTLY, вне транзакции). Имя должно быть из SearchIndexes.
+func (r *NoteRepoPG) ReindexSearch(ctx context.Context, index string) error {
+	known := false
+	for _, name := range SearchIndexes {
+		if name == index {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("unknown search index %q", index)
+	}
+	_, err := r.db.ExecContext(ctx, `REINDEX INDEX CONCURRENTLY `+index)
+	return err
+}
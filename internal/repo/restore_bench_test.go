@@ -0,0 +1,41 @@
+package repo
+
+import (
+	"context"
+	"testing"
+)
+
+// BenchmarkRestore сравнивает построчный RestoreNote с COPY в RestoreBatch
+// на загрузке benchNotes заметок.
+func BenchmarkRestore(b *testing.B) {
+	r, cleanup := benchRepo(b)
+	ctx := context.Background()
+	notes := benchBackupNotes(benchNotes)
+
+	b.Run("row", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, n := range notes {
+				if _, err := r.RestoreNote(ctx, n); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+			cleanup()
+			b.StartTimer()
+		}
+	})
+
+	b.Run("copy", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for start := 0; start < len(notes); start += 1000 {
+				end := min(start+1000, len(notes))
+				if _, err := r.RestoreBatch(ctx, notes[start:end]); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+			cleanup()
+			b.StartTimer()
+		}
+	})
+}
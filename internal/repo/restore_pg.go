@@ -0,0 +1,152 @@
+package repo
+
+import (
+	"context"
+	"strings"
+
+	"example.com/notes-api/internal/core"
+	"github.com/lib/pq"
+)
+
+// restoreColumns — колонки, переносимые из резервной копии.
+var restoreColumns = []string{
+	"id", "title", "content", "content_key_id", "content_compressed", "slug", "version", "view_count", "last_viewed_at",
+	"metadata", "color", "icon", "position", "latitude", "longitude", "expires_at",
+	"encrypted", "ciphertext", "nonce", "key_id", "lang",
+	"archived_at", "legal_hold_at", "deleted_at", "created_at", "updated_at",
+}
+
+// WipeNotes удаляет все заметки (блокировки удаляются каскадом). notes_log не трогается.
+func (r *NoteRepoPG) WipeNotes(ctx context.Context) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM notes`)
+	return err
+}
+
+// RestoreNote вставляет заметку из резервной копии с исходными ID, slug и датами.
+// Content шифруется текущим ключом. Если заметка с таким ID или slug уже есть,
+// ничего не меняет и возвращает false.
+func (r *NoteRepoPG) RestoreNote(ctx context.Context, n core.Note) (bool, error) {
+	content, contentKeyID, compressed, err := r.sealContent(n.Content)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, `
+		INSERT INTO notes (id, title, content, content_key_id, content_compressed, slug, version, view_count, last_viewed_at,
+		                   metadata, color, icon, position, latitude, longitude, expires_at,
+		                   encrypted, ciphertext, nonce, key_id, lang,
+		                   archived_at, legal_hold_at, deleted_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9,
+		        COALESCE($10::jsonb, '{}'), COALESCE(NULLIF($11, ''), 'default'), $12, $13, $14, $15, $16,
+		        $17, $18, $19, $20, $21,
+		        $22, $23, $24, $25, $26)
+		ON CONFLICT DO NOTHING
+	`, n.ID, n.Title, content, contentKeyID, compressed, n.Slug, n.Version, n.ViewCount, n.LastViewedAt,
+		jsonParam(n.Metadata), n.Color, n.Icon, n.Position, n.Latitude, n.Longitude, n.ExpiresAt,
+		n.Encrypted, bytesParam(n.Ciphertext), bytesParam(n.Nonce), n.KeyID, restoredLang(n),
+		n.ArchivedAt, n.LegalHoldAt, n.DeletedAt, n.CreatedAt, n.UpdatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// restoredLang — язык из копии; в копиях до появления колонки lang его нет,
+// и язык определяется заново.
+func restoredLang(n core.Note) string {
+	if n.Lang != "" {
+		return n.Lang
+	}
+	return detectLang(n.Title, n.Content)
+}
+
+// RestoreBatch вставляет пачку заметок из резервной копии через COPY во временную
+// таблицу — на больших копиях это намного быстрее построчных RestoreNote.
+// Заметки с занятыми ID или slug пропускаются; возвращает ID вставленных.
+// Всегда идёт в собственной транзакции: временная таблица живёт до её фиксации.
+func (r *NoteRepoPG) RestoreBatch(ctx context.Context, notes []core.Note) ([]int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`CREATE TEMP TABLE notes_restore (LIKE notes INCLUDING DEFAULTS) ON COMMIT DROP`); err != nil {
+		return nil, err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("notes_restore", restoreColumns...))
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range notes {
+		content, contentKeyID, compressed, err := r.sealContent(n.Content)
+		if err != nil {
+			stmt.Close()
+			return nil, err
+		}
+		metadata := "{}"
+		if len(n.Metadata) > 0 {
+			metadata = string(n.Metadata)
+		}
+		color := n.Color
+		if color == "" {
+			color = core.DefaultColor
+		}
+
+		if _, err := stmt.ExecContext(ctx,
+			n.ID, n.Title, content, contentKeyID, compressed, n.Slug, n.Version, n.ViewCount, n.LastViewedAt,
+			metadata, color, n.Icon, n.Position, n.Latitude, n.Longitude, n.ExpiresAt,
+			n.Encrypted, bytesParam(n.Ciphertext), bytesParam(n.Nonce), n.KeyID, restoredLang(n),
+			n.ArchivedAt, n.LegalHoldAt, n.DeletedAt, n.CreatedAt, n.UpdatedAt,
+		); err != nil {
+			stmt.Close()
+			return nil, err
+		}
+	}
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return nil, err
+	}
+	if err := stmt.Close(); err != nil {
+		return nil, err
+	}
+
+	cols := strings.Join(restoreColumns, ", ")
+	rows, err := tx.QueryContext(ctx, `
+		INSERT INTO notes (`+cols+`)
+		SELECT `+cols+` FROM notes_restore
+		ON CONFLICT DO NOTHING
+		RETURNING id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, tx.Commit()
+}
+
+// ResetNoteSequence сдвигает последовательность ID за максимальный ID после вставок с явными ID.
+func (r *NoteRepoPG) ResetNoteSequence(ctx context.Context) error {
+	_, err := r.conn(ctx).ExecContext(ctx,
+		`SELECT setval(pg_get_serial_sequence('notes', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM notes`)
+	return err
+}
@@ -0,0 +1,135 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"example.com/notes-api/internal/core"
+)
+
+// retentionTargets — выборка ID строк, подпадающих под правило (параметр $1 — граница,
+// см. retentionBound).
+// Удерживаемые заметки и их история не затрагиваются.
+var retentionTargets = map[string]string{
+	core.RetentionArchiveNotes: `
+		SELECT id FROM notes
+		WHERE archived_at IS NULL
+		  AND legal_hold_at IS NULL
+		  AND COALESCE(updated_at, created_at) < $1
+		  AND ` + visible,
+	core.RetentionPurgeLog: `
+		SELECT id FROM notes_log
+		WHERE created_at < $1
+		  AND note_id NOT IN (SELECT id FROM notes WHERE legal_hold_at IS NOT NULL)`,
+	core.RetentionTrimLog: `
+		SELECT id FROM notes_log
+		WHERE id <= (SELECT id FROM notes_log ORDER BY id DESC OFFSET $1 LIMIT 1)
+		  AND note_id NOT IN (SELECT id FROM notes WHERE legal_hold_at IS NOT NULL)`,
+	core.RetentionPurgeDeleted: `
+		SELECT id FROM notes
+		WHERE deleted_at < $1
+		  AND legal_hold_at IS NULL`,
+}
+
+// ApplyRetention применяет правило к не более чем limit строкам и возвращает их число.
+func (r *NoteRepoPG) ApplyRetention(ctx context.Context, rule core.RetentionRule, cutoff time.Time, limit int) (int64, error) {
+	var (
+		query string
+		args  = []any{retentionBound(rule, cutoff), limit}
+	)
+	switch rule.Kind {
+	case core.RetentionArchiveNotes:
+		query = `
+			WITH archived AS (
+				UPDATE notes SET archived_at = $3
+				WHERE id IN (` + retentionTargets[rule.Kind] + ` ORDER BY id LIMIT $2)
+				RETURNING id
+			)
+			INSERT INTO notes_log (note_id, action, created_at)
+			SELECT id, $4, $3 FROM archived`
+		args = append(args, r.clock.Now(), core.ActionArchived)
+	case core.RetentionPurgeLog, core.RetentionTrimLog:
+		query = `
+			DELETE FROM notes_log
+			WHERE id IN (` + retentionTargets[rule.Kind] + ` ORDER BY id LIMIT $2)`
+	case core.RetentionPurgeDeleted:
+		query = `
+			DELETE FROM notes
+			WHERE id IN (` + retentionTargets[rule.Kind] + ` ORDER BY id LIMIT $2)`
+	default:
+		return 0, fmt.Errorf("unknown retention rule %q", rule.Kind)
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// PreviewRetention считает строки, которые затронет правило, и возвращает часть их ID.
+func (r *NoteRepoPG) PreviewRetention(ctx context.Context, rule core.RetentionRule, cutoff time.Time, sample int) (int64, []int64, error) {
+	target, ok := retentionTargets[rule.Kind]
+	if !ok {
+		return 0, nil, fmt.Errorf("unknown retention rule %q", rule.Kind)
+	}
+
+	bound := retentionBound(rule, cutoff)
+
+	var count int64
+	if err := r.conn(ctx).QueryRowContext(ctx, `SELECT count(*) FROM (`+target+`) t`, bound).Scan(&count); err != nil {
+		return 0, nil, err
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, target+` ORDER BY id LIMIT $2`, bound, sample)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return 0, nil, err
+		}
+		ids = append(ids, id)
+	}
+	return count, ids, rows.Err()
+}
+
+// retentionBound — значение $1 для выборки правила: число оставляемых строк
+// для trim_log, граница по времени для остальных.
+func retentionBound(rule core.RetentionRule, cutoff time.Time) any {
+	if rule.ByAge() {
+		return cutoff
+	}
+	return rule.MaxRows
+}
+
+// TableStats возвращает оценку числа строк и размер на диске для таблиц notes и notes_log.
+// Оценка берётся из статистики планировщика и не требует полного прохода по таблице.
+func (r *NoteRepoPG) TableStats(ctx context.Context) ([]core.TableStats, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT relname, GREATEST(reltuples, 0)::bigint, pg_total_relation_size(oid)
+		FROM pg_class
+		WHERE relkind IN ('r', 'p') AND relname IN ('notes', 'notes_log')
+		  AND relnamespace = 'public'::regnamespace
+		ORDER BY relname
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []core.TableStats{}
+	for rows.Next() {
+		var s core.TableStats
+		if err := rows.Scan(&s.Table, &s.EstimatedRows, &s.SizeBytes); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
@@ -0,0 +1,69 @@
+package repo
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"example.com/notes-api/internal/core"
+)
+
+// Review выполняет переход согласования t от имени reviewer и пишет его в
+// notes_log. Переход с назначением (t.Assign) делает reviewer рецензентом;
+// решения (t.ByReviewer) принимает только назначенный рецензент, иначе
+// core.ErrNotReviewer. Недопустимый из текущего состояния переход —
+// core.ErrReviewState, нет заметки — core.ErrNotFound.
+func (r *NoteRepoPG) Review(ctx context.Context, id int64, t core.ReviewTransition, reviewer string) error {
+	tx, err := r.begin(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var (
+		state    string
+		assigned *string
+	)
+	err = tx.QueryRowContext(ctx, `
+		SELECT review_state, reviewer
+		FROM notes
+		WHERE id = $1 AND `+visible+`
+		FOR UPDATE
+	`, id).Scan(&state, &assigned)
+	if err != nil {
+		return notFound(err)
+	}
+	if !slices.Contains(t.From, state) {
+		return core.ErrReviewState
+	}
+	if t.ByReviewer && (assigned == nil || *assigned != reviewer) {
+		return core.ErrNotReviewer
+	}
+
+	// Назначение ставит рецензента, решение сохраняет его и ставит
+	// reviewed_at; возврат в черновик вне решения начинает процесс заново
+	var (
+		next       *string
+		reviewedAt *time.Time
+	)
+	switch {
+	case t.Assign:
+		next = &reviewer
+	case t.ByReviewer:
+		next = assigned
+		now := r.clock.Now()
+		reviewedAt = &now
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE notes
+		SET review_state = $2, reviewer = $3, reviewed_at = $4
+		WHERE id = $1
+	`, id, t.To, next, reviewedAt); err != nil {
+		return err
+	}
+	if err := r.logAction(ctx, tx, id, t.Action); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
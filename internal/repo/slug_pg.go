@@ -0,0 +1,56 @@
+package repo
+
+import (
+	"context"
+	"strconv"
+
+	"example.com/notes-api/internal/core"
+	"example.com/notes-api/internal/slug"
+)
+
+// GetBySlug возвращает заметку по slug или core.ErrNotFound.
+func (r *NoteRepoPG) GetBySlug(ctx context.Context, s string) (*core.Note, error) {
+	n, err := r.scanNote(r.conn(ctx).QueryRowContext(ctx, `
+		SELECT `+noteColumns+`
+		FROM notes
+		WHERE slug = $1 AND `+visible+`
+	`, s))
+	return n, notFound(err)
+}
+
+// uniqueSlug строит slug из заголовка и добавляет суффикс -2, -3, …,
+// если такой уже занят другой заметкой (excludeID — сама заметка, 0 — новая).
+func uniqueSlug(ctx context.Context, q queryer, title string, excludeID int64) (string, error) {
+	base := slug.Make(title)
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT slug FROM notes
+		WHERE (slug = $1 OR slug LIKE $1 || '-%') AND id <> $2
+	`, base, excludeID)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	taken := map[string]bool{}
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return "", err
+		}
+		taken[s] = true
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	if !taken[base] {
+		return base, nil
+	}
+	for i := 2; ; i++ {
+		candidate := base + "-" + strconv.Itoa(i)
+		if !taken[candidate] {
+			return candidate, nil
+		}
+	}
+}
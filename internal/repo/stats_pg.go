@@ -0,0 +1,33 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"example.com/notes-api/internal/core"
+)
+
+// NoteStats собирает статистику заметки из notes и notes_log одним запросом;
+// если заметки нет — core.ErrNotFound.
+func (r *NoteRepoPG) NoteStats(ctx context.Context, id int64) (*core.NoteStats, error) {
+	s := core.NoteStats{NoteID: id}
+	err := r.conn(ctx).QueryRowContext(ctx, `
+		SELECT n.version, n.view_count, n.last_viewed_at, n.created_at,
+		       count(l.id) FILTER (WHERE l.action = $2),
+		       max(l.created_at) FILTER (WHERE l.action = $2)
+		FROM notes n
+		LEFT JOIN notes_log l ON l.note_id = n.id
+		WHERE n.id = $1 AND `+visible+`
+		GROUP BY n.id
+	`, id, core.ActionUpdated).Scan(
+		&s.Version, &s.ViewCount, &s.LastViewedAt, &s.CreatedAt,
+		&s.Edits, &s.LastEditedAt,
+	)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	days := max(time.Since(s.CreatedAt).Hours()/24, 1)
+	s.EditsPerDay = float64(s.Edits) / days
+	return &s, nil
+}
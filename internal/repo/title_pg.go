@@ -0,0 +1,68 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"example.com/notes-api/internal/core"
+	"github.com/lib/pq"
+)
+
+// titleUniqueIndex — уникальный индекс заголовков из необязательной миграции
+// migrations/optional/unique_titles.sql. Без неё заголовки могут повторяться.
+const titleUniqueIndex = "idx_notes_title_unique"
+
+// pgUniqueViolation — SQLSTATE нарушения уникальности.
+const pgUniqueViolation = "23505"
+
+// titleTaken превращает нарушение titleUniqueIndex в *core.TitleTakenError
+// с ID заметки, уже занявшей title; остальные ошибки возвращает как есть.
+// Поиск идёт через пул, а не транзакцию вызывающего: та уже прервана ошибкой,
+// а заметка-владелец заголовка зафиксирована, иначе индекс ждал бы её.
+func (r *NoteRepoPG) titleTaken(ctx context.Context, err error, title string) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != pgUniqueViolation || pqErr.Constraint != titleUniqueIndex {
+		return err
+	}
+
+	var id int64
+	if lookupErr := r.db.QueryRowContext(ctx, `
+		SELECT id FROM notes
+		WHERE lower(title) = lower($1) AND deleted_at IS NULL
+		ORDER BY id
+		LIMIT 1
+	`, title).Scan(&id); lookupErr != nil {
+		return core.ErrTitleTaken
+	}
+	return &core.TitleTakenError{NoteID: id}
+}
+
+// likeEscaper экранирует спецсимволы LIKE в префиксе поиска.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// FindByTitle ищет заметки по заголовку без учёта регистра: точное совпадение
+// или (prefix) заголовки, начинающиеся с title. Точные совпадения идут первыми,
+// затем — по алфавиту.
+func (r *NoteRepoPG) FindByTitle(ctx context.Context, title string, prefix bool, limit int) ([]core.Note, error) {
+	cond := `lower(title) = lower($1)`
+	arg := title
+	if prefix {
+		cond = `lower(title) LIKE lower($1) || '%'`
+		arg = likeEscaper.Replace(title)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT `+noteColumns+`
+		FROM notes
+		WHERE `+cond+` AND `+visible+`
+		ORDER BY lower(title) = lower($2) DESC, lower(title), id
+		LIMIT $3
+	`, arg, title, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanNotes(rows)
+}
@@ -0,0 +1,28 @@
+package repo
+
+import (
+	"database/sql/driver"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// transientCodes — SQLSTATE ошибок, после которых тот же запрос может пройти:
+// конфликты транзакций, занятые блокировки, перезапуск или перегрузка сервера.
+var transientCodes = map[pq.ErrorCode]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+	"55P03": true, // lock_not_available
+	"53300": true, // too_many_connections
+	"57P01": true, // admin_shutdown
+	"57P03": true, // cannot_connect_now
+}
+
+// Transient сообщает, что ошибку БД имеет смысл повторить (retry.Policy.Retryable).
+func Transient(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && transientCodes[pqErr.Code]
+}
@@ -0,0 +1,121 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// txKey — ключ контекста, под которым лежит текущая транзакция.
+type txKey struct{}
+
+// TxManager выполняет несколько операций репозитория в одной транзакции:
+// методы NoteRepoPG, вызванные с контекстом из WithinTx, присоединяются к ней
+// вместо того, чтобы открывать собственную.
+type TxManager struct {
+	db *sql.DB
+}
+
+// NewTxManager создаёт менеджер транзакций поверх db — той же базы, что у репозитория.
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTx вызывает fn в транзакции и фиксирует её, если fn вернула nil;
+// при ошибке или панике транзакция откатывается. Вложенный вызов
+// присоединяется к внешней транзакции.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // откат если Commit не вызван
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// errNestedDryRun — пробный запуск нельзя вложить в транзакцию: откатить
+// только его изменения не получится.
+var errNestedDryRun = errors.New("dry run inside a transaction")
+
+// DryRun вызывает fn в транзакции и всегда откатывает её: fn видит результат
+// своих изменений, но в базе ничего не остаётся.
+func (m *TxManager) DryRun(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return errNestedDryRun
+	}
+
+	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	return fn(context.WithValue(ctx, txKey{}, tx))
+}
+
+// WithinTx выполняет операции репозитория из fn в одной транзакции, см. TxManager.WithinTx.
+func (r *NoteRepoPG) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.tx.WithinTx(ctx, fn)
+}
+
+// DryRun выполняет операции репозитория из fn без сохранения, см. TxManager.DryRun.
+func (r *NoteRepoPG) DryRun(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.tx.DryRun(ctx, fn)
+}
+
+// dbConn — общий интерфейс *sql.DB и *sql.Tx.
+type dbConn interface {
+	queryer
+	execer
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// conn возвращает транзакцию из ctx, а без неё — пул соединений.
+func (r *NoteRepoPG) conn(ctx context.Context) dbConn {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return r.db
+}
+
+// txScope — транзакция метода репозитория. Если метод вызван внутри
+// TxManager.WithinTx, это внешняя транзакция: Commit и Rollback ничего не
+// делают, итог решает WithinTx.
+type txScope struct {
+	*sql.Tx
+	own bool
+}
+
+// begin открывает транзакцию метода или присоединяется к транзакции из ctx.
+func (r *NoteRepoPG) begin(ctx context.Context, opts *sql.TxOptions) (txScope, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return txScope{Tx: tx}, nil
+	}
+	tx, err := r.db.BeginTx(ctx, opts)
+	if err != nil {
+		return txScope{}, err
+	}
+	return txScope{Tx: tx, own: true}, nil
+}
+
+func (t txScope) Commit() error {
+	if !t.own {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+func (t txScope) Rollback() error {
+	if !t.own {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"example.com/notes-api/internal/clock"
+	"example.com/notes-api/internal/core"
+)
+
+// usersEmailIndex — уникальный индекс email (migrations/0024_users.sql).
+const usersEmailIndex = "idx_users_email"
+
+// UserRepoPG — учётные записи пользователей в PostgreSQL.
+type UserRepoPG struct {
+	db    *sql.DB
+	clock clock.Clock
+}
+
+// NewUserRepoPG создаёт репозиторий пользователей поверх db.
+func NewUserRepoPG(db *sql.DB) *UserRepoPG {
+	return &UserRepoPG{db: db, clock: clock.System}
+}
+
+var _ core.UserRepository = (*UserRepoPG)(nil)
+
+const userColumns = `id, email, name, password_hash, created_at, updated_at`
+
+func scanUser(row interface{ Scan(...any) error }) (*core.User, error) {
+	var u core.User
+	if err := row.Scan(&u.ID, &u.Email, &u.Name, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, core.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &u, nil
+}
+
+// CreateUser сохраняет пользователя; email приводится к нижнему регистру.
+func (r *UserRepoPG) CreateUser(ctx context.Context, u core.User) (int64, error) {
+	var id int64
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO users (email, name, password_hash)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, strings.ToLower(u.Email), u.Name, u.PasswordHash).Scan(&id)
+	if err != nil {
+		return 0, emailTaken(err)
+	}
+	return id, nil
+}
+
+// GetUserByID возвращает пользователя по ID.
+func (r *UserRepoPG) GetUserByID(ctx context.Context, id int64) (*core.User, error) {
+	return scanUser(r.db.QueryRowContext(ctx,
+		`SELECT `+userColumns+` FROM users WHERE id = $1`, id))
+}
+
+// GetUserByEmail возвращает пользователя по email без учёта регистра.
+func (r *UserRepoPG) GetUserByEmail(ctx context.Context, email string) (*core.User, error) {
+	return scanUser(r.db.QueryRowContext(ctx,
+		`SELECT `+userColumns+` FROM users WHERE email = $1`, strings.ToLower(email)))
+}
+
+// UpdateUser меняет заданные поля пользователя и updated_at.
+func (r *UserRepoPG) UpdateUser(ctx context.Context, id int64, u core.UserUpdate) error {
+	var email *string
+	if u.Email != nil {
+		lower := strings.ToLower(*u.Email)
+		email = &lower
+	}
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE users
+		SET email         = COALESCE($2, email),
+		    name          = COALESCE($3, name),
+		    password_hash = COALESCE($4, password_hash),
+		    updated_at    = $5
+		WHERE id = $1
+	`, id, email, u.Name, u.PasswordHash, r.clock.Now())
+	if err != nil {
+		return emailTaken(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return core.ErrUserNotFound
+	}
+	return nil
+}
+
+// emailTaken превращает нарушение usersEmailIndex в core.ErrEmailTaken.
+func emailTaken(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation && pqErr.Constraint == usersEmailIndex {
+		return core.ErrEmailTaken
+	}
+	return err
+}
@@ -0,0 +1,45 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"example.com/notes-api/internal/core"
+	"github.com/lib/pq"
+)
+
+// AddViews увеличивает счётчики просмотров пачкой одним запросом.
+func (r *NoteRepoPG) AddViews(ctx context.Context, views map[int64]int64, at time.Time) error {
+	ids := make([]int64, 0, len(views))
+	counts := make([]int64, 0, len(views))
+	for id, n := range views {
+		ids = append(ids, id)
+		counts = append(counts, n)
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, `
+		UPDATE notes n
+		SET view_count = n.view_count + v.cnt,
+		    last_viewed_at = $3
+		FROM unnest($1::bigint[], $2::bigint[]) AS v(id, cnt)
+		WHERE n.id = v.id
+	`, pq.Array(ids), pq.Array(counts), at)
+	return err
+}
+
+// ListRecentlyViewed возвращает недавно просмотренные заметки.
+func (r *NoteRepoPG) ListRecentlyViewed(ctx context.Context, limit int) ([]core.Note, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT `+noteColumns+`
+		FROM notes
+		WHERE last_viewed_at IS NOT NULL AND `+visible+`
+		ORDER BY last_viewed_at DESC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanNotes(rows)
+}
@@ -0,0 +1,134 @@
+// Package retention применяет правила хранения данных по расписанию.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/notes-api/internal/core"
+)
+
+const (
+	batchSize   = 500
+	sampleLimit = 10
+)
+
+// Store выполняет правила хранения в хранилище.
+type Store interface {
+	ApplyRetention(ctx context.Context, rule core.RetentionRule, cutoff time.Time, limit int) (int64, error)
+	PreviewRetention(ctx context.Context, rule core.RetentionRule, cutoff time.Time, sample int) (int64, []int64, error)
+}
+
+// Engine применяет набор правил хранения.
+type Engine struct {
+	store Store
+	rules []core.RetentionRule
+}
+
+// NewEngine создаёт движок с заданными правилами.
+func NewEngine(store Store, rules []core.RetentionRule) *Engine {
+	return &Engine{store: store, rules: rules}
+}
+
+// Rules возвращает настроенные правила.
+func (e *Engine) Rules() []core.RetentionRule {
+	return e.rules
+}
+
+// Run применяет все правила пачками по batchSize строк и пишет итог в лог.
+func (e *Engine) Run(ctx context.Context) error {
+	results, err := e.Apply(ctx)
+	for _, res := range results {
+		if res.Affected > 0 {
+			log.Printf("Retention %s: %d rows affected", res.Rule.Kind, res.Affected)
+		}
+	}
+	return err
+}
+
+// Apply применяет все правила пачками по batchSize строк и возвращает число
+// затронутых строк по каждому правилу. При ошибке возвращает итоги уже
+// применённых правил.
+func (e *Engine) Apply(ctx context.Context) ([]core.RetentionResult, error) {
+	now := time.Now()
+	results := make([]core.RetentionResult, 0, len(e.rules))
+	for _, rule := range e.rules {
+		cutoff := now.Add(-rule.MaxAge)
+
+		var total int64
+		for {
+			n, err := e.store.ApplyRetention(ctx, rule, cutoff, batchSize)
+			if err != nil {
+				return results, fmt.Errorf("%s: %w", rule.Kind, err)
+			}
+			total += n
+			if n < batchSize {
+				break
+			}
+		}
+		results = append(results, core.RetentionResult{Rule: rule, Affected: total})
+	}
+	return results, nil
+}
+
+// Preview показывает, сколько строк затронет каждое правило, ничего не меняя.
+func (e *Engine) Preview(ctx context.Context) ([]core.RetentionPreview, error) {
+	now := time.Now()
+	previews := make([]core.RetentionPreview, 0, len(e.rules))
+	for _, rule := range e.rules {
+		cutoff := now.Add(-rule.MaxAge)
+		count, sample, err := e.store.PreviewRetention(ctx, rule, cutoff, sampleLimit)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", rule.Kind, err)
+		}
+		preview := core.RetentionPreview{
+			Rule:      rule,
+			Affected:  count,
+			SampleIDs: sample,
+		}
+		if rule.ByAge() {
+			preview.Cutoff = &cutoff
+		}
+		previews = append(previews, preview)
+	}
+	return previews, nil
+}
+
+// ParseRules разбирает строку вида "archive_notes:8760h,purge_log:2160h,trim_log:1000000".
+func ParseRules(spec string) ([]core.RetentionRule, error) {
+	var rules []core.RetentionRule
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		kind, age, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid rule %q, want kind:duration", part)
+		}
+		switch kind {
+		case core.RetentionArchiveNotes, core.RetentionPurgeLog, core.RetentionPurgeDeleted:
+		case core.RetentionTrimLog:
+			maxRows, err := strconv.ParseInt(age, 10, 64)
+			if err != nil || maxRows < 0 {
+				return nil, fmt.Errorf("invalid row count in rule %q", part)
+			}
+			rules = append(rules, core.RetentionRule{Kind: kind, MaxRows: maxRows})
+			continue
+		default:
+			return nil, fmt.Errorf("unknown rule kind %q", kind)
+		}
+
+		maxAge, err := time.ParseDuration(age)
+		if err != nil || maxAge <= 0 {
+			return nil, fmt.Errorf("invalid max age in rule %q", part)
+		}
+		rules = append(rules, core.RetentionRule{Kind: kind, MaxAge: maxAge})
+	}
+	return rules, nil
+}
@@ -0,0 +1,113 @@
+// Package retry повторяет исходящие вызовы (публикация в брокер, перестройка
+// индексов) с экспоненциальной задержкой и случайным разбросом, чтобы
+// временный сбой не ронял операцию, а повторы многих клиентов не совпадали
+// по времени.
+package retry
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"math/rand/v2"
+	"time"
+)
+
+// Policy — правила повтора одного вида вызовов.
+type Policy struct {
+	// Name — имя в метриках: retry_attempts, retry_retries, retry_failures.
+	Name string
+	// Attempts — сколько раз вызывать всего, включая первый; 0 — 3.
+	Attempts int
+	// Base — задержка перед первым повтором, удваивается с каждым; 0 — 100 мс.
+	Base time.Duration
+	// Max — предел задержки; 0 — 10 с.
+	Max time.Duration
+	// Retryable решает, стоит ли повторять после ошибки; nil — любая ошибка,
+	// кроме Permanent и отмены контекста.
+	Retryable func(error) bool
+}
+
+// Метрики по имени политики: сколько было вызовов, повторов и окончательных отказов.
+var (
+	attempts = expvar.NewMap("retry_attempts")
+	retries  = expvar.NewMap("retry_retries")
+	failures = expvar.NewMap("retry_failures")
+)
+
+type permanent struct{ err error }
+
+func (p permanent) Error() string { return p.err.Error() }
+func (p permanent) Unwrap() error { return p.err }
+
+// Permanent помечает ошибку как неисправимую повтором: Do вернёт её сразу.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanent{err}
+}
+
+// Do вызывает fn, пока она не вернёт nil, ошибка не окажется неповторяемой
+// или не кончатся попытки. Возвращает последнюю ошибку; ожидание между
+// попытками прерывается отменой ctx.
+func (p Policy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	n := p.Attempts
+	if n <= 0 {
+		n = 3
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		attempts.Add(p.Name, 1)
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if attempt == n || !p.retryable(ctx, err) {
+			break
+		}
+
+		retries.Add(p.Name, 1)
+		t := time.NewTimer(p.delay(attempt))
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			failures.Add(p.Name, 1)
+			return err
+		}
+	}
+	failures.Add(p.Name, 1)
+
+	var perm permanent
+	if errors.As(err, &perm) {
+		return perm.err
+	}
+	return err
+}
+
+func (p Policy) retryable(ctx context.Context, err error) bool {
+	var perm permanent
+	if errors.As(err, &perm) || ctx.Err() != nil {
+		return false
+	}
+	return p.Retryable == nil || p.Retryable(err)
+}
+
+// delay — задержка после попытки attempt: случайная в [d/2, d], где
+// d = Base·2^(attempt-1), но не больше Max.
+func (p Policy) delay(attempt int) time.Duration {
+	base, ceiling := p.Base, p.Max
+	if base <= 0 {
+		base = 100 * time.Millisecond
+	}
+	if ceiling <= 0 {
+		ceiling = 10 * time.Second
+	}
+
+	d := base
+	for i := 1; i < attempt && d < ceiling; i++ {
+		d *= 2
+	}
+	d = min(d, ceiling)
+	return d/2 + rand.N(d/2+1)
+}
@@ -0,0 +1,53 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDo(t *testing.T) {
+	errTemp := errors.New("temporary")
+	p := Policy{Name: "test", Attempts: 3, Base: time.Microsecond}
+
+	tests := []struct {
+		name  string
+		errs  []error // ошибки попыток по порядку; дальше — успех
+		calls int
+		want  error
+	}{
+		{"success", nil, 1, nil},
+		{"recovers", []error{errTemp, errTemp}, 3, nil},
+		{"gives up", []error{errTemp, errTemp, errTemp, errTemp}, 3, errTemp},
+		{"permanent", []error{Permanent(errTemp)}, 1, errTemp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := p.Do(context.Background(), func(ctx context.Context) error {
+				calls++
+				if calls <= len(tt.errs) {
+					return tt.errs[calls-1]
+				}
+				return nil
+			})
+			if err != tt.want {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+			if calls != tt.calls {
+				t.Errorf("calls = %d, want %d", calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestDelay(t *testing.T) {
+	p := Policy{Base: 100 * time.Millisecond, Max: time.Second}
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second} {
+		if d := p.delay(attempt); d < want/2 || d > want {
+			t.Errorf("delay(%d) = %s, want within [%s, %s]", attempt, d, want/2, want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Package slug строит человекочитаемые идентификаторы для URL из заголовков.
+package slug
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxLen — максимальная длина slug без числового суффикса.
+const MaxLen = 80
+
+// Fallback используется, если в заголовке нет ни одной латинской буквы или цифры.
+const Fallback = "note"
+
+// translit — транслитерация кириллицы (упрощённый ГОСТ 7.79-2000, система Б).
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+}
+
+// Make строит slug: транслитерация, нижний регистр, всё кроме [a-z0-9] — дефисы.
+func Make(title string) string {
+	var b strings.Builder
+	dash := false
+
+	for _, r := range strings.ToLower(title) {
+		var part string
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			part = string(r)
+		case translit[r] != "":
+			part = translit[r]
+		case r == 'ъ' || r == 'ь' || r == '\'' || unicode.Is(unicode.Mn, r):
+			continue
+		default:
+			dash = b.Len() > 0
+			continue
+		}
+
+		if dash {
+			b.WriteByte('-')
+			dash = false
+		}
+		if b.Len()+len(part) > MaxLen {
+			break
+		}
+		b.WriteString(part)
+	}
+
+	if b.Len() == 0 {
+		return Fallback
+	}
+	return b.String()
+}
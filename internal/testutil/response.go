@@ -0,0 +1,95 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// update перезаписывает golden-файлы: go test ./... -update
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// Response — прочитанный ответ сервера.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// AssertStatus проверяет код ответа.
+func (r *Response) AssertStatus(t testing.TB, want int) {
+	t.Helper()
+	if r.Status != want {
+		t.Fatalf("status = %d, want %d; body: %s", r.Status, want, r.Body)
+	}
+}
+
+// Decode разбирает тело ответа как JSON в v.
+func (r *Response) Decode(t testing.TB, v any) {
+	t.Helper()
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		t.Fatalf("decode response: %v; body: %s", err, r.Body)
+	}
+}
+
+// AssertGolden сравнивает JSON-тело с testdata/<name>.golden.json.
+// Поля из ignore (например, request_id) перед сравнением удаляются на любой
+// глубине; JSON нормализуется, так что порядок ключей и отступы не важны.
+func (r *Response) AssertGolden(t testing.TB, name string, ignore ...string) {
+	t.Helper()
+	got := normalize(t, r.Body, ignore)
+	path := filepath.Join("testdata", name+".golden.json")
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("response does not match %s\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func normalize(t testing.TB, body []byte, ignore []string) []byte {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		t.Fatalf("response is not JSON: %v; body: %s", err, body)
+	}
+	drop(v, ignore)
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(out, '\n')
+}
+
+// drop удаляет ключи ignore из всех объектов в v.
+func drop(v any, ignore []string) {
+	switch v := v.(type) {
+	case map[string]any:
+		for _, key := range ignore {
+			delete(v, key)
+		}
+		for _, child := range v {
+			drop(child, ignore)
+		}
+	case []any:
+		for _, child := range v {
+			drop(child, ignore)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Package testutil — набор для тестов HTTP-обработчиков без базы данных:
+// тестовый сервер с настоящим роутером (с хранилищем в памяти или без него),
+// построитель запросов и проверки ответа по golden-файлам. Сервер с хранилищем
+// в памяти живёт по часам Clock, поэтому метки времени в golden-файлах не меняются.
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"example.com/notes-api/internal/changes"
+	"example.com/notes-api/internal/clock"
+	httpx "example.com/notes-api/internal/http"
+	"example.com/notes-api/internal/http/handlers"
+	"example.com/notes-api/internal/repo"
+)
+
+// Now — время на часах Clock.
+var Now = time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+// Clock — остановленные часы сервера NewMemoryServer: всегда показывают Now.
+var Clock clock.Clock = clock.Func(func() time.Time { return Now })
+
+// Server — httptest-сервер с роутером API.
+type Server struct {
+	*httptest.Server
+	// Handler — обработчики сервера; поля можно менять до первого запроса.
+	Handler *handlers.Handler
+}
+
+// NewServer поднимает роутер для h с настройками cfg; сервер закрывается
+// по окончании теста.
+func NewServer(t testing.TB, h *handlers.Handler, cfg httpx.Config) *Server {
+	t.Helper()
+	s := httptest.NewServer(httpx.NewRouter(h, cfg))
+	t.Cleanup(s.Close)
+	return &Server{Server: s, Handler: h}
+}
+
+// NewMemoryServer поднимает сервер на хранилищах в памяти: заметки,
+// учётные записи и лента изменений. Каждый вызов — пустая база; репозитории
+// и обработчики идут по часам Clock.
+func NewMemoryServer(t testing.TB, cfg httpx.Config) *Server {
+	t.Helper()
+	return NewServer(t, &handlers.Handler{
+		Repo:    repo.NewNoteRepoMemory(repo.WithMemoryClock(Clock)),
+		Users:   repo.NewUserRepoMemory(repo.WithMemoryClock(Clock)),
+		Changes: changes.NewFeed(100),
+		Clock:   Clock,
+	}, cfg)
+}
+
+// Request — построитель запроса к Server.
+type Request struct {
+	server *Server
+	method string
+	path   string
+	body   []byte
+	header http.Header
+}
+
+// Request начинает запрос method к path (путь от корня, например /api/v1/notes).
+func (s *Server) Request(method, path string) *Request {
+	return &Request{server: s, method: method, path: path, header: http.Header{}}
+}
+
+// JSON задаёт тело запроса — v, закодированное в JSON.
+func (r *Request) JSON(v any) *Request {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	r.body = b
+	r.header.Set("Content-Type", "application/json")
+	return r
+}
+
+// Body задаёт тело запроса как есть (например, заведомо битый JSON).
+func (r *Request) Body(s string) *Request {
+	r.body = []byte(s)
+	r.header.Set("Content-Type", "application/json")
+	return r
+}
+
+// Header добавляет заголовок запроса.
+func (r *Request) Header(key, value string) *Request {
+	r.header.Set(key, value)
+	return r
+}
+
+// Do отправляет запрос и читает ответ целиком.
+func (r *Request) Do(t testing.TB) *Response {
+	t.Helper()
+	req, err := http.NewRequest(r.method, r.server.URL+r.path, bytes.NewReader(r.body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header = r.header
+
+	resp, err := r.server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Response{Status: resp.StatusCode, Header: resp.Header, Body: body}
+}
@@ -0,0 +1,39 @@
+// Package textnorm приводит заголовки и текст заметок к единому виду перед
+// записью: одинаковые для человека строки от разных клиентов и платформ
+// должны совпадать байт в байт, иначе расходятся поиск, проверка
+// уникальности заголовков и диффы.
+package textnorm
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Title нормализует заголовок: NFC, управляющие символы и переводы строк
+// становятся пробелами, пробелы по краям убираются, а подряд идущие
+// внутри схлопываются в один.
+func Title(s string) string {
+	s = norm.NFC.String(s)
+	return strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r)
+	}), " ")
+}
+
+// Content нормализует текст заметки: NFC, переводы строк — \n, управляющие
+// символы, кроме \n и \t, удаляются, пробельные символы в конце текста
+// обрезаются. Пробелы в концах строк остаются: в Markdown два пробела —
+// перенос строки.
+func Content(s string) string {
+	s = norm.NFC.String(s)
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	s = strings.Map(func(r rune) rune {
+		if r != '\n' && r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.TrimRightFunc(s, unicode.IsSpace)
+}
@@ -0,0 +1,30 @@
+package textnorm
+
+import "testing"
+
+func TestTitle(t *testing.T) {
+	tests := map[string]string{
+		"  План  на\tнеделю \n": "План на неделю",
+		"Cafe\u0301":            "Caf\u00e9",
+		"a\u0000b":              "a b",
+		"\U0001F469\u200d\U0001F4BB работа": "\U0001F469\u200d\U0001F4BB работа",
+	}
+	for in, want := range tests {
+		if got := Title(in); got != want {
+			t.Errorf("Title(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestContent(t *testing.T) {
+	tests := map[string]string{
+		"line  \r\nnext\rlast\n\n  ": "line  \nnext\nlast",
+		"e\u0301\u0007\tx":           "\u00e9\tx",
+		"\u0085keep":                 "keep",
+	}
+	for in, want := range tests {
+		if got := Content(in); got != want {
+			t.Errorf("Content(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
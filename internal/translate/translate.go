@@ -0,0 +1,79 @@
+// Package translate переводит текст заметок внешним сервисом.
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Translator переводит texts с языка from (код ISO 639-1 или "auto") на to;
+// переводы возвращаются в том же порядке.
+type Translator interface {
+	Translate(ctx context.Context, texts []string, from, to string) ([]string, error)
+}
+
+// LibreTranslate — Translator поверх HTTP API LibreTranslate (обычно self-hosted).
+type LibreTranslate struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewLibreTranslate создаёт клиент сервера LibreTranslate по адресу baseURL,
+// например "http://libretranslate:5000"; apiKey пустой, если сервер без ключей.
+func NewLibreTranslate(baseURL, apiKey string) *LibreTranslate {
+	return &LibreTranslate{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type ltRequest struct {
+	Q      []string `json:"q"`
+	Source string   `json:"source"`
+	Target string   `json:"target"`
+	Format string   `json:"format"`
+	APIKey string   `json:"api_key,omitempty"`
+}
+
+type ltResponse struct {
+	TranslatedText []string `json:"translatedText"`
+	Error          string   `json:"error"`
+}
+
+// Translate отправляет texts в /translate одним запросом.
+func (lt *LibreTranslate) Translate(ctx context.Context, texts []string, from, to string) ([]string, error) {
+	body, err := json.Marshal(ltRequest{Q: texts, Source: from, Target: to, Format: "text", APIKey: lt.apiKey})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, lt.baseURL+"/translate", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := lt.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var lr ltResponse
+	if err := json.NewDecoder(resp.Body).Decode(&lr); err != nil {
+		return nil, fmt.Errorf("libretranslate: status %d: decode response: %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("libretranslate: status %d: %s", resp.StatusCode, lr.Error)
+	}
+	if len(lr.TranslatedText) != len(texts) {
+		return nil, fmt.Errorf("libretranslate: got %d translations for %d texts", len(lr.TranslatedText), len(texts))
+	}
+	return lr.TranslatedText, nil
+}
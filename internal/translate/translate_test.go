@@ -0,0 +1,42 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLibreTranslate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ltRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || r.URL.Path != "/translate" {
+			http.Error(w, `{"error":"bad request"}`, http.StatusBadRequest)
+			return
+		}
+		if req.Target != "en" || req.APIKey != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":"Invalid API key"}`))
+			return
+		}
+		out := make([]string, len(req.Q))
+		for i, q := range req.Q {
+			out[i] = "en:" + q
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"translatedText": out})
+	}))
+	defer srv.Close()
+
+	got, err := NewLibreTranslate(srv.URL, "secret").Translate(context.Background(), []string{"Покупки", "Молоко"}, "ru", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "en:Покупки" || got[1] != "en:Молоко" {
+		t.Errorf("translations = %q", got)
+	}
+
+	if _, err := NewLibreTranslate(srv.URL, "").Translate(context.Background(), []string{"x"}, "auto", "en"); err == nil {
+		t.Error("want error for rejected API key")
+	}
+}
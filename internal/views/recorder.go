@@ -0,0 +1,96 @@
+// Package views асинхронно учитывает просмотры заметок.
+package views
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Store сохраняет накопленные просмотры одной пачкой.
+type Store interface {
+	AddViews(ctx context.Context, views map[int64]int64, at time.Time) error
+}
+
+type view struct {
+	noteID int64
+	viewer string
+}
+
+// Recorder копит просмотры в памяти и периодически сбрасывает их в Store.
+// Повторный просмотр той же заметки тем же зрителем в пределах window не учитывается.
+type Recorder struct {
+	store    Store
+	window   time.Duration
+	interval time.Duration
+	ch       chan view
+
+	seen    map[view]time.Time
+	pending map[int64]int64
+}
+
+// NewRecorder создаёт Recorder с окном дедупликации window.
+func NewRecorder(store Store, window time.Duration) *Recorder {
+	return &Recorder{
+		store:    store,
+		window:   window,
+		interval: 5 * time.Second,
+		ch:       make(chan view, 1024),
+		seen:     make(map[view]time.Time),
+		pending:  make(map[int64]int64),
+	}
+}
+
+// Record ставит просмотр в очередь, не блокируя запрос.
+// При переполненной очереди просмотр отбрасывается.
+func (r *Recorder) Record(noteID int64, viewer string) {
+	select {
+	case r.ch <- view{noteID: noteID, viewer: viewer}:
+	default:
+	}
+}
+
+// Run обрабатывает очередь до отмены ctx, после чего сбрасывает остаток.
+func (r *Recorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case v := <-r.ch:
+			r.add(v, time.Now())
+		case <-ticker.C:
+			r.flush(ctx)
+		case <-ctx.Done():
+			r.flush(context.Background())
+			return
+		}
+	}
+}
+
+func (r *Recorder) add(v view, now time.Time) {
+	if last, ok := r.seen[v]; ok && now.Sub(last) < r.window {
+		return
+	}
+	r.seen[v] = now
+	r.pending[v.noteID]++
+}
+
+func (r *Recorder) flush(ctx context.Context) {
+	now := time.Now()
+	for v, at := range r.seen {
+		if now.Sub(at) >= r.window {
+			delete(r.seen, v)
+		}
+	}
+
+	if len(r.pending) == 0 {
+		return
+	}
+
+	if err := r.store.AddViews(ctx, r.pending, now); err != nil {
+		log.Println("Failed to save note views:", err)
+		return
+	}
+	r.pending = make(map[int64]int64)
+}
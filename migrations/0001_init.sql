@@ -0,0 +1,23 @@
+-- Базовая схема: заметки и журнал действий.
+CREATE TABLE IF NOT EXISTS notes (
+    id         BIGSERIAL PRIMARY KEY,
+    title      TEXT        NOT NULL,
+    content    TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS notes_log (
+    id         BIGSERIAL PRIMARY KEY,
+    note_id    BIGINT      NOT NULL,
+    action     TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Keyset-пагинация по (created_at, id).
+CREATE INDEX IF NOT EXISTS idx_notes_created_id
+    ON notes (created_at DESC, id DESC);
+
+-- Полнотекстовый поиск по заголовку.
+CREATE INDEX IF NOT EXISTS idx_notes_title_fts
+    ON notes USING gin (to_tsvector('simple', title));
@@ -0,0 +1,2 @@
+-- Версия заметки для оптимистичной блокировки при синхронизации.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
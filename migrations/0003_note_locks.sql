@@ -0,0 +1,6 @@
+-- Блокировки редактирования заметок.
+CREATE TABLE IF NOT EXISTS note_locks (
+    note_id    BIGINT PRIMARY KEY REFERENCES notes (id) ON DELETE CASCADE,
+    owner      TEXT        NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,7 @@
+-- Счётчик просмотров и время последнего просмотра.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS view_count     BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS last_viewed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_notes_last_viewed
+    ON notes (last_viewed_at DESC)
+    WHERE last_viewed_at IS NOT NULL;
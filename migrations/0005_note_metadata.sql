@@ -0,0 +1,6 @@
+-- Произвольные метаданные интеграций.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}';
+
+-- Индекс под запросы вида metadata @> '{"key": "value"}'.
+CREATE INDEX IF NOT EXISTS idx_notes_metadata
+    ON notes USING gin (metadata jsonb_path_ops);
@@ -0,0 +1,5 @@
+-- Цвет карточки и иконка заметки.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS color TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS icon  TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_notes_color ON notes (color);
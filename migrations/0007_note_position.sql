@@ -0,0 +1,15 @@
+-- Ручной порядок заметок (дробный ранг; меньше — выше в списке).
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS position DOUBLE PRECISION;
+
+UPDATE notes n
+SET position = o.rn
+FROM (
+    SELECT id, row_number() OVER (ORDER BY created_at DESC, id DESC) AS rn
+    FROM notes
+) o
+WHERE n.id = o.id AND n.position IS NULL;
+
+ALTER TABLE notes ALTER COLUMN position SET DEFAULT 0;
+ALTER TABLE notes ALTER COLUMN position SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notes_position ON notes (position, id);
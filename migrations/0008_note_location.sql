@@ -0,0 +1,16 @@
+-- Геометки заметок и поиск поблизости (earthdistance).
+CREATE EXTENSION IF NOT EXISTS cube;
+CREATE EXTENSION IF NOT EXISTS earthdistance;
+
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS latitude  DOUBLE PRECISION;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+
+ALTER TABLE notes DROP CONSTRAINT IF EXISTS notes_location_check;
+ALTER TABLE notes ADD CONSTRAINT notes_location_check CHECK (
+    (latitude IS NULL AND longitude IS NULL)
+    OR (latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notes_location
+    ON notes USING gist (ll_to_earth(latitude, longitude))
+    WHERE latitude IS NOT NULL;
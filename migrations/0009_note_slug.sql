@@ -0,0 +1,9 @@
+-- Человекочитаемые slug для ссылок. Старые заметки получают note-<id>,
+-- сгенерировать slug из заголовка можно через PATCH {"regenerate_slug": true}.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS slug TEXT;
+
+UPDATE notes SET slug = 'note-' || id WHERE slug IS NULL;
+
+ALTER TABLE notes ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notes_slug ON notes (slug);
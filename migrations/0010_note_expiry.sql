@@ -0,0 +1,6 @@
+-- Срок жизни заметки: истёкшие скрываются сразу и удаляются фоновой задачей.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_notes_expires_at
+    ON notes (expires_at)
+    WHERE expires_at IS NOT NULL;
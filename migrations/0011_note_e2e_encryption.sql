@@ -0,0 +1,17 @@
+-- Сквозное шифрование на клиенте: сервер хранит шифртекст как непрозрачные байты.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS encrypted  BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS ciphertext BYTEA;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS nonce      BYTEA;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS key_id     TEXT;
+
+ALTER TABLE notes DROP CONSTRAINT IF EXISTS notes_encrypted_check;
+ALTER TABLE notes ADD CONSTRAINT notes_encrypted_check CHECK (
+    NOT encrypted
+    OR (ciphertext IS NOT NULL AND nonce IS NOT NULL AND key_id IS NOT NULL AND content = '')
+);
+
+-- Зашифрованные заметки не индексируются полнотекстовым поиском.
+DROP INDEX IF EXISTS idx_notes_title_fts;
+CREATE INDEX idx_notes_title_fts
+    ON notes USING gin (to_tsvector('simple', title))
+    WHERE NOT encrypted;
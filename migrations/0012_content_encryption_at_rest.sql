@@ -0,0 +1,2 @@
+-- ID ключа, которым зашифрован content (NULL — открытый текст).
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS content_key_id TEXT;
@@ -0,0 +1,9 @@
+-- Архив заметок (в том числе по правилам хранения).
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_notes_archived_at
+    ON notes (archived_at)
+    WHERE archived_at IS NOT NULL;
+
+-- Для правил очистки notes_log по возрасту.
+CREATE INDEX IF NOT EXISTS idx_notes_log_created_at ON notes_log (created_at);
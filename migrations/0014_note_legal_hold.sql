@@ -0,0 +1,6 @@
+-- Юридическое удержание: такие заметки не удаляются ни по сроку жизни, ни правилами хранения.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS legal_hold_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_notes_legal_hold
+    ON notes (id)
+    WHERE legal_hold_at IS NOT NULL;
@@ -0,0 +1,6 @@
+-- Мягкое удаление: DELETE /notes/{id} только помечает заметку, администратор может её восстановить.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_notes_deleted_at
+    ON notes (deleted_at)
+    WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,6 @@
+-- Ежедневные заметки: не больше одной неудалённой заметки на дату.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS daily_date DATE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notes_daily_date
+    ON notes (daily_date)
+    WHERE daily_date IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,24 @@
+-- Язык заметки (ISO 639-1, пустая строка — не определён). Заполняется
+-- приложением при записи; для старых заметок — командой reindex.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS lang TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_notes_lang ON notes (lang);
+
+-- Конфигурация полнотекстового поиска для языка заметки. Поиск должен
+-- строить tsvector так же, как индекс: to_tsvector(note_fts_config(lang), title).
+CREATE OR REPLACE FUNCTION note_fts_config(lang TEXT) RETURNS regconfig
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+    SELECT CASE lang
+        WHEN 'ru' THEN 'russian'
+        WHEN 'en' THEN 'english'
+        WHEN 'de' THEN 'german'
+        WHEN 'fr' THEN 'french'
+        WHEN 'es' THEN 'spanish'
+        ELSE 'simple'
+    END::regconfig
+$$;
+
+DROP INDEX IF EXISTS idx_notes_title_fts;
+CREATE INDEX idx_notes_title_fts
+    ON notes USING gin (to_tsvector(note_fts_config(lang), title))
+    WHERE NOT encrypted;
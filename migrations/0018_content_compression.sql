@@ -0,0 +1,3 @@
+-- content длиннее порога CONTENT_COMPRESS_THRESHOLD хранится сжатым
+-- (base64 от gzip, до шифрования). Старые заметки сжимает команда compress.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS content_compressed BOOLEAN NOT NULL DEFAULT false;
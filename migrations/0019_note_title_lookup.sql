@@ -0,0 +1,5 @@
+-- Поиск заметки по заголовку (GET /notes/by-title): точное совпадение и
+-- префикс без учёта регистра. text_pattern_ops нужен для LIKE 'префикс%'.
+CREATE INDEX IF NOT EXISTS idx_notes_title_lower
+    ON notes (lower(title) text_pattern_ops)
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,15 @@
+-- Потолки размеров заметки (core.TitleCeiling, core.ContentCeiling) на случай
+-- записи в обход API. content хранится сжатым и/или зашифрованным в base64,
+-- поэтому его потолок вдвое выше: так в него помещается любой допустимый текст.
+-- NOT VALID: проверяются только новые и изменённые строки, существующие не
+-- сканируются. Проверить их позже: ALTER TABLE notes VALIDATE CONSTRAINT ...
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'notes_title_length') THEN
+        ALTER TABLE notes
+            ADD CONSTRAINT notes_title_length CHECK (char_length(title) <= 1000) NOT VALID,
+            ADD CONSTRAINT notes_content_size CHECK (octet_length(content) <= 16777216) NOT VALID,
+            ADD CONSTRAINT notes_ciphertext_size CHECK (octet_length(ciphertext) <= 8388608) NOT VALID;
+    END IF;
+END
+$$;
@@ -0,0 +1,5 @@
+-- Данные карточки для списков: вид содержимого (plain, markdown, checklist)
+-- и превью без разметки. Считаются при записи; у старых заметок пустые и
+-- вычисляются при чтении. При шифровании content в БД превью не хранится.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS content_kind TEXT NOT NULL DEFAULT '';
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS preview TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,9 @@
+-- Объявления: администратор закрепляет заметку над списком у всех, с
+-- необязательным окном показа [announce_from, announce_until).
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS announced_at TIMESTAMPTZ;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS announce_from TIMESTAMPTZ;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS announce_until TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_notes_announced
+    ON notes (announced_at)
+    WHERE announced_at IS NOT NULL;
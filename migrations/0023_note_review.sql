@@ -0,0 +1,11 @@
+-- Согласование заметок: draft → in_review → published. Пустое состояние —
+-- заметка вне процесса согласования. reviewer — имя назначенного
+-- рецензента (как владелец блокировки), reviewed_at — время решения.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS review_state TEXT NOT NULL DEFAULT ''
+    CHECK (review_state IN ('', 'draft', 'in_review', 'published'));
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS reviewer TEXT;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_notes_review_state
+    ON notes (review_state)
+    WHERE review_state <> '';
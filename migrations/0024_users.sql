@@ -0,0 +1,13 @@
+-- Учётные записи пользователей. email хранится в нижнем регистре и уникален;
+-- password_hash — bcrypt, открытый пароль не сохраняется.
+CREATE TABLE IF NOT EXISTS users (
+    id            BIGSERIAL PRIMARY KEY,
+    email         TEXT        NOT NULL,
+    name          TEXT        NOT NULL DEFAULT '',
+    password_hash TEXT        NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email
+    ON users (email);
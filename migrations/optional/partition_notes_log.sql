@@ -0,0 +1,47 @@
+-- Необязательная миграция для больших установок: notes_log секционируется
+-- по месяцам created_at. Не входит в make migrate, применяется вручную один раз:
+--   psql "$DATABASE_URL" -f migrations/optional/partition_notes_log.sql
+-- Секции на следующие месяцы создаёт фоновая задача log-partitions.
+-- Таблица блокируется на время копирования — запускать в окно обслуживания.
+BEGIN;
+
+ALTER SEQUENCE notes_log_id_seq OWNED BY NONE;
+ALTER TABLE notes_log RENAME TO notes_log_old;
+ALTER INDEX IF EXISTS idx_notes_log_created_at RENAME TO idx_notes_log_old_created_at;
+
+CREATE TABLE notes_log (
+    id         BIGINT      NOT NULL DEFAULT nextval('notes_log_id_seq'),
+    note_id    BIGINT      NOT NULL,
+    action     TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+
+CREATE INDEX idx_notes_log_created_at ON notes_log (created_at);
+
+-- Строки вне созданных секций попадают сюда, вставка не падает.
+CREATE TABLE notes_log_default PARTITION OF notes_log DEFAULT;
+
+DO $$
+DECLARE
+    m TIMESTAMPTZ;
+BEGIN
+    FOR m IN
+        SELECT generate_series(
+            date_trunc('month', COALESCE((SELECT MIN(created_at) FROM notes_log_old), now())),
+            date_trunc('month', now()) + interval '3 months',
+            interval '1 month')
+    LOOP
+        EXECUTE format(
+            'CREATE TABLE %I PARTITION OF notes_log FOR VALUES FROM (%L) TO (%L)',
+            'notes_log_' || to_char(m, 'YYYY_MM'), m, m + interval '1 month');
+    END LOOP;
+END $$;
+
+INSERT INTO notes_log (id, note_id, action, created_at)
+SELECT id, note_id, action, created_at FROM notes_log_old;
+
+DROP TABLE notes_log_old;
+ALTER SEQUENCE notes_log_id_seq OWNED BY notes_log.id;
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- Необязательная миграция для вики-сценариев, где заголовок служит ключом:
+-- заголовки неудалённых заметок уникальны без учёта регистра. Создание или
+-- переименование в занятый заголовок получает 409 с ID владельца заголовка.
+-- Не входит в make migrate, применяется вручную:
+--   psql "$DATABASE_URL" -f migrations/optional/unique_titles.sql
+-- Если повторы уже есть, индекс не создастся — их нужно переименовать заранее:
+--   SELECT lower(title), array_agg(id) FROM notes WHERE deleted_at IS NULL
+--   GROUP BY 1 HAVING count(*) > 1;
+-- Отключение: DROP INDEX idx_notes_title_unique;
+CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS idx_notes_title_unique
+    ON notes (lower(title))
+    WHERE deleted_at IS NULL;